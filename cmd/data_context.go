@@ -5,42 +5,108 @@ import (
 	"github.com/openweb3/web3go"
 	"github.com/scroll-tech/rpc-gateway/store"
 	"github.com/scroll-tech/rpc-gateway/store/mysql"
+	"github.com/scroll-tech/rpc-gateway/store/postgres"
 	"github.com/scroll-tech/rpc-gateway/store/redis"
+	"github.com/scroll-tech/rpc-gateway/store/sqlite"
+	"github.com/scroll-tech/rpc-gateway/util/pubsub"
+	"github.com/scroll-tech/rpc-gateway/util/rate"
 	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/sirupsen/logrus"
 )
 
 // storeContext context to hold store instances
 type storeContext struct {
-	cfxDB    *mysql.MysqlStore
-	ethDB    *mysql.MysqlStore
-	cfxCache *redis.RedisStore
+	cfxDB    store.DBStore
+	ethDB    store.DBStore
+	cfxCache store.CacheStore
+
+	// broker to dispatch synced chain data from sync to rpc service
+	broker pubsub.Broker
 }
 
 func mustInitStoreContext() storeContext {
+	if embeddedModeEnabled {
+		return mustInitEmbeddedStoreContext()
+	}
+
 	var ctx storeContext
 
 	// prepare core space db store
-	if config := mysql.MustNewConfigFromViper(); config.Enabled {
-		ctx.cfxDB = config.MustOpenOrCreate(mysql.StoreOption{
-			Disabler: store.StoreConfig(),
-		})
-	}
+	ctx.cfxDB = mustOpenDBStore(
+		mysql.MustNewConfigFromViper(), postgres.MustNewConfigFromViper(), store.StoreConfig(),
+	)
 
 	// prepare evm space db store
-	if ethConfig := mysql.MustNewEthStoreConfigFromViper(); ethConfig.Enabled {
-		ctx.ethDB = ethConfig.MustOpenOrCreate(mysql.StoreOption{
-			Disabler: store.EthStoreConfig(),
-		})
-	}
+	ctx.ethDB = mustOpenDBStore(
+		mysql.MustNewEthStoreConfigFromViper(), postgres.MustNewEthStoreConfigFromViper(), store.EthStoreConfig(),
+	)
 
 	// prepare redis store
 	if redis, ok := redis.MustNewRedisStoreFromViper(store.StoreConfig()); ok {
 		ctx.cfxCache = redis
 	}
 
+	// prepare pub/sub broker
+	if broker, ok := pubsub.MustNewBrokerFromViper(); ok {
+		ctx.broker = broker
+	}
+
 	return ctx
 }
 
+// mustInitEmbeddedStoreContext initializes store context for embedded mode, which persists evm
+// space chain data into SQLite and dispatches synced data via in-process memory broker.
+//
+// Note, core space database store is not supported in embedded mode, but core space cache store
+// could be persisted into another SQLite database instead of redis if enabled.
+func mustInitEmbeddedStoreContext() storeContext {
+	var ctx storeContext
+
+	ctx.ethDB = sqlite.MustNewEthStoreConfigFromViper().MustOpenOrCreate(
+		sqlite.StoreOption{Disabler: store.EthStoreConfig()},
+	)
+
+	if config, ok := sqlite.MustNewCacheConfigFromViper(); ok {
+		ctx.cfxCache = config.MustOpenOrCreate(sqlite.StoreOption{Disabler: store.StoreConfig()})
+	}
+
+	// sync and RPC services are always running within the same process
+	ctx.broker = pubsub.NewMemoryBroker()
+
+	return ctx
+}
+
+// mustOpenDBStore opens db store with the enabled database backend, which could be either
+// MySQL or PostgreSQL, or returns nil if none enabled.
+func mustOpenDBStore(
+	mysqlConfig *mysql.Config, pgConfig *postgres.Config, disabler store.StoreDisabler,
+) store.DBStore {
+	if mysqlConfig.Enabled && pgConfig.Enabled {
+		logrus.Fatal("Only one of MySQL or PostgreSQL database backend can be enabled")
+	}
+
+	if mysqlConfig.Enabled {
+		return mysqlConfig.MustOpenOrCreate(mysql.StoreOption{Disabler: disabler})
+	}
+
+	if pgConfig.Enabled {
+		return pgConfig.MustOpenOrCreate(postgres.StoreOption{Disabler: disabler})
+	}
+
+	return nil
+}
+
+// ethRateLimitKeysetLoader returns the loader of evm space rate limit keyset, which is loaded from
+// core space store as before, or evm space store if core space store not available, e.g. in
+// embedded mode.
+func (ctx *storeContext) ethRateLimitKeysetLoader() rate.KeysetLoader {
+	if ctx.cfxDB != nil {
+		return ctx.cfxDB.LoadRateLimitKeyset
+	}
+
+	return ctx.ethDB.LoadRateLimitKeyset
+}
+
 func (ctx *storeContext) Close() {
 	if ctx.cfxDB != nil {
 		ctx.cfxDB.Close()
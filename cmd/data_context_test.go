@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
+	"github.com/scroll-tech/rpc-gateway/util/rate"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestEmbeddedStoreContext(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "confura.db")
+	viper.Set("ethstore.sqlite.path", dbPath)
+	defer viper.Set("ethstore.sqlite.path", nil)
+
+	storeCtx := mustInitEmbeddedStoreContext()
+	defer storeCtx.Close()
+
+	// core space stores are not supported in embedded mode
+	assert.Nil(t, storeCtx.cfxDB)
+	assert.Nil(t, storeCtx.cfxCache)
+	require.NotNil(t, storeCtx.ethDB)
+	assert.NotNil(t, storeCtx.broker)
+
+	// rate limit keyset is loaded from evm space store instead
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&gormstore.RateLimit{SID: 1, LimitKey: "key1"}).Error)
+
+	keyInfos, err := storeCtx.ethRateLimitKeysetLoader()(&rate.KeysetFilter{KeySet: []string{"key1"}})
+	require.NoError(t, err)
+	if assert.Equal(t, 1, len(keyInfos)) {
+		assert.Equal(t, "key1", keyInfos[0].Key)
+	}
+}
+
+func TestEmbeddedCacheStoreContext(t *testing.T) {
+	viper.Set("ethstore.sqlite.path", filepath.Join(t.TempDir(), "confura.db"))
+	defer viper.Set("ethstore.sqlite.path", nil)
+
+	viper.Set("store.sqlite.enabled", true)
+	defer viper.Set("store.sqlite.enabled", nil)
+
+	viper.Set("store.sqlite.path", filepath.Join(t.TempDir(), "confura-cache.db"))
+	defer viper.Set("store.sqlite.path", nil)
+
+	storeCtx := mustInitEmbeddedStoreContext()
+	defer storeCtx.Close()
+
+	// core space cache store persisted into another database instead of redis
+	assert.Nil(t, storeCtx.cfxDB)
+	require.NotNil(t, storeCtx.cfxCache)
+	require.NotNil(t, storeCtx.ethDB)
+
+	require.NoError(t, storeCtx.cfxCache.StoreConfig("foo", "bar"))
+
+	confs, err := storeCtx.ethDB.LoadConfig("foo")
+	require.NoError(t, err)
+	assert.Empty(t, confs)
+
+	confs, err = storeCtx.cfxCache.LoadConfig("foo")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, confs)
+}
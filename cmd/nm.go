@@ -6,6 +6,10 @@ import (
 
 	"github.com/scroll-tech/rpc-gateway/cmd/util"
 	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/mysql"
+	"github.com/scroll-tech/rpc-gateway/store/redis"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
 	"github.com/scroll-tech/rpc-gateway/util/rpc"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -55,15 +59,45 @@ func startNodeManagerService(*cobra.Command, []string) {
 		startEvmSpaceNodeServer(ctx, &wg)
 	}
 
+	// serve metrics for prometheus to scrape if enabled
+	go metrics.MustServePrometheusGraceful(ctx, &wg)
+
 	util.GracefulShutdown(&wg, cancel)
 }
 
 func startNativeSpaceNodeServer(ctx context.Context, wg *sync.WaitGroup) {
-	server, endpoint := node.Factory().CreatRpcServer()
+	registry := mustNewNodeRegistry("cfx", mysql.MustNewConfigFromViper(), store.StoreConfig())
+	server, endpoint := node.Factory().CreatRpcServer(registry)
 	go server.MustServeGraceful(ctx, wg, endpoint, rpc.ProtocolHttp)
 }
 
 func startEvmSpaceNodeServer(ctx context.Context, wg *sync.WaitGroup) {
-	server, endpoint := node.EthFactory().CreatRpcServer()
+	registry := mustNewNodeRegistry("eth", mysql.MustNewEthStoreConfigFromViper(), store.EthStoreConfig())
+	server, endpoint := node.EthFactory().CreatRpcServer(registry)
 	go server.MustServeGraceful(ctx, wg, endpoint, rpc.ProtocolHttp)
 }
+
+// mustNewNodeRegistry creates node registry to persist fullnodes of the specified space, or
+// returns nil if not configured.
+func mustNewNodeRegistry(
+	space string, mysqlConfig *mysql.Config, disabler store.StoreDisabler,
+) node.Registry {
+	config := node.NodeRegistryConfig()
+
+	switch config.Storage {
+	case "":
+		return nil
+	case node.RegistryStorageRedis:
+		return node.NewRedisRegistry(redis.MustNewRedisClient(config.RedisURL), space)
+	case node.RegistryStorageMysql:
+		if !mysqlConfig.Enabled {
+			logrus.WithField("space", space).Fatal("MySQL must be enabled to persist node registry")
+		}
+
+		db := mysqlConfig.MustOpenOrCreate(mysql.StoreOption{Disabler: disabler})
+		return db.NodeRegistry()
+	default:
+		logrus.WithField("storage", config.Storage).Fatal("Unsupported node registry storage")
+		return nil
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/scroll-tech/rpc-gateway/cmd/test"
 	"github.com/scroll-tech/rpc-gateway/cmd/util"
 	"github.com/scroll-tech/rpc-gateway/config"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -19,6 +20,9 @@ var (
 	rpcServerEnabled  bool
 	syncServerEnabled bool
 
+	// embedded mode to run without any external database, e.g. for local development and CI
+	embeddedModeEnabled bool
+
 	rootCmd = &cobra.Command{
 		Use:   "confura",
 		Short: "Ethereum Infura like Public RPC Service on Conflux Network.",
@@ -47,6 +51,12 @@ func init() {
 		&syncServerEnabled, "sync", false, "whether to start data sync/prune service",
 	)
 
+	// boot flag for embedded mode, which is also available for sub commands
+	rootCmd.PersistentFlags().BoolVar(
+		&embeddedModeEnabled, "embedded", false,
+		"whether to run with embedded SQLite store and in-memory broker for evm space",
+	)
+
 	rootCmd.AddCommand(test.Cmd)
 }
 
@@ -57,6 +67,11 @@ func start(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	if embeddedModeEnabled && !nodeServerEnabled && !rpcServerEnabled && !syncServerEnabled {
+		// start both sync and RPC services within a single binary by default
+		rpcServerEnabled, syncServerEnabled = true, true
+	}
+
 	if !nodeServerEnabled && !rpcServerEnabled && !syncServerEnabled {
 		logrus.Fatal("No services started")
 	}
@@ -74,7 +89,13 @@ func start(cmd *cobra.Command, args []string) {
 		startSyncServiceAdaptively(ctx, wg, syncCtx)
 	}
 
-	if rpcServerEnabled { // start RPC
+	if rpcServerEnabled && embeddedModeEnabled { // start evm space RPC, and core space RPC if cache enabled
+		if storeCtx.cfxCache != nil {
+			startNativeSpaceRpcServer(ctx, wg, storeCtx)
+		}
+
+		startEvmSpaceRpcServer(ctx, wg, storeCtx)
+	} else if rpcServerEnabled { // start RPC
 		startNativeSpaceRpcServer(ctx, wg, storeCtx)
 		startEvmSpaceRpcServer(ctx, wg, storeCtx)
 		startNativeSpaceBridgeRpcServer(ctx, wg)
@@ -86,6 +107,9 @@ func start(cmd *cobra.Command, args []string) {
 		startEvmSpaceNodeServer(ctx, wg)
 	}
 
+	// serve metrics for prometheus to scrape if enabled
+	go metrics.MustServePrometheusGraceful(ctx, wg)
+
 	util.GracefulShutdown(wg, cancel)
 }
 
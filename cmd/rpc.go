@@ -15,12 +15,13 @@ import (
 	"github.com/spf13/viper"
 
 	viperutil "github.com/Conflux-Chain/go-conflux-util/viper"
-	gethrpc "github.com/ethereum/go-ethereum/rpc"
 	cmdutil "github.com/scroll-tech/rpc-gateway/cmd/util"
 	"github.com/scroll-tech/rpc-gateway/node"
 	"github.com/scroll-tech/rpc-gateway/rpc"
 	"github.com/scroll-tech/rpc-gateway/rpc/handler"
 	"github.com/scroll-tech/rpc-gateway/store/redis"
+	"github.com/scroll-tech/rpc-gateway/util/finality"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
 	"github.com/scroll-tech/rpc-gateway/util/rate"
 	"github.com/scroll-tech/rpc-gateway/util/relay"
 	rpcutil "github.com/scroll-tech/rpc-gateway/util/rpc"
@@ -93,12 +94,15 @@ func startRpcService(*cobra.Command, []string) {
 		startDebugSpaceRpcServer(ctx, &wg)
 	}
 
+	// serve metrics for prometheus to scrape if enabled
+	go metrics.MustServePrometheusGraceful(ctx, &wg)
+
 	cmdutil.GracefulShutdown(&wg, cancel)
 }
 
 // startNativeSpaceRpcServer starts core space RPC server
 func startNativeSpaceRpcServer(ctx context.Context, wg *sync.WaitGroup, storeCtx storeContext) {
-	router := node.Factory().CreateRouter()
+	router := node.Factory().CreateRouter(ctx)
 	option := rpc.CfxAPIOption{
 		Relayer: relay.MustNewTxnRelayerFromViper(),
 	}
@@ -122,7 +126,7 @@ func startNativeSpaceRpcServer(ctx context.Context, wg *sync.WaitGroup, storeCtx
 		if redisUrl := viper.GetString("rpc.throttling.redisUrl"); len(redisUrl) > 0 {
 			prunedHandler = handler.NewCfxPrunedLogsHandler(
 				node.NewCfxClientProvider(router),
-				storeCtx.cfxDB.UserStore,
+				storeCtx.cfxDB,
 				redis.MustNewRedisClient(redisUrl),
 			)
 		}
@@ -152,8 +156,17 @@ func startNativeSpaceRpcServer(ctx context.Context, wg *sync.WaitGroup, storeCtx
 
 // startEvmSpaceRpcServer starts evm space RPC server
 func startEvmSpaceRpcServer(ctx context.Context, wg *sync.WaitGroup, storeCtx storeContext) {
-	var option rpc.EthAPIOption
-	router := node.EthFactory().CreateRouter()
+	option := rpc.EthAPIOption{
+		LogsBroker: storeCtx.broker,
+		Relayer:    relay.MustNewEthTxnRelayerFromViper(),
+	}
+	// client provider shared by RPC server and transaction tracker
+	clientProvider := node.NewEthClientProvider(node.EthFactory().CreateRouter(ctx))
+
+	// serve `syncing` subscription by the sync status tracked by node manager if available
+	if syncStatus, ok := node.EthFactory().CreateSyncStatusProvider(); ok {
+		option.SyncStatusProvider = syncStatus
+	}
 
 	if storeCtx.ethDB != nil {
 		// initialize store handler
@@ -161,15 +174,37 @@ func startEvmSpaceRpcServer(ctx context.Context, wg *sync.WaitGroup, storeCtx st
 		// initialize logs api handler
 		option.LogApiHandler = handler.NewEthLogsApiHandler(storeCtx.ethDB)
 
+		// initialize gas station handler if enabled
+		if gasHandler, ok := handler.MustNewEthGasStationHandlerFromViper(storeCtx.ethDB); ok {
+			option.GasStationHandler = gasHandler
+			go gasHandler.Run(ctx)
+		}
+
+		// initialize transaction tracker if enabled, and only one instance checks the tracked
+		// transactions to avoid duplicate rebroadcasts
+		if txTracker, ok := handler.MustNewEthTxTrackerFromViper(storeCtx.ethDB, clientProvider); ok {
+			option.TxTracker = txTracker
+
+			if txTracker.IsChecker() {
+				go txTracker.Run(ctx)
+			}
+		}
+
 		// periodically reload rate limit settings from db
 		go rate.DefaultRegistryEth.AutoReload(
-			15*time.Second, storeCtx.ethDB.LoadRateLimitConfigs, storeCtx.cfxDB.LoadRateLimitKeyset,
+			15*time.Second, storeCtx.ethDB.LoadRateLimitConfigs, storeCtx.ethRateLimitKeysetLoader(),
 		)
 	}
 
+	// initialize response cache, which is invalidated on chain reorg
+	rpc.MustInitEthResponseCache(ctx, storeCtx.ethDB, storeCtx.broker)
+
+	// track chain finality to resolve `safe` and `finalized` block tags if enabled
+	finality.MustInitFromViper(ctx)
+
 	// initialize RPC server
 	exposedModules := viper.GetStringSlice("ethrpc.exposedModules")
-	server := rpc.MustNewEvmSpaceServer(router, exposedModules, option)
+	server := rpc.MustNewEvmSpaceServer(ctx, clientProvider, exposedModules, option)
 
 	// serve HTTP endpoint
 	httpEndpoint := viper.GetString("ethrpc.endpoint")
@@ -197,7 +232,7 @@ func startDebugSpaceRpcServer(ctx context.Context, wg *sync.WaitGroup) {
 	httpEndpoint := viper.GetString("debugrpc.endpoint")
 	ethNodeRPCURL := viper.GetString("node.router.ethnoderpcurl")
 	logrus.Debug("Debug Space RPC server HTTP endpoint=", httpEndpoint, ", ethNodeRPCURL=", ethNodeRPCURL)
-	client, err := gethrpc.DialHTTP(ethNodeRPCURL)
+	client, err := node.DialNodeRPC(ethNodeRPCURL)
 	if err != nil {
 		logrus.WithError(err).Panic("Failed to create rpc client for debugSpaceRpcServer")
 	}
@@ -8,6 +8,8 @@ import (
 	"github.com/scroll-tech/rpc-gateway/store"
 	cisync "github.com/scroll-tech/rpc-gateway/sync"
 	"github.com/scroll-tech/rpc-gateway/sync/catchup"
+	"github.com/scroll-tech/rpc-gateway/util/finality"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -118,6 +120,9 @@ func startSyncService(*cobra.Command, []string) {
 		startSyncEthDatabase(ctx, &wg, syncCtx)
 	}
 
+	// serve metrics for prometheus to scrape if enabled
+	go metrics.MustServePrometheusGraceful(ctx, &wg)
+
 	util.GracefulShutdown(&wg, cancel)
 }
 
@@ -186,7 +191,10 @@ func startSyncCfxCache(ctx context.Context, wg *sync.WaitGroup, syncCtx syncCont
 func startSyncEthDatabase(ctx context.Context, wg *sync.WaitGroup, syncCtx syncContext) {
 	logrus.Info("Start to sync evm space blockchain data into database")
 
-	ethSyncer := cisync.MustNewEthSyncer(syncCtx.syncEth, syncCtx.ethDB)
+	// track chain finality if enabled, e.g. to sync finalized blocks only
+	finality.MustInitFromViper(ctx)
+
+	ethSyncer := cisync.MustNewEthSyncer(syncCtx.syncEth, syncCtx.ethDB, syncCtx.broker)
 	go ethSyncer.Sync(ctx, wg)
 
 	// start evm space db prune
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"syscall"
 
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
 	"github.com/sirupsen/logrus"
 )
 
@@ -26,6 +27,9 @@ func GracefulShutdown(wg *sync.WaitGroup, cancel context.CancelFunc) {
 	logrus.Info("Waiting for shutdown...")
 	wg.Wait()
 
+	// flush the pending spans if any
+	tracing.Shutdown()
+
 	logrus.Info("Shutdown gracefully")
 }
 
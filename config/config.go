@@ -1,6 +1,7 @@
 package config
 
 import (
+	"os"
 	"strings"
 
 	"github.com/Conflux-Chain/go-conflux-util/viper"
@@ -8,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/util/alert"
 	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
 	"github.com/sirupsen/logrus"
 
 	// For go-ethereum v1.0.15, node pkg imports internal/debug pkg which will inits log root
@@ -21,16 +23,25 @@ import (
 const viperEnvPrefix = "infura"
 
 func init() {
-	// init viper
-	viper.MustInit(viperEnvPrefix)
+	// init viper, except for unit tests which set the required configurations explicitly
+	if !isTestBinary() {
+		viper.MustInit(viperEnvPrefix)
+	}
 	// init logger
 	initLogger()
 	// init metrics
 	metrics.Init()
+	// init tracing
+	tracing.Init()
 	// init alert
 	alert.InitDingRobot()
 }
 
+// isTestBinary returns true if running as the binary compiled by `go test`.
+func isTestBinary() bool {
+	return strings.HasSuffix(strings.TrimSuffix(os.Args[0], ".exe"), ".test")
+}
+
 func initLogger() {
 	var config struct {
 		Level      string `default:"info"`
@@ -0,0 +1,280 @@
+package node
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/util/rpc/handlers"
+	"github.com/sirupsen/logrus"
+)
+
+// Role of node management RPC caller.
+type Role string
+
+const (
+	// RoleReadOnly is allowed to list nodes, query node status and route keys.
+	RoleReadOnly Role = "readonly"
+	// RoleService is allowed to report the states observed by RPC services in addition.
+	RoleService Role = "service"
+	// RoleAdmin is allowed to mutate nodes in addition.
+	RoleAdmin Role = "admin"
+)
+
+// roleLevels is the privilege level of roles, and the higher level is granted the lower ones.
+var roleLevels = map[Role]int{RoleReadOnly: 1, RoleService: 2, RoleAdmin: 3}
+
+// ctxKeyIdentity is the context key of authenticated caller identity.
+const ctxKeyIdentity = handlers.CtxKey("Infura-Node-Identity")
+
+var errPermissionDenied = errors.New("permission denied")
+
+type authUserConfig struct {
+	// caller name for audit
+	Name string
+	Role Role
+	// bearer token in `Authorization` HTTP header
+	Token string
+	// common name of client certificate for mutual TLS authentication
+	CommonName string
+}
+
+type authConfig struct {
+	// whether to authenticate node management RPC callers
+	Enabled bool
+	Users   []authUserConfig
+	// serve HTTPS if certificate configured, and verify client certificate if CA configured
+	TLS struct {
+		CertFile     string
+		KeyFile      string
+		ClientCAFile string
+	}
+	// file to append audit log of mutations in JSON lines, or standard logger if empty
+	AuditLog string
+}
+
+// clientAuthConfig credentials to access node management RPC.
+type clientAuthConfig struct {
+	// bearer token in `Authorization` HTTP header
+	Token string
+	// client certificate for mutual TLS authentication
+	CertFile string
+	KeyFile  string
+	// CA to verify server certificate, or system CA if empty
+	CAFile string
+}
+
+// Identity is the authenticated caller of node management RPC.
+type Identity struct {
+	Name string
+	Role Role
+}
+
+func identityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(ctxKeyIdentity).(Identity)
+	return identity, ok
+}
+
+// authenticator authenticates node management RPC callers by bearer token or client certificate.
+type authenticator struct {
+	tokens      map[string]Identity // token => identity
+	commonNames map[string]Identity // certificate common name => identity
+}
+
+func newAuthenticator(users []authUserConfig) (*authenticator, error) {
+	auth := authenticator{
+		tokens:      make(map[string]Identity),
+		commonNames: make(map[string]Identity),
+	}
+
+	for _, user := range users {
+		if _, ok := roleLevels[user.Role]; !ok {
+			return nil, errors.Errorf("invalid role %v of user %v", user.Role, user.Name)
+		}
+
+		if len(user.Token) == 0 && len(user.CommonName) == 0 {
+			return nil, errors.Errorf("neither token nor common name configured for user %v", user.Name)
+		}
+
+		identity := Identity{Name: user.Name, Role: user.Role}
+
+		if len(user.Token) > 0 {
+			auth.tokens[user.Token] = identity
+		}
+
+		if len(user.CommonName) > 0 {
+			auth.commonNames[user.CommonName] = identity
+		}
+	}
+
+	return &auth, nil
+}
+
+func (a *authenticator) authenticate(r *http.Request) (Identity, bool) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		if identity, ok := a.commonNames[r.TLS.VerifiedChains[0][0].Subject.CommonName]; ok {
+			return identity, true
+		}
+	}
+
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return Identity{}, false
+	}
+
+	token := []byte(strings.TrimPrefix(header, "Bearer "))
+
+	// in constant time to prevent timing attack
+	for k, identity := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(k), token) == 1 {
+			return identity, true
+		}
+	}
+
+	return Identity{}, false
+}
+
+// middleware rejects unauthenticated requests, and injects the caller identity into context.
+func (a *authenticator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := a.authenticate(r)
+		if !ok {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), ctxKeyIdentity, identity)
+		ctx = context.WithValue(ctx, handlers.CtxKeyRealIP, handlers.GetIPAddress(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authorize checks if the caller is granted the specified role.
+func authorize(ctx context.Context, role Role) error {
+	if !cfg.Auth.Enabled {
+		return nil
+	}
+
+	identity, ok := identityFromContext(ctx)
+	if !ok || roleLevels[identity.Role] < roleLevels[role] {
+		return errPermissionDenied
+	}
+
+	return nil
+}
+
+func mustNewServerTLSConfig() *tls.Config {
+	if len(cfg.Auth.TLS.CertFile) == 0 {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.Auth.TLS.CertFile, cfg.Auth.TLS.KeyFile)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to load node manager server certificate")
+	}
+
+	config := tls.Config{Certificates: []tls.Certificate{cert}}
+
+	if len(cfg.Auth.TLS.ClientCAFile) > 0 {
+		config.ClientCAs = mustLoadCertPool(cfg.Auth.TLS.ClientCAFile)
+		// token authentication is still allowed without client certificate
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return &config
+}
+
+func mustLoadCertPool(file string) *x509.CertPool {
+	pool, err := loadCertPool(file)
+	if err != nil {
+		logrus.WithError(err).WithField("file", file).Fatal("Failed to load CA file")
+	}
+
+	return pool
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read CA file")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.Errorf("no valid certificate found in CA file %v", file)
+	}
+
+	return pool, nil
+}
+
+// DialNodeRPC dials node management RPC with the configured credentials, if any.
+func DialNodeRPC(url string) (*rpc.Client, error) {
+	auth := cfg.Router.NodeRpcAuth
+
+	var client *rpc.Client
+	var err error
+
+	if len(auth.CertFile) > 0 || len(auth.CAFile) > 0 {
+		var config tls.Config
+
+		if len(auth.CertFile) > 0 {
+			cert, err := tls.LoadX509KeyPair(auth.CertFile, auth.KeyFile)
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to load client certificate")
+			}
+
+			config.Certificates = []tls.Certificate{cert}
+		}
+
+		if len(auth.CAFile) > 0 {
+			if config.RootCAs, err = loadCertPool(auth.CAFile); err != nil {
+				return nil, err
+			}
+		}
+
+		client, err = rpc.DialHTTPWithClient(url, &http.Client{
+			Transport: &http.Transport{TLSClientConfig: &config},
+		})
+	} else {
+		client, err = rpc.DialHTTP(url)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(auth.Token) > 0 {
+		if !strings.HasPrefix(strings.ToLower(url), "https://") {
+			logrus.WithField("url", url).Warn("Bearer token sent to node management RPC without HTTPS")
+		}
+
+		client.SetHeader("Authorization", "Bearer "+auth.Token)
+	}
+
+	return client, nil
+}
+
+// mustNewAuditLogger creates logger to write audit log of node mutations.
+func mustNewAuditLogger(file string) *logrus.Logger {
+	if len(file) == 0 {
+		return logrus.StandardLogger()
+	}
+
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		logrus.WithError(err).WithField("file", file).Fatal("Failed to open audit log file")
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(f)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	return logger
+}
@@ -0,0 +1,83 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticator(t *testing.T) {
+	_, err := newAuthenticator([]authUserConfig{{Name: "ops", Role: "root", Token: "t"}})
+	assert.Error(t, err)
+
+	auth, err := newAuthenticator([]authUserConfig{
+		{Name: "ops", Role: RoleAdmin, Token: "admin-token"},
+		{Name: "gateway", Role: RoleReadOnly, Token: "readonly-token"},
+	})
+	assert.NoError(t, err)
+
+	var identity Identity
+	handler := auth.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, _ = identityFromContext(r.Context())
+	}))
+
+	serve := func(header string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if len(header) > 0 {
+			req.Header.Set("Authorization", header)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve(""))
+	assert.Equal(t, http.StatusUnauthorized, serve("Bearer invalid-token"))
+	assert.Equal(t, http.StatusUnauthorized, serve("admin-token"))
+
+	assert.Equal(t, http.StatusOK, serve("Bearer readonly-token"))
+	assert.Equal(t, Identity{Name: "gateway", Role: RoleReadOnly}, identity)
+
+	assert.Equal(t, http.StatusOK, serve("Bearer admin-token"))
+	assert.Equal(t, Identity{Name: "ops", Role: RoleAdmin}, identity)
+}
+
+func TestAuthorizeMutation(t *testing.T) {
+	cfg.Auth.Enabled = true
+	defer func() { cfg.Auth.Enabled = false }()
+
+	groupConf := map[Group]UrlConfig{GroupEthHttp: {}}
+	api := mustNewApi(newTestNode, groupConf, nil)
+
+	readonly := context.WithValue(context.Background(), ctxKeyIdentity, Identity{Name: "gateway", Role: RoleReadOnly})
+	assert.Equal(t, errPermissionDenied, api.Add(readonly, GroupEthHttp, "http://node1:8545"))
+
+	service := context.WithValue(context.Background(), ctxKeyIdentity, Identity{Name: "gateway", Role: RoleService})
+	assert.Equal(t, errPermissionDenied, api.Add(service, GroupEthHttp, "http://node1:8545"))
+	assert.Equal(t, errPermissionDenied, api.Add(context.Background(), GroupEthHttp, "http://node1:8545"))
+	assert.Empty(t, api.List(GroupEthHttp))
+
+	admin := context.WithValue(context.Background(), ctxKeyIdentity, Identity{Name: "ops", Role: RoleAdmin})
+	assert.NoError(t, api.Add(admin, GroupEthHttp, "http://node1:8545"))
+	assert.Equal(t, []string{"http://node1:8545"}, api.List(GroupEthHttp))
+
+	assert.Equal(t, errPermissionDenied, api.Remove(readonly, GroupEthHttp, "http://node1:8545"))
+	assert.NoError(t, api.Remove(admin, GroupEthHttp, "http://node1:8545"))
+	assert.Empty(t, api.List(GroupEthHttp))
+}
+
+func TestDialNodeRPCWithBadCA(t *testing.T) {
+	oldAuth := cfg.Router.NodeRpcAuth
+	defer func() { cfg.Router.NodeRpcAuth = oldAuth }()
+
+	// error returned rather than exit
+	cfg.Router.NodeRpcAuth = clientAuthConfig{CAFile: filepath.Join(t.TempDir(), "ca.pem")}
+	_, err := DialNodeRPC("https://127.0.0.1:22530")
+	assert.Error(t, err)
+}
@@ -4,6 +4,11 @@ import (
 	"context"
 	"sync"
 
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	gorpc "github.com/openweb3/go-rpc-provider"
+	"github.com/openweb3/go-rpc-provider/interfaces"
+	providers "github.com/openweb3/go-rpc-provider/provider_wrapper"
+	"github.com/openweb3/web3go"
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/util"
 	"github.com/scroll-tech/rpc-gateway/util/rpc"
@@ -11,6 +16,10 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ctxKeyRoutingKey is the routing key of request bound to the upstream calls, so that retry and
+// hedge walk the hash ring successors of the request.
+const ctxKeyRoutingKey = handlers.CtxKey("Infura-RPC-Routing-Key")
+
 var (
 	ErrClientUnavailable = errors.New("no full node available")
 )
@@ -28,13 +37,21 @@ type clientProvider struct {
 
 	// group => node name => RPC client
 	clients map[Group]*util.ConcurrentMap
+
+	// group => budget of hedged requests
+	hedgeBudgets map[Group]*hedgeBudget
 }
 
 func newClientProvider(router Router, factory clientFactory) *clientProvider {
+	if _, ok := router.(SuccessorRouter); !ok && (cfg.Retry.Enabled || cfg.Hedge.Enabled) {
+		logrus.Warn("Router doesn't support to route successor fullnodes, upstream calls won't be retried or hedged")
+	}
+
 	return &clientProvider{
-		router:  router,
-		factory: factory,
-		clients: make(map[Group]*util.ConcurrentMap),
+		router:       router,
+		factory:      factory,
+		clients:      make(map[Group]*util.ConcurrentMap),
+		hedgeBudgets: make(map[Group]*hedgeBudget),
 	}
 }
 
@@ -71,6 +88,66 @@ func (p *clientProvider) getClient(key string, group Group) (interface{}, error)
 		return nil, ErrClientUnavailable
 	}
 
+	if !breakerAllow(rpc.Url2NodeName(url)) {
+		url = p.rerouteByBreaker(group, key, url)
+	}
+
+	client, err := p.getClientByURL(url, group, clients, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientWithRoutingKey(client, key), nil
+}
+
+// rerouteByBreaker walks the hash ring successors of the routing key for another fullnode allowed
+// by circuit breaker, or returns the specified fullnode if none available, in which case requests
+// fail fast.
+func (p *clientProvider) rerouteByBreaker(group Group, key string, url string) string {
+	if router, ok := p.router.(SuccessorRouter); ok {
+		for _, next := range router.RouteSuccessors(group, []byte(key)) {
+			if breakerAllow(rpc.Url2NodeName(next)) {
+				return next
+			}
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"group": group,
+		"node":  rpc.Url2NodeName(url),
+	}).Debug("No fullnode available with circuit closed, use the open circuit one")
+
+	return url
+}
+
+// getClientByNodeName gets client of the specified fullnode by node name, which is looked up among
+// the connected fullnodes at first, and then all the fullnodes of group if router supports.
+func (p *clientProvider) getClientByNodeName(group Group, nodeName string) (interface{}, error) {
+	clients, ok := p.clients[group]
+	if !ok {
+		return nil, errors.Errorf("Unknown node group %v", group)
+	}
+
+	if client, ok := clients.Load(nodeName); ok {
+		return client, nil
+	}
+
+	if router, ok := p.router.(SuccessorRouter); ok {
+		for _, url := range router.RouteSuccessors(group, []byte(nodeName)) {
+			if rpc.Url2NodeName(url) == nodeName {
+				logger := logrus.WithField("group", group)
+				return p.getClientByURL(url, group, clients, logger)
+			}
+		}
+	}
+
+	return nil, ErrClientUnavailable
+}
+
+// getClientByURL gets client of the specified fullnode URL, or creates a new one if absent.
+func (p *clientProvider) getClientByURL(
+	url string, group Group, clients *util.ConcurrentMap, logger *logrus.Entry,
+) (interface{}, error) {
 	nodeName := rpc.Url2NodeName(url)
 
 	logger = logger.WithFields(logrus.Fields{
@@ -83,7 +160,17 @@ func (p *clientProvider) getClient(key string, group Group) (interface{}, error)
 		// TODO improvements required
 		// 1. Necessary retry? (but longer timeout). Better to let user side to decide.
 		// 2. Different metrics for different full nodes.
-		return p.factory(url)
+		client, err := p.factory(url)
+		if err == nil {
+			// hedge ahead of retry, so that hedged requests won't be retried
+			p.hookHedge(client, group, nodeName)
+			p.hookRetry(client, group, nodeName)
+			// observe each upstream call to this fullnode rather than retried or hedged ones
+			hookLoad(client, nodeName)
+			hookBreaker(client, group, nodeName)
+		}
+
+		return client, err
 	})
 
 	if err != nil {
@@ -100,6 +187,59 @@ func (p *clientProvider) getClient(key string, group Group) (interface{}, error)
 	return client, nil
 }
 
+// clientWithRoutingKey wraps the shared client of fullnode to bind the routing key to upstream
+// calls, since typed client methods always call with background context, and thus the routing
+// key is unavailable for the client middlewares.
+func clientWithRoutingKey(client interface{}, key string) interface{} {
+	switch c := client.(type) {
+	case *Web3goClient:
+		provider := &routingKeyProvider{Provider: c.Provider(), key: key}
+		return &Web3goClient{Client: web3go.NewClientWithProvider(provider), URL: c.URL}
+	case *sdk.Client:
+		keyed := *c
+		keyed.MiddlewarableProvider = providers.NewMiddlewarableProvider(
+			&routingKeyProvider{Provider: c.Provider(), key: key},
+		)
+		return &keyed
+	default:
+		return client
+	}
+}
+
+// routingKeyProvider binds the routing key to the upstream calls without routing key.
+type routingKeyProvider struct {
+	interfaces.Provider
+	key string
+}
+
+func (p *routingKeyProvider) withRoutingKey(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(ctxKeyRoutingKey).(string); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, ctxKeyRoutingKey, p.key)
+}
+
+func (p *routingKeyProvider) CallContext(
+	ctx context.Context, result interface{}, method string, args ...interface{},
+) error {
+	return p.Provider.CallContext(p.withRoutingKey(ctx), result, method, args...)
+}
+
+func (p *routingKeyProvider) BatchCallContext(ctx context.Context, b []gorpc.BatchElem) error {
+	return p.Provider.BatchCallContext(p.withRoutingKey(ctx), b)
+}
+
+// routingKeyFromContext returns the routing key bound to upstream call, or remote address of
+// request if absent.
+func routingKeyFromContext(ctx context.Context) string {
+	if key, ok := ctx.Value(ctxKeyRoutingKey).(string); ok {
+		return key
+	}
+
+	return remoteAddrFromContext(ctx)
+}
+
 func remoteAddrFromContext(ctx context.Context) string {
 	if ip, ok := handlers.GetIPAddressFromContext(ctx); ok {
 		return ip
@@ -0,0 +1,351 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	providers "github.com/openweb3/go-rpc-provider/provider_wrapper"
+	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// BreakerState is the state of circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed allows all requests to fullnode.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects requests to fullnode until timeout.
+	BreakerOpen
+	// BreakerHalfOpen allows probe requests to fullnode to check if recovered.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "halfOpen"
+	default:
+		return "unknown"
+	}
+}
+
+type breakerConfig struct {
+	// whether to break circuit of fullnodes upon high error rate of upstream calls
+	Enabled bool
+	// time window to count the error rate of upstream calls
+	Window time.Duration `default:"10s"`
+	// min number of upstream calls within window to evaluate error rate
+	MinRequests uint64 `default:"20"`
+	// error rate of transport errors to open circuit, e.g. 0.5 means 50%
+	ErrorRate float64 `default:"0.5"`
+	// duration to keep circuit open before half-open
+	OpenTimeout time.Duration `default:"30s"`
+	// interval to allow a probe request while half-open
+	ProbeInterval time.Duration `default:"1s"`
+	// number of consecutive succeeded probe requests to close circuit
+	ProbeSuccesses uint64 `default:"3"`
+	// interval to report circuit breaker states to node manager if node manager RPC configured
+	// along with credentials of service role
+	ReportInterval time.Duration `default:"5s"`
+}
+
+// circuitBreaker breaks circuit of fullnode by the live outcomes of upstream calls, which are
+// the same as the non-RPC error rate metrics of fullnode.
+type circuitBreaker struct {
+	space    string
+	nodeName string
+
+	mu    sync.Mutex
+	state BreakerState
+
+	windowStart time.Time
+	requests    uint64 // number of upstream calls within window
+	failures    uint64 // number of transport errors within window
+
+	openedAt  time.Time
+	probedAt  time.Time
+	successes uint64 // consecutive succeeded probe requests while half-open
+}
+
+func newCircuitBreaker(space, nodeName string) *circuitBreaker {
+	return &circuitBreaker{
+		space:       space,
+		nodeName:    nodeName,
+		windowStart: time.Now(),
+	}
+}
+
+// State returns the current state of circuit breaker.
+func (b *circuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.checkTimeout(time.Now())
+
+	return b.state
+}
+
+// allow returns true if requests allowed to the fullnode, and only one probe request allowed
+// per probe interval while half-open.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.checkTimeout(now)
+
+	switch b.state {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if now.Sub(b.probedAt) < cfg.Breaker.ProbeInterval {
+			return false
+		}
+
+		b.probedAt = now
+	}
+
+	return true
+}
+
+// observe observes the outcome of upstream call.
+func (b *circuitBreaker) observe(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.checkTimeout(now)
+
+	switch b.state {
+	case BreakerClosed:
+		if now.Sub(b.windowStart) > cfg.Breaker.Window {
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+
+		b.requests++
+		if failed {
+			b.failures++
+		}
+
+		if b.requests >= cfg.Breaker.MinRequests &&
+			float64(b.failures) >= float64(b.requests)*cfg.Breaker.ErrorRate {
+			b.transit(BreakerOpen, now)
+		}
+	case BreakerHalfOpen:
+		if failed {
+			b.transit(BreakerOpen, now)
+		} else if b.successes++; b.successes >= cfg.Breaker.ProbeSuccesses {
+			b.transit(BreakerClosed, now)
+		}
+	}
+}
+
+func (b *circuitBreaker) checkTimeout(now time.Time) {
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= cfg.Breaker.OpenTimeout {
+		b.transit(BreakerHalfOpen, now)
+	}
+}
+
+func (b *circuitBreaker) transit(state BreakerState, now time.Time) {
+	logrus.WithFields(logrus.Fields{
+		"node":     b.nodeName,
+		"from":     b.state,
+		"to":       state,
+		"requests": b.requests,
+		"failures": b.failures,
+	}).Info("Fullnode circuit breaker state changed")
+
+	b.state = state
+
+	switch state {
+	case BreakerOpen:
+		b.openedAt = now
+		metrics.Registry.RPC.FullnodeBreakerOpened(b.space, b.nodeName).Mark(1)
+	case BreakerHalfOpen:
+		b.probedAt, b.successes = time.Time{}, 0
+	case BreakerClosed:
+		b.windowStart, b.requests, b.failures = now, 0, 0
+	}
+
+	metrics.Registry.RPC.FullnodeBreakerState(b.space, b.nodeName).Update(int64(state))
+}
+
+// nodeBreakers is the circuit breakers of fullnodes by node name, which is shared among groups
+// since the same fullnode may be configured in multiple groups.
+var nodeBreakers util.ConcurrentMap
+
+func getCircuitBreaker(space, nodeName string) *circuitBreaker {
+	val, _ := nodeBreakers.LoadOrStoreFn(nodeName, func(interface{}) interface{} {
+		return newCircuitBreaker(space, nodeName)
+	})
+
+	return val.(*circuitBreaker)
+}
+
+// BreakerStateOf returns the circuit breaker state of the specified fullnode, which is the local
+// one if RPC service running in the same process, otherwise the one reported by RPC services.
+func BreakerStateOf(nodeName string) (BreakerState, bool) {
+	if val, ok := nodeBreakers.Load(nodeName); ok {
+		return val.(*circuitBreaker).State(), true
+	}
+
+	return reportedBreakers.state(nodeName)
+}
+
+// breakerStates returns the states of all circuit breakers by node name.
+func breakerStates() map[string]BreakerState {
+	states := make(map[string]BreakerState)
+
+	nodeBreakers.Range(func(key, value interface{}) bool {
+		states[key.(string)] = value.(*circuitBreaker).State()
+		return true
+	})
+
+	return states
+}
+
+// breakerReporterID identifies the RPC service instance that reports circuit breaker states, since
+// RPC service instances may share the same credentials.
+var breakerReporterID = newInstanceID()
+
+func newInstanceID() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%v-%v-%x", hostname, os.Getpid(), rand.Uint32())
+}
+
+// reportBreakerStates periodically reports circuit breaker states to node manager, since circuit
+// breakers live in RPC services while node status is served by node manager.
+func reportBreakerStates(ctx context.Context, client *rpc.Client) {
+	ticker := time.NewTicker(cfg.Breaker.ReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		states := breakerStates()
+		if len(states) == 0 {
+			continue
+		}
+
+		if err := client.Call(nil, "node_reportBreakers", breakerReporterID, states); err != nil {
+			logrus.WithError(err).Warn("Failed to report circuit breaker states to node manager RPC")
+		}
+	}
+}
+
+// breakerReport is the circuit breaker state reported by RPC service.
+type breakerReport struct {
+	state      BreakerState
+	reportedAt time.Time
+}
+
+// breakerReports collects circuit breaker states reported by RPC services.
+type breakerReports struct {
+	mu      sync.Mutex
+	reports map[string]map[string]breakerReport // node name => reporter => report
+}
+
+// reportedBreakers is the circuit breaker states reported to node manager.
+var reportedBreakers = breakerReports{reports: make(map[string]map[string]breakerReport)}
+
+func (r *breakerReports) report(reporter string, states map[string]BreakerState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	for nodeName, state := range states {
+		if _, ok := r.reports[nodeName]; !ok {
+			r.reports[nodeName] = make(map[string]breakerReport)
+		}
+
+		r.reports[nodeName][reporter] = breakerReport{state, now}
+	}
+}
+
+// state returns the worst state of the specified fullnode among RPC services, and the ones that
+// not reported recently are ignored, e.g. RPC service stopped.
+func (r *breakerReports) state(nodeName string) (BreakerState, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result, found := BreakerClosed, false
+	expiry := time.Now().Add(-3 * cfg.Breaker.ReportInterval)
+
+	for reporter, report := range r.reports[nodeName] {
+		if report.reportedAt.Before(expiry) {
+			delete(r.reports[nodeName], reporter)
+			continue
+		}
+
+		if !found || report.state.severity() > result.severity() {
+			result, found = report.state, true
+		}
+	}
+
+	return result, found
+}
+
+// severity returns the severity of state, e.g. open is worse than half-open.
+func (s BreakerState) severity() int {
+	switch s {
+	case BreakerOpen:
+		return 2
+	case BreakerHalfOpen:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// breakerAllow returns true if requests allowed to the specified fullnode by circuit breaker.
+func breakerAllow(nodeName string) bool {
+	if !cfg.Breaker.Enabled {
+		return true
+	}
+
+	val, ok := nodeBreakers.Load(nodeName)
+	return !ok || val.(*circuitBreaker).allow()
+}
+
+// hookBreaker hooks middleware for the RPC client of the specified fullnode to feed circuit
+// breaker if enabled.
+func hookBreaker(client interface{}, group Group, nodeName string) {
+	if !cfg.Breaker.Enabled {
+		return
+	}
+
+	if provider := middlewarableProviderOf(client); provider != nil {
+		provider.HookCallContext(middlewareBreaker(getCircuitBreaker(group.Space(), nodeName)))
+	}
+}
+
+func middlewareBreaker(breaker *circuitBreaker) providers.CallContextMiddleware {
+	return func(handler providers.CallContextFunc) providers.CallContextFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			err := handler(ctx, result, method, args...)
+
+			// caller canceled, e.g. hedged by another fullnode
+			if ctx.Err() != context.Canceled {
+				breaker.observe(isTransportError(err))
+			}
+
+			return err
+		}
+	}
+}
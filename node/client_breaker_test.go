@@ -0,0 +1,155 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func setBreakerConfig(t *testing.T) {
+	oldConf := cfg.Breaker
+	t.Cleanup(func() { cfg.Breaker = oldConf })
+
+	cfg.Breaker = breakerConfig{
+		Enabled:        true,
+		Window:         time.Minute,
+		MinRequests:    4,
+		ErrorRate:      0.5,
+		OpenTimeout:    50 * time.Millisecond,
+		ProbeInterval:  10 * time.Millisecond,
+		ProbeSuccesses: 2,
+		ReportInterval: time.Minute,
+	}
+}
+
+func TestCircuitBreakerStates(t *testing.T) {
+	setBreakerConfig(t)
+
+	breaker := newCircuitBreaker("eth", "node1")
+	assert.True(t, breaker.allow())
+
+	// not enough requests to evaluate error rate
+	breaker.observe(true)
+	breaker.observe(true)
+	breaker.observe(true)
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	// opened due to high error rate
+	breaker.observe(false)
+	assert.Equal(t, BreakerOpen, breaker.State())
+	assert.False(t, breaker.allow())
+
+	// half-open after timeout, and allow only one probe per interval
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	assert.True(t, breaker.allow())
+	assert.False(t, breaker.allow())
+
+	// opened again if probe failed
+	breaker.observe(true)
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	// closed after consecutive succeeded probes
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, breaker.allow())
+	breaker.observe(false)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	breaker.observe(false)
+	assert.Equal(t, BreakerClosed, breaker.State())
+	assert.True(t, breaker.allow())
+}
+
+func TestClientSkipOpenCircuit(t *testing.T) {
+	setBreakerConfig(t)
+
+	okServer := newTestRpcServer(`"result":"0x1"`)
+	defer okServer.Close()
+
+	deadUrl := "http://127.0.0.1:2" // connection refused
+	deadNode := rpc.Url2NodeName(deadUrl)
+	defer nodeBreakers.Delete(deadNode)
+
+	router := &mockRouter{
+		urls: map[string]string{"dead": deadUrl},
+		url:  okServer.URL,
+	}
+
+	provider := NewEthClientProvider(router)
+	provider.registerGroup(GroupEthHttp)
+
+	for i := uint64(0); i < cfg.Breaker.MinRequests; i++ {
+		client, err := provider.getClient("dead", GroupEthHttp)
+		assert.NoError(t, err)
+
+		var result string
+		err = client.(*Web3goClient).Provider().CallContext(context.Background(), &result, "eth_blockNumber")
+		assert.Error(t, err)
+	}
+
+	state, ok := BreakerStateOf(deadNode)
+	assert.True(t, ok)
+	assert.Equal(t, BreakerOpen, state)
+
+	// rerouted to another fullnode
+	client, err := provider.getClient("dead", GroupEthHttp)
+	assert.NoError(t, err)
+	assert.Equal(t, okServer.URL, client.(*Web3goClient).URL)
+}
+
+func TestReportBreakers(t *testing.T) {
+	setBreakerConfig(t)
+
+	api := mustNewApi(newTestNode, map[Group]UrlConfig{}, nil)
+
+	_, ok := BreakerStateOf("test-reported-node")
+	assert.False(t, ok)
+
+	open := map[string]BreakerState{"test-reported-node": BreakerOpen}
+	halfOpen := map[string]BreakerState{"test-reported-node": BreakerHalfOpen}
+
+	service := context.WithValue(context.Background(), ctxKeyIdentity, Identity{Name: "gateway", Role: RoleService})
+	readonly := context.WithValue(context.Background(), ctxKeyIdentity, Identity{Name: "viewer", Role: RoleReadOnly})
+
+	cfg.Auth.Enabled = true
+
+	assert.Equal(t, errPermissionDenied, api.ReportBreakers(readonly, "rpc1", open))
+	assert.Error(t, api.ReportBreakers(service, "", open))
+
+	_, ok = BreakerStateOf("test-reported-node")
+	assert.False(t, ok)
+
+	// anonymous reports accepted if authentication disabled, like other node management RPCs
+	cfg.Auth.Enabled = false
+	assert.NoError(t, api.ReportBreakers(context.Background(), "rpc0", halfOpen))
+
+	state, ok := BreakerStateOf("test-reported-node")
+	assert.True(t, ok)
+	assert.Equal(t, BreakerHalfOpen, state)
+
+	cfg.Auth.Enabled = true
+	defer func() { cfg.Auth.Enabled = false }()
+
+	// the worst state among RPC service instances that share the same credentials
+	assert.NoError(t, api.ReportBreakers(service, "rpc1", open))
+	assert.NoError(t, api.ReportBreakers(service, "rpc2", halfOpen))
+
+	state, ok = BreakerStateOf("test-reported-node")
+	assert.True(t, ok)
+	assert.Equal(t, BreakerOpen, state)
+
+	status := NewStatus(GroupEthHttp, "test-reported-node")
+	defer status.Close()
+
+	data, err := json.Marshal(&status)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"circuitBreaker":"open"`)
+
+	// stale reports ignored, e.g. RPC service stopped
+	cfg.Breaker.ReportInterval = 0
+	_, ok = BreakerStateOf("test-reported-node")
+	assert.False(t, ok)
+}
@@ -0,0 +1,191 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	providers "github.com/openweb3/go-rpc-provider/provider_wrapper"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// max number of hedged requests that could be accumulated in budget for burst
+const hedgeBudgetBurst = 10
+
+type hedgeConfig struct {
+	// whether to hedge latency-sensitive read requests to another fullnode
+	Enabled bool
+	// methods to hedge, which must be idempotent
+	Methods []string `default:"[eth_call,eth_getBalance,eth_getCode,eth_getStorageAt,eth_getTransactionCount,eth_estimateGas,cfx_call,cfx_getBalance,cfx_getCode,cfx_getStorageAt,cfx_getNextNonce,cfx_estimateGasAndCollateral]"`
+	// percentile of the primary fullnode latency observed from upstream calls to wait for before hedging
+	Percentile float64 `default:"0.95"`
+	// delay before hedging if no latency sampled for the primary fullnode
+	Delay time.Duration `default:"100ms"`
+	// lower bound of the delay before hedging
+	MinDelay time.Duration `default:"20ms"`
+	// max ratio of hedged requests to all the requests, e.g. 0.1 means at most 10% extra load
+	MaxRatio float64 `default:"0.1"`
+}
+
+// hedgeMethods is the set of methods to hedge.
+var hedgeMethods = make(map[string]bool)
+
+// hedgeBudget limits the hedged requests by ratio in token bucket way, i.e. each request
+// earns the ratio of token and each hedged request costs a whole token.
+type hedgeBudget struct {
+	mu     sync.Mutex
+	ratio  float64
+	tokens float64
+}
+
+func newHedgeBudget(ratio float64) *hedgeBudget {
+	return &hedgeBudget{ratio: ratio}
+}
+
+func (b *hedgeBudget) earn() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens += b.ratio; b.tokens > hedgeBudgetBurst {
+		b.tokens = hedgeBudgetBurst
+	}
+}
+
+func (b *hedgeBudget) spend() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
+
+type hedgeResponse struct {
+	result json.RawMessage
+	err    error
+	hedged bool
+}
+
+// hookHedge hooks the hedge middleware for the RPC client of the specified fullnode if enabled.
+func (p *clientProvider) hookHedge(client interface{}, group Group, nodeName string) {
+	if !cfg.Hedge.Enabled || cfg.Hedge.MaxRatio <= 0 {
+		return
+	}
+
+	if provider := middlewarableProviderOf(client); provider != nil {
+		provider.HookCallContext(p.middlewareHedge(group, nodeName))
+	}
+}
+
+// middlewareHedge sends the same request to another fullnode of the same group if the primary
+// fullnode doesn't respond in time, and the first response wins.
+func (p *clientProvider) middlewareHedge(group Group, nodeName string) providers.CallContextMiddleware {
+	return func(handler providers.CallContextFunc) providers.CallContextFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			if !hedgeMethods[method] || isDelegatedCall(ctx) {
+				return handler(ctx, result, method, args...)
+			}
+
+			budget := p.hedgeBudget(group)
+			budget.earn()
+
+			// cancel the slower one once responded
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			respCh := make(chan hedgeResponse, 2)
+			call := func(ctx context.Context, fn providers.CallContextFunc, hedged bool) {
+				var raw json.RawMessage
+				err := fn(ctx, &raw, method, args...)
+				respCh <- hedgeResponse{raw, err, hedged}
+			}
+
+			go call(ctx, handler, false)
+
+			timer := time.NewTimer(hedgeDelay(nodeName))
+			defer timer.Stop()
+
+			select {
+			case resp := <-respCh:
+				metrics.Registry.RPC.FullnodeHedged(group.Space(), method).Mark(false)
+				return decodeHedgeResponse(resp, result)
+			case <-timer.C:
+			}
+
+			inflight := 1
+
+			if budget.spend() {
+				if provider, hedgeNode, ok := p.nextUntriedProvider(ctx, group, map[string]bool{nodeName: true}); ok {
+					logrus.WithFields(logrus.Fields{
+						"method":    method,
+						"node":      nodeName,
+						"hedgeNode": hedgeNode,
+					}).Debug("Hedge upstream call to another fullnode")
+
+					go call(context.WithValue(ctx, ctxKeyDelegated, true), provider.CallContext, true)
+					inflight++
+				}
+			}
+
+			metrics.Registry.RPC.FullnodeHedged(group.Space(), method).Mark(inflight > 1)
+
+			// the first response wins unless failed to request fullnode
+			var resp hedgeResponse
+			for i := 0; i < inflight; i++ {
+				if resp = <-respCh; !isTransportError(resp.err) {
+					break
+				}
+			}
+
+			if inflight > 1 {
+				metrics.Registry.RPC.FullnodeHedgeWon(group.Space(), method).Mark(resp.hedged)
+			}
+
+			return decodeHedgeResponse(resp, result)
+		}
+	}
+}
+
+func (p *clientProvider) hedgeBudget(group Group) *hedgeBudget {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	budget, ok := p.hedgeBudgets[group]
+	if !ok {
+		budget = newHedgeBudget(cfg.Hedge.MaxRatio)
+		p.hedgeBudgets[group] = budget
+	}
+
+	return budget
+}
+
+// hedgeDelay returns the percentile latency of the specified fullnode, which is observed from
+// the upstream calls of this process, so that it's available without node manager running in
+// the same process.
+func hedgeDelay(nodeName string) time.Duration {
+	delay := cfg.Hedge.Delay
+
+	if latency, ok := getNodeLoad(nodeName).percentile(cfg.Hedge.Percentile); ok {
+		delay = latency
+	}
+
+	if delay < cfg.Hedge.MinDelay {
+		delay = cfg.Hedge.MinDelay
+	}
+
+	return delay
+}
+
+func decodeHedgeResponse(resp hedgeResponse, result interface{}) error {
+	if resp.err != nil || result == nil || len(resp.result) == 0 {
+		return resp.err
+	}
+
+	return json.Unmarshal(resp.result, result)
+}
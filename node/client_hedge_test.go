@@ -0,0 +1,126 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedgeBudget(t *testing.T) {
+	budget := newHedgeBudget(0.5)
+
+	budget.earn()
+	assert.False(t, budget.spend())
+
+	budget.earn()
+	assert.True(t, budget.spend())
+	assert.False(t, budget.spend())
+
+	// bounded for burst
+	for i := 0; i < 100; i++ {
+		budget.earn()
+	}
+
+	for i := 0; i < hedgeBudgetBurst; i++ {
+		assert.True(t, budget.spend())
+	}
+
+	assert.False(t, budget.spend())
+}
+
+func TestClientHedgeSlowNode(t *testing.T) {
+	oldConf := cfg.Hedge
+	defer func() { cfg.Hedge = oldConf }()
+
+	cfg.Hedge.Enabled = true
+	cfg.Hedge.Delay = 20 * time.Millisecond
+	cfg.Hedge.MaxRatio = 1
+	hedgeMethods["eth_call"] = true
+
+	fastServer := newTestRpcServer(`"result":"0x2"`)
+	defer fastServer.Close()
+
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slowServer.Close()
+
+	router := &mockRouter{
+		urls: map[string]string{"slow": slowServer.URL},
+		url:  fastServer.URL,
+	}
+
+	provider := NewEthClientProvider(router)
+	provider.registerGroup(GroupEthHttp)
+
+	client, err := provider.getClient("slow", GroupEthHttp)
+	assert.NoError(t, err)
+
+	start := time.Now()
+
+	var result string
+	err = client.(*Web3goClient).Provider().CallContext(context.Background(), &result, "eth_call")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x2", result)
+	assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+}
+
+func TestClientHedgeByObservedLatency(t *testing.T) {
+	oldConf := cfg.Hedge
+	defer func() { cfg.Hedge = oldConf }()
+
+	// hedge delay never falls back to the default one in time
+	cfg.Hedge.Enabled = true
+	cfg.Hedge.Percentile = 0.9
+	cfg.Hedge.Delay = 10 * time.Second
+	cfg.Hedge.MinDelay = time.Millisecond
+	cfg.Hedge.MaxRatio = 1
+	hedgeMethods["eth_call"] = true
+
+	fastServer := newTestRpcServer(`"result":"0x2"`)
+	defer fastServer.Close()
+
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slowServer.Close()
+
+	// latency observed from upstream calls without node manager running
+	slowNode := rpc.Url2NodeName(slowServer.URL)
+	defer nodeLoads.Delete(slowNode)
+
+	for i := 0; i < 10; i++ {
+		getNodeLoad(slowNode).observe(20 * time.Millisecond)
+	}
+
+	assert.Equal(t, 20*time.Millisecond, hedgeDelay(slowNode))
+
+	// hedged to the successor of the request
+	provider := NewEthClientProvider(&keyedSuccessorRouter{successors: map[string][]string{
+		"10.0.0.1": {slowServer.URL, fastServer.URL},
+	}})
+	provider.registerGroup(GroupEthHttp)
+
+	client, err := provider.getClient("10.0.0.1", GroupEthHttp)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	var result string
+	err = client.(*Web3goClient).Provider().CallContext(context.Background(), &result, "eth_call")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x2", result)
+	assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+}
@@ -0,0 +1,254 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	providers "github.com/openweb3/go-rpc-provider/provider_wrapper"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// smoothing factor of the EWMA latency observed from upstream calls
+	loadEwmaAlpha = 0.2
+	// number of random candidates to choose the least loaded one from
+	loadChoices = 2
+	// number of the recent latency samples observed from upstream calls
+	loadLatencySamples = 256
+	// lower bound of availability, so that unavailable fullnode is still chosen at a high cost
+	loadMinAvailability = 0.01
+)
+
+// WeightConfig static weight of fullnode for load balancing, which is 1 by default.
+type WeightConfig struct {
+	Url    string
+	Weight float64
+}
+
+// nodeWeights is the static weight of fullnodes by node name.
+var nodeWeights = make(map[string]float64)
+
+// registeredWeights is the weight of fullnodes registered in node manager by group, which takes
+// precedence over the static one, e.g. updated by `node_update` at runtime.
+var registeredWeights util.ConcurrentMap // group => node name => weight
+
+// setRegisteredWeights replaces the registered weight of fullnodes of the specified group.
+func setRegisteredWeights(group Group, records []NodeRecord) {
+	weights := make(map[string]float64)
+
+	for _, record := range records {
+		if record.Weight > 0 {
+			weights[record.NodeName()] = record.Weight
+		}
+	}
+
+	registeredWeights.Store(group, weights)
+}
+
+// nodeWeight returns the weight of fullnode for load balancing, or 0 if not weighted.
+func nodeWeight(group Group, nodeName string) float64 {
+	if val, ok := registeredWeights.Load(group); ok {
+		if weight, ok := val.(map[string]float64)[nodeName]; ok {
+			return weight
+		}
+	}
+
+	return nodeWeights[nodeName]
+}
+
+// nodeLoad tracks the load of a fullnode observed by RPC client.
+type nodeLoad struct {
+	inflight int64 // number of in-flight upstream calls
+
+	mu      sync.Mutex
+	ewma    float64 // EWMA latency in nanoseconds, 0 if never sampled
+	failure float64 // EWMA ratio of upstream calls failed due to transport error
+
+	samples    [loadLatencySamples]time.Duration // ring buffer of the recent latency samples
+	numSamples int                               // total number of latency samples
+}
+
+func (l *nodeLoad) observe(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ewma == 0 {
+		l.ewma = float64(latency)
+	} else {
+		l.ewma = loadEwmaAlpha*float64(latency) + (1-loadEwmaAlpha)*l.ewma
+	}
+
+	l.samples[l.numSamples%loadLatencySamples] = latency
+	l.numSamples++
+}
+
+// percentile returns the percentile of the recent latency samples, or false if never sampled.
+func (l *nodeLoad) percentile(p float64) (time.Duration, bool) {
+	l.mu.Lock()
+	samples := make([]time.Duration, util.MinInt(l.numSamples, loadLatencySamples))
+	copy(samples, l.samples[:len(samples)])
+	l.mu.Unlock()
+
+	if len(samples) == 0 {
+		return 0, false
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	index := int(math.Ceil(p*float64(len(samples)))) - 1
+	index = util.MaxInt(0, util.MinInt(index, len(samples)-1))
+
+	return samples[index], true
+}
+
+// observeResult observes whether the upstream call failed due to transport error.
+func (l *nodeLoad) observeResult(failed bool) {
+	var sample float64
+	if failed {
+		sample = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.failure = loadEwmaAlpha*sample + (1-loadEwmaAlpha)*l.failure
+}
+
+func (l *nodeLoad) latency() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.ewma
+}
+
+// availability returns the ratio of upstream calls succeeded recently, which is 1 if never sampled.
+func (l *nodeLoad) availability() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return math.Max(1-l.failure, loadMinAvailability)
+}
+
+// nodeLoads is the load of fullnodes by node name, which is shared among groups and spaces
+// since the same fullnode may be configured in multiple groups.
+var nodeLoads util.ConcurrentMap
+
+func getNodeLoad(nodeName string) *nodeLoad {
+	val, _ := nodeLoads.LoadOrStoreFn(nodeName, func(interface{}) interface{} {
+		return &nodeLoad{}
+	})
+
+	return val.(*nodeLoad)
+}
+
+// hookLoad hooks middleware for the RPC client of the specified fullnode to track the load.
+func hookLoad(client interface{}, nodeName string) {
+	if provider := middlewarableProviderOf(client); provider != nil {
+		provider.HookCallContext(middlewareLoad(getNodeLoad(nodeName)))
+	}
+}
+
+func middlewareLoad(load *nodeLoad) providers.CallContextMiddleware {
+	return func(handler providers.CallContextFunc) providers.CallContextFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			atomic.AddInt64(&load.inflight, 1)
+			defer atomic.AddInt64(&load.inflight, -1)
+
+			start := time.Now()
+			err := handler(ctx, result, method, args...)
+
+			// caller canceled, e.g. hedged by another fullnode
+			if ctx.Err() != context.Canceled {
+				load.observe(time.Since(start))
+				load.observeResult(isTransportError(err))
+			}
+
+			return err
+		}
+	}
+}
+
+// getClientByLoad gets client of the least loaded fullnode among the random candidates of the
+// specified group, a.k.a. power of two choices.
+func (p *clientProvider) getClientByLoad(group Group) (interface{}, error) {
+	clients, ok := p.clients[group]
+	if !ok {
+		return nil, errors.Errorf("Unknown node group %v", group)
+	}
+
+	candidates := make([]loadCandidate, 0, loadChoices)
+
+	for i := 0; i < loadChoices; i++ {
+		key := fmt.Sprintf("random_key_%v", rand.Int())
+
+		if url := p.router.Route(group, []byte(key)); len(url) > 0 {
+			cost := nodeLoadCost(group, rpc.Url2NodeName(url))
+			candidates = append(candidates, loadCandidate{url, cost})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].cost < candidates[j].cost
+	})
+
+	// check circuit breaker in order of load cost, so that the probe request of half-open
+	// fullnode won't be consumed unless chosen
+	for _, c := range candidates {
+		if breakerAllow(rpc.Url2NodeName(c.url)) {
+			logger := logrus.WithFields(logrus.Fields{"group": group, "cost": c.cost})
+			return p.getClientByURL(c.url, group, clients, logger)
+		}
+	}
+
+	logrus.WithField("group", group).
+		WithError(ErrClientUnavailable).
+		Error("Failed to get full node client from provider")
+
+	return nil, ErrClientUnavailable
+}
+
+// loadCandidate is the fullnode candidate to choose by load cost.
+type loadCandidate struct {
+	url  string
+	cost float64
+}
+
+// nodeLoadCost evaluates the load cost of fullnode, which is the expected latency scaled by
+// in-flight calls, availability and weight. The lower the better.
+//
+// Note, all the signals are observed from upstream calls of RPC client, rather than sampled by
+// health monitor, which is only available in node manager.
+func nodeLoadCost(group Group, nodeName string) float64 {
+	load := getNodeLoad(nodeName)
+
+	latency := load.latency()
+
+	// penalize the long tail latency
+	if p99, ok := load.percentile(0.99); ok {
+		latency = 0.8*latency + 0.2*float64(p99)
+	}
+
+	// never sampled, regarded as fast to probe it
+	if latency == 0 {
+		latency = float64(time.Millisecond)
+	}
+
+	cost := latency * float64(atomic.LoadInt64(&load.inflight)+1)
+
+	cost /= load.availability()
+
+	if weight := nodeWeight(group, nodeName); weight > 0 {
+		cost /= weight
+	}
+
+	return cost
+}
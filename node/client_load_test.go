@@ -0,0 +1,181 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeLoadCost(t *testing.T) {
+	fast, slow := getNodeLoad("test-fast-node"), getNodeLoad("test-slow-node")
+
+	fast.observe(10 * time.Millisecond)
+	fast.observe(30 * time.Millisecond) // ewma: 0.2 * 30 + 0.8 * 10 = 14ms
+	assert.Equal(t, float64(14*time.Millisecond), fast.latency())
+
+	slow.observe(50 * time.Millisecond)
+	assert.Less(t, nodeLoadCost(GroupEthHttp, "test-fast-node"), nodeLoadCost(GroupEthHttp, "test-slow-node"))
+
+	// too many in-flight requests
+	fast.inflight = 3
+	assert.Greater(t, nodeLoadCost(GroupEthHttp, "test-fast-node"), nodeLoadCost(GroupEthHttp, "test-slow-node"))
+
+	// static weight
+	nodeWeights["test-slow-node"] = 0.5
+	defer delete(nodeWeights, "test-slow-node")
+	assert.Equal(t, float64(100*time.Millisecond), nodeLoadCost(GroupEthHttp, "test-slow-node"))
+}
+
+func TestNodeLoadExcludesRetry(t *testing.T) {
+	oldConf := cfg.Retry
+	defer func() { cfg.Retry = oldConf }()
+
+	cfg.Retry.Enabled = true
+	cfg.Retry.Attempts = 2
+	cfg.Retry.Budget = 3 * time.Second
+
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	defer slowServer.Close()
+
+	deadUrl := "http://127.0.0.1:1/load" // connection refused
+
+	router := &mockRouter{urls: map[string]string{"dead": deadUrl}, url: slowServer.URL}
+	provider := NewEthClientProvider(router)
+	provider.registerGroup(GroupEthHttp)
+
+	client, err := provider.getClient("dead", GroupEthHttp)
+	assert.NoError(t, err)
+
+	// retried on the slow node upon transport error
+	var result string
+	err = client.(*Web3goClient).Provider().CallContext(context.Background(), &result, "eth_blockNumber")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x1", result)
+
+	// latency of the retried call is observed by the slow node rather than the failed one
+	deadLoad := getNodeLoad(rpc.Url2NodeName(deadUrl))
+	assert.Less(t, deadLoad.latency(), float64(200*time.Millisecond))
+	assert.Zero(t, atomic.LoadInt64(&deadLoad.inflight))
+
+	slowLoad := getNodeLoad(rpc.Url2NodeName(slowServer.URL))
+	assert.GreaterOrEqual(t, slowLoad.latency(), float64(200*time.Millisecond))
+}
+
+// roundRobinRouter routes keys to fullnodes in turn.
+type roundRobinRouter struct {
+	urls []string
+	next int
+}
+
+func (r *roundRobinRouter) Route(group Group, key []byte) string {
+	url := r.urls[r.next%len(r.urls)]
+	r.next++
+	return url
+}
+
+func TestClientByLoadKeepsHalfOpenProbe(t *testing.T) {
+	setBreakerConfig(t)
+
+	cheapUrl, probeUrl := "http://test-cheap-node:8545", "http://test-probe-node:8545"
+	cheapNode, probeNode := rpc.Url2NodeName(cheapUrl), rpc.Url2NodeName(probeUrl)
+
+	getNodeLoad(cheapNode).observe(time.Millisecond)
+	getNodeLoad(probeNode).observe(time.Second)
+
+	breaker := getCircuitBreaker("eth", probeNode)
+	defer nodeBreakers.Delete(probeNode)
+	breaker.transit(BreakerHalfOpen, time.Now())
+
+	provider := NewEthClientProvider(&roundRobinRouter{urls: []string{probeUrl, cheapUrl}})
+	provider.registerGroup(GroupEthHttp)
+
+	client, err := provider.getClientByLoad(GroupEthHttp)
+	assert.NoError(t, err)
+	assert.Equal(t, cheapUrl, client.(*Web3goClient).URL)
+
+	// probe request of the half-open fullnode not consumed since not chosen
+	assert.True(t, breaker.allow())
+}
+
+func TestClientByLoadWithRegisteredWeight(t *testing.T) {
+	heavyUrl, lightUrl := "http://test-heavy-node:8545", "http://test-light-node:8545"
+
+	getNodeLoad(rpc.Url2NodeName(heavyUrl)).observe(20 * time.Millisecond)
+	getNodeLoad(rpc.Url2NodeName(lightUrl)).observe(10 * time.Millisecond)
+
+	groupConf := map[Group]UrlConfig{GroupEthHttp: {Nodes: []string{heavyUrl, lightUrl}}}
+	api := mustNewApi(newTestNode, groupConf, newMemoryRegistry())
+	defer registeredWeights.Delete(GroupEthHttp)
+
+	provider := NewEthClientProvider(&roundRobinRouter{urls: []string{heavyUrl, lightUrl}})
+	provider.registerGroup(GroupEthHttp)
+
+	client, err := provider.getClientByLoad(GroupEthHttp)
+	assert.NoError(t, err)
+	assert.Equal(t, lightUrl, client.(*Web3goClient).URL)
+
+	// weight updated at runtime changes the least loaded fullnode
+	weight := 4.0
+	assert.NoError(t, api.Update(context.Background(), GroupEthHttp, heavyUrl, NodeUpdate{Weight: &weight}))
+
+	client, err = provider.getClientByLoad(GroupEthHttp)
+	assert.NoError(t, err)
+	assert.Equal(t, heavyUrl, client.(*Web3goClient).URL)
+}
+
+func TestNodeLoadPercentile(t *testing.T) {
+	var load nodeLoad
+
+	_, ok := load.percentile(0.9)
+	assert.False(t, ok)
+
+	for i := 1; i <= 10; i++ {
+		load.observe(time.Duration(i) * time.Millisecond)
+	}
+
+	latency, ok := load.percentile(0.9)
+	assert.True(t, ok)
+	assert.Equal(t, 9*time.Millisecond, latency)
+
+	latency, _ = load.percentile(0.5)
+	assert.Equal(t, 5*time.Millisecond, latency)
+
+	// only the recent samples kept
+	for i := 0; i < loadLatencySamples; i++ {
+		load.observe(time.Second)
+	}
+
+	latency, _ = load.percentile(0)
+	assert.Equal(t, time.Second, latency)
+}
+
+func TestNodeLoadAvailability(t *testing.T) {
+	healthy, flaky := getNodeLoad("test-healthy-node"), getNodeLoad("test-flaky-node")
+
+	// never sampled
+	assert.Equal(t, float64(1), flaky.availability())
+
+	healthy.observe(20 * time.Millisecond)
+	flaky.observe(10 * time.Millisecond)
+	assert.Less(t, nodeLoadCost(GroupEthHttp, "test-flaky-node"), nodeLoadCost(GroupEthHttp, "test-healthy-node"))
+
+	// faster but failed due to transport error
+	for i := 0; i < 5; i++ {
+		healthy.observeResult(false)
+		flaky.observeResult(true)
+	}
+
+	assert.Equal(t, float64(1), healthy.availability())
+	assert.Less(t, flaky.availability(), 0.5)
+	assert.Greater(t, nodeLoadCost(GroupEthHttp, "test-flaky-node"), nodeLoadCost(GroupEthHttp, "test-healthy-node"))
+}
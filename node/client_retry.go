@@ -0,0 +1,164 @@
+package node
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	providers "github.com/openweb3/go-rpc-provider/provider_wrapper"
+	"github.com/openweb3/go-rpc-provider/utils"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/scroll-tech/rpc-gateway/util/rpc/handlers"
+	"github.com/sirupsen/logrus"
+)
+
+// ctxKeyDelegated marks the upstream call as delegated from another fullnode (e.g. retry or
+// hedge), so that it won't be retried or hedged recursively by the middlewares of this fullnode.
+const ctxKeyDelegated = handlers.CtxKey("Infura-RPC-Delegated")
+
+type retryConfig struct {
+	// whether to retry upstream calls on other fullnodes upon transport error
+	Enabled bool
+	// max number of attempts, including the first call
+	Attempts int `default:"3"`
+	// deadline budget for all the attempts since the first call, no more retry once exceeded
+	Budget time.Duration `default:"10s"`
+	// methods that must never be retried, e.g. non-idempotent or filter bound to fullnode
+	ExcludedMethods []string `default:"[eth_sendRawTransaction,eth_sendTransaction,eth_newFilter,eth_newBlockFilter,eth_newPendingTransactionFilter,eth_getFilterChanges,eth_getFilterLogs,eth_uninstallFilter,cfx_sendRawTransaction,cfx_sendTransaction,cfx_newFilter,cfx_newBlockFilter,cfx_newPendingTransactionFilter,cfx_getFilterChanges,cfx_getFilterLogs,cfx_uninstallFilter]"`
+}
+
+// retryExcludedMethods is the set of methods that must never be retried.
+var retryExcludedMethods = make(map[string]bool)
+
+// hookRetry hooks the retry middleware for the RPC client of the specified fullnode if enabled.
+func (p *clientProvider) hookRetry(client interface{}, group Group, nodeName string) {
+	if !cfg.Retry.Enabled || cfg.Retry.Attempts <= 1 {
+		return
+	}
+
+	if provider := middlewarableProviderOf(client); provider != nil {
+		provider.HookCallContext(p.middlewareRetry(group, nodeName))
+	}
+}
+
+// middlewareRetry retries the upstream call on other fullnodes of the same group if failed due to
+// transport error, e.g. IO error or timeout.
+func (p *clientProvider) middlewareRetry(group Group, nodeName string) providers.CallContextMiddleware {
+	return func(handler providers.CallContextFunc) providers.CallContextFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			start := time.Now()
+
+			err := handler(ctx, result, method, args...)
+			if !shouldRetry(ctx, method, err) {
+				return err
+			}
+
+			retryCtx, cancel := context.WithDeadline(
+				context.WithValue(ctx, ctxKeyDelegated, true), start.Add(cfg.Retry.Budget),
+			)
+			defer cancel()
+
+			failedNode := nodeName
+			tried := map[string]bool{nodeName: true}
+
+			for attempt := 1; attempt < cfg.Retry.Attempts && retryCtx.Err() == nil; attempt++ {
+				provider, nextNode, ok := p.nextUntriedProvider(ctx, group, tried)
+				if !ok {
+					break
+				}
+
+				metrics.Registry.RPC.FullnodeRetry(group.Space(), failedNode).Mark(1)
+
+				logrus.WithFields(logrus.Fields{
+					"method":     method,
+					"failedNode": failedNode,
+					"nextNode":   nextNode,
+					"attempt":    attempt,
+				}).WithError(err).Debug("Retry upstream call on another fullnode due to transport error")
+
+				tried[nextNode] = true
+
+				if err = provider.CallContext(retryCtx, result, method, args...); !isTransportError(err) {
+					return err
+				}
+
+				failedNode = nextNode
+			}
+
+			return err
+		}
+	}
+}
+
+// nextUntriedProvider walks the hash ring successors of the routing key bound to the upstream call,
+// e.g. the remote address of request, for the next untried fullnode of the specified group.
+func (p *clientProvider) nextUntriedProvider(
+	ctx context.Context, group Group, tried map[string]bool,
+) (*providers.MiddlewarableProvider, string, bool) {
+	clients, ok := p.clients[group]
+	if !ok {
+		return nil, "", false
+	}
+
+	router, ok := p.router.(SuccessorRouter)
+	if !ok {
+		return nil, "", false
+	}
+
+	key := routingKeyFromContext(ctx)
+
+	for _, url := range router.RouteSuccessors(group, []byte(key)) {
+		nodeName := rpc.Url2NodeName(url)
+		if tried[nodeName] || !breakerAllow(nodeName) {
+			continue
+		}
+
+		logger := logrus.WithFields(logrus.Fields{"key": key, "group": group})
+
+		client, err := p.getClientByURL(url, group, clients, logger)
+		if err != nil {
+			continue
+		}
+
+		if provider := middlewarableProviderOf(client); provider != nil {
+			return provider, nodeName, true
+		}
+	}
+
+	return nil, "", false
+}
+
+func shouldRetry(ctx context.Context, method string, err error) bool {
+	if !isTransportError(err) || retryExcludedMethods[method] {
+		return false
+	}
+
+	// caller canceled or timed out
+	if ctx.Err() != nil {
+		return false
+	}
+
+	return !isDelegatedCall(ctx)
+}
+
+func isDelegatedCall(ctx context.Context) bool {
+	delegated, _ := ctx.Value(ctxKeyDelegated).(bool)
+	return delegated
+}
+
+// isTransportError returns true if failed to request fullnode rather than RPC error responded.
+func isTransportError(err error) bool {
+	return err != nil && !utils.IsRPCJSONError(err)
+}
+
+func middlewarableProviderOf(client interface{}) *providers.MiddlewarableProvider {
+	switch c := client.(type) {
+	case *Web3goClient:
+		return c.Provider()
+	case *sdk.Client:
+		return c.Provider()
+	default:
+		return nil
+	}
+}
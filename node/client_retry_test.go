@@ -0,0 +1,159 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openweb3/go-rpc-provider/utils"
+	"github.com/scroll-tech/rpc-gateway/util/rpc/handlers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRouter struct {
+	urls map[string]string // key => url
+	url  string            // for random keys
+}
+
+func (r *mockRouter) Route(group Group, key []byte) string {
+	if url, ok := r.urls[string(key)]; ok {
+		return url
+	}
+
+	return r.url
+}
+
+func (r *mockRouter) RouteSuccessors(group Group, key []byte) []string {
+	return []string{r.Route(group, key), r.url}
+}
+
+func newTestRpcServer(result string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,` + result + `}`))
+	}))
+}
+
+func TestClientRetryOnTransportError(t *testing.T) {
+	oldConf := cfg.Retry
+	defer func() { cfg.Retry = oldConf }()
+
+	cfg.Retry.Enabled = true
+	cfg.Retry.Attempts = 2
+	cfg.Retry.Budget = 3 * time.Second
+	retryExcludedMethods["eth_sendRawTransaction"] = true
+
+	okServer := newTestRpcServer(`"result":"0x1"`)
+	defer okServer.Close()
+
+	rpcErrServer := newTestRpcServer(`"error":{"code":-32000,"message":"execution reverted"}`)
+	defer rpcErrServer.Close()
+
+	deadUrl := "http://127.0.0.1:1" // connection refused
+
+	router := &mockRouter{
+		urls: map[string]string{"dead": deadUrl, "rpcErr": rpcErrServer.URL},
+		url:  okServer.URL,
+	}
+
+	provider := NewEthClientProvider(router)
+	provider.registerGroup(GroupEthHttp)
+
+	getClient := func(key string) *Web3goClient {
+		client, err := provider.getClient(key, GroupEthHttp)
+		assert.NoError(t, err)
+		return client.(*Web3goClient)
+	}
+
+	// retried on another node upon transport error
+	var result string
+	err := getClient("dead").Provider().CallContext(context.Background(), &result, "eth_blockNumber")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x1", result)
+
+	// never retried for excluded methods
+	err = getClient("dead").Provider().CallContext(context.Background(), &result, "eth_sendRawTransaction", "0x00")
+	assert.Error(t, err)
+	assert.False(t, utils.IsRPCJSONError(err))
+
+	// never retried upon RPC error
+	err = getClient("rpcErr").Provider().CallContext(context.Background(), &result, "eth_call")
+	assert.True(t, utils.IsRPCJSONError(err))
+}
+
+// keyedSuccessorRouter routes successors by key, e.g. hash ring.
+type keyedSuccessorRouter struct {
+	successors map[string][]string // key => urls
+}
+
+func (r *keyedSuccessorRouter) Route(group Group, key []byte) string {
+	return r.successors[string(key)][0]
+}
+
+func (r *keyedSuccessorRouter) RouteSuccessors(group Group, key []byte) []string {
+	return r.successors[string(key)]
+}
+
+func TestClientRetryOnSuccessorsOfRequest(t *testing.T) {
+	oldConf := cfg.Retry
+	defer func() { cfg.Retry = oldConf }()
+
+	cfg.Retry.Enabled = true
+	cfg.Retry.Attempts = 2
+	cfg.Retry.Budget = 3 * time.Second
+
+	server1 := newTestRpcServer(`"result":"0x1"`)
+	defer server1.Close()
+
+	server2 := newTestRpcServer(`"result":"0x2"`)
+	defer server2.Close()
+
+	deadUrl := "http://127.0.0.1:1" // connection refused
+
+	// both clients routed to the dead fullnode, but with different successors
+	provider := NewEthClientProvider(&keyedSuccessorRouter{successors: map[string][]string{
+		"10.0.0.1": {deadUrl, server1.URL, server2.URL},
+		"10.0.0.2": {deadUrl, server2.URL, server1.URL},
+	}})
+	provider.registerGroup(GroupEthHttp)
+
+	blockNumberOf := func(ip string) uint64 {
+		ctx := context.WithValue(context.Background(), handlers.CtxKeyRealIP, ip)
+
+		client, err := provider.GetClientByIP(ctx)
+		require.NoError(t, err)
+
+		// typed client calls with background context
+		blockNum, err := client.Eth.BlockNumber()
+		require.NoError(t, err)
+
+		return blockNum.Uint64()
+	}
+
+	// retried on the successor of each client
+	assert.Equal(t, uint64(1), blockNumberOf("10.0.0.1"))
+	assert.Equal(t, uint64(2), blockNumberOf("10.0.0.2"))
+}
+
+func TestChainedRouterSuccessorsFallback(t *testing.T) {
+	nodes := []string{"http://node1:8545", "http://node2:8545", "http://node3:8545"}
+	router := NewChainedRouter(map[Group]UrlConfig{GroupEthHttp: {Nodes: nodes}}, &roundRobinRouter{urls: nodes})
+
+	// fall back to configured fullnodes if no router supports to route successors
+	urls := router.(SuccessorRouter).RouteSuccessors(GroupEthHttp, []byte("key"))
+	assert.ElementsMatch(t, nodes, urls)
+
+	// stable order for the same key
+	assert.Equal(t, urls, router.(SuccessorRouter).RouteSuccessors(GroupEthHttp, []byte("key")))
+
+	assert.Empty(t, router.(SuccessorRouter).RouteSuccessors(GroupEthWs, []byte("key")))
+}
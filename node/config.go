@@ -1,10 +1,12 @@
 package node
 
 import (
+	"strings"
 	"time"
 
 	"github.com/Conflux-Chain/go-conflux-util/viper"
 	"github.com/buraksezer/consistent"
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
 	"github.com/sirupsen/logrus"
 )
 
@@ -47,11 +49,70 @@ func init() {
 		GroupEthLogs: {
 			Nodes: cfg.EthLogNodes,
 		},
+		GroupEthArchives: {
+			Nodes: cfg.EthArchiveNodes,
+		},
 		GroupDebugHttp: {
 			Nodes: cfg.DebugURLs,
 		},
 	}
 
+	mustLoadUserDefinedGroups()
+
+	for _, wc := range cfg.Weights {
+		nodeWeights[rpc.Url2NodeName(wc.Url)] = wc.Weight
+	}
+
+	for _, method := range cfg.Retry.ExcludedMethods {
+		retryExcludedMethods[method] = true
+	}
+
+	for _, method := range cfg.Hedge.Methods {
+		hedgeMethods[method] = true
+	}
+
+	cfxRoutingTable = mustNewRoutingTable(cfg.Routing.Cfx, cfxBuiltinRoutes, GroupCfxHttp, urlCfg)
+	ethRoutingTable = mustNewRoutingTable(cfg.Routing.Eth, ethBuiltinRoutes, GroupEthHttp, ethUrlCfg)
+}
+
+// mustLoadUserDefinedGroups adds user defined node groups into the group configurations of
+// core space or evm space by group name prefix.
+func mustLoadUserDefinedGroups() {
+	for name, conf := range cfg.Groups {
+		group := Group(strings.ToLower(name))
+
+		var groupConf map[Group]UrlConfig
+		switch {
+		case strings.HasPrefix(string(group), "cfx"):
+			groupConf = urlCfg
+		case strings.HasPrefix(string(group), "eth"):
+			groupConf = ethUrlCfg
+		default:
+			logrus.WithField("group", name).Fatal("Node group name must begin with `cfx` or `eth`")
+		}
+
+		if _, ok := groupConf[group]; ok {
+			logrus.WithField("group", name).Fatal("Node group conflicts with the built-in one")
+		}
+
+		groupConf[group] = conf
+	}
+}
+
+func mustNewRoutingTable(
+	config RoutingConfig, builtins []RouteConfig, defaultGroup Group, groupConf map[Group]UrlConfig,
+) *RoutingTable {
+	// group names are case insensitive as viper does
+	for i := range config.Routes {
+		config.Routes[i].Group = Group(strings.ToLower(string(config.Routes[i].Group)))
+	}
+
+	table, err := NewRoutingTable(config, builtins, defaultGroup, groupConf)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create RPC routing table")
+	}
+
+	return table
 }
 
 type config struct {
@@ -65,7 +126,21 @@ type config struct {
 	LogNodes     []string
 	EthLogNodes  []string
 	ArchiveNodes []string
-	HashRing     struct {
+	// evm space archive fullnodes to serve historical state queries
+	EthArchiveNodes []string
+	// user defined node groups, whose names must begin with `cfx` or `eth` as space
+	Groups  map[string]UrlConfig
+	Routing struct {
+		Cfx RoutingConfig
+		Eth RoutingConfig
+	}
+	Weights  []WeightConfig
+	Registry RegistryConfig
+	Auth     authConfig
+	Retry    retryConfig
+	Hedge    hedgeConfig
+	Breaker  breakerConfig
+	HashRing struct {
 		PartitionCount    int     `default:"15739"`
 		ReplicationFactor int     `default:"51"`
 		Load              float64 `default:"1.25"`
@@ -82,11 +157,18 @@ type config struct {
 			RemindInterval time.Duration `default:"5m"`
 			SuccessCounter uint64        `default:"60"`
 		}
+		Probe struct {
+			Interval time.Duration `default:"30s"`
+			Timeout  time.Duration `default:"3s"`
+			// probes by node group
+			Groups map[string]probeConfig
+		}
 	}
 	Router struct {
 		RedisURL        string
 		NodeRPCURL      string
 		EthNodeRPCURL   string
+		NodeRpcAuth     clientAuthConfig
 		ChainedFailover struct {
 			URL      string
 			WSURL    string
@@ -48,8 +48,32 @@ func (p *EthClientProvider) GetClientByIP(ctx context.Context) (*Web3goClient, e
 func (p *EthClientProvider) GetClientByIPGroup(ctx context.Context, group Group) (*Web3goClient, error) {
 	remoteAddr := remoteAddrFromContext(ctx)
 	client, err := p.getClient(remoteAddr, group)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.(*Web3goClient), nil
+}
+
+// GetClientByLoad gets client of the least loaded fullnode in specific group.
+func (p *EthClientProvider) GetClientByLoad(group Group) (*Web3goClient, error) {
+	client, err := p.getClientByLoad(group)
+	if err != nil {
+		return nil, err
+	}
 
-	return client.(*Web3goClient), err
+	return client.(*Web3goClient), nil
+}
+
+// GetClientByNodeName gets client of the specified fullnode in specific group, e.g. the one that
+// transaction relayed to.
+func (p *EthClientProvider) GetClientByNodeName(group Group, nodeName string) (*Web3goClient, error) {
+	client, err := p.getClientByNodeName(group, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.(*Web3goClient), nil
 }
 
 func (p *EthClientProvider) GetClientRandom() (*Web3goClient, error) {
@@ -59,6 +83,9 @@ func (p *EthClientProvider) GetClientRandom() (*Web3goClient, error) {
 func (p *EthClientProvider) GetClientRandomByGroup(group Group) (*Web3goClient, error) {
 	key := fmt.Sprintf("random_key_%v", rand.Int())
 	client, err := p.getClient(key, group)
+	if err != nil {
+		return nil, err
+	}
 
-	return client.(*Web3goClient), err
+	return client.(*Web3goClient), nil
 }
@@ -1,9 +1,11 @@
 package node
 
 import (
+	"context"
 	"sync"
 
 	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/sirupsen/logrus"
 )
 
 var (
@@ -59,12 +61,27 @@ func newFactory(nf nodeFactory, rpcSrvEndpoint string, groupConf map[Group]UrlCo
 	}
 }
 
-// CreatRpcServer creates node manager RPC server
-func (f *factory) CreatRpcServer() (*rpc.Server, string) {
-	return NewServer(f.nodeFactory, f.groupConf), f.rpcSrvEndpoint
+// CreatRpcServer creates node manager RPC server, with optional node registry to persist nodes.
+func (f *factory) CreatRpcServer(registry Registry) (*rpc.Server, string) {
+	return NewServer(f.nodeFactory, f.groupConf, registry), f.rpcSrvEndpoint
+}
+
+// CreateSyncStatusProvider creates provider to query the sync status of fullnodes tracked by
+// node manager, or returns false if node manager RPC not configured.
+func (f *factory) CreateSyncStatusProvider() (SyncStatusProvider, bool) {
+	if len(f.nodeRpcUrl) == 0 {
+		return nil, false
+	}
+
+	client, err := DialNodeRPC(f.nodeRpcUrl)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create node manager rpc client")
+	}
+
+	return NewNodeRpcSyncStatusProvider(client), true
 }
 
 // CreateRouter creates node router
-func (f *factory) CreateRouter() Router {
-	return MustNewRouter(cfg.Router.RedisURL, f.nodeRpcUrl, f.groupConf)
+func (f *factory) CreateRouter(ctx context.Context) Router {
+	return MustNewRouter(ctx, cfg.Router.RedisURL, f.nodeRpcUrl, f.groupConf)
 }
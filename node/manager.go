@@ -82,6 +82,7 @@ func (m *Manager) Remove(url string) {
 		delete(m.nodes, nodeName)
 		delete(m.nodeName2Epochs, nodeName)
 		m.hashRing.Remove(nodeName)
+		m.resolver.Invalidate(nodeName)
 	}
 }
 
@@ -145,6 +146,15 @@ func (m *Manager) Distribute(key []byte) Node {
 	return node
 }
 
+// RouteSuccessors returns the full node URLs in hash ring order beginning with the one that the
+// specified key located on.
+func (m *Manager) RouteSuccessors(key []byte) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return ringSuccessors(m.hashRing, key)
+}
+
 // Route implements the Router interface.
 func (m *Manager) Route(key []byte) string {
 	if n := m.Distribute(key); n != nil {
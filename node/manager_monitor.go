@@ -50,7 +50,8 @@ func (m *Manager) ReportUnhealthy(nodeName string, remind bool, reason error) {
 	// remove unhealthy node from hash ring
 	m.hashRing.Remove(nodeName)
 
-	// FIXME update repartition cache if configured
+	// evict keys resolved to the unhealthy node, so as to distribute to other nodes
+	m.resolver.Invalidate(nodeName)
 }
 
 // ReportHealthy reports healthy status of managed node to manager.
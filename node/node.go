@@ -17,6 +17,9 @@ import (
 var (
 	_ Node = (*CfxNode)(nil)
 	_ Node = (*EthNode)(nil)
+
+	_ RpcCaller = (*CfxNode)(nil)
+	_ RpcCaller = (*EthNode)(nil)
 )
 
 // Node represents a full node with friendly name and health status.
@@ -123,6 +126,11 @@ func (n *EthNode) LatestEpochNumber() (uint64, error) {
 	return block.Uint64(), nil
 }
 
+// CallContext implements the RpcCaller interface.
+func (n *EthNode) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return n.Provider().CallContext(ctx, result, method, args...)
+}
+
 // CfxNode represents a core space fullnode with friendly name and health status.
 type CfxNode struct {
 	sdk.ClientOperator
@@ -161,6 +169,15 @@ func (n *CfxNode) LatestEpochNumber() (uint64, error) {
 	return epoch.ToInt().Uint64(), nil
 }
 
+// CallContext implements the RpcCaller interface.
+func (n *CfxNode) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if client, ok := n.ClientOperator.(*sdk.Client); ok {
+		return client.Provider().CallContext(ctx, result, method, args...)
+	}
+
+	return n.CallRPC(result, method, args...)
+}
+
 func (n *CfxNode) Close() {
 	n.baseNode.Close()
 	n.ClientOperator.Close()
@@ -0,0 +1,237 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// RpcCaller is implemented by fullnode to call arbitrary JSON-RPC method.
+type RpcCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// Probe checks the health of fullnode in addition to the latest epoch heartbeat, e.g. chain ID
+// or syncing state.
+type Probe interface {
+	// Name returns the probe name for diagnosis.
+	Name() string
+
+	// Probe returns the failure reason if fullnode is unhealthy.
+	Probe(ctx context.Context, caller RpcCaller) error
+}
+
+type customProbeConfig struct {
+	// JSON-RPC method to call
+	Method string
+	// JSON-RPC params
+	Params []interface{}
+	// expected result, or any result is fine if nil
+	Result interface{}
+}
+
+type probeConfig struct {
+	// expected chain ID, skip if 0
+	ChainId uint64
+	// whether fullnode must not be syncing, only supported in evm space
+	Syncing bool
+	// min number of peers, skip if 0, only supported in evm space
+	MinPeers uint64
+	// arbitrary JSON-RPC probes with expected result
+	Custom []customProbeConfig
+}
+
+var (
+	// probes registered by code in addition to the configured ones
+	extraProbes   = make(map[Group][]Probe)
+	extraProbesMu sync.Mutex
+)
+
+// RegisterProbe registers custom probe for fullnodes of the specified group, which should be
+// called before node manager started.
+func RegisterProbe(group Group, probe Probe) {
+	extraProbesMu.Lock()
+	defer extraProbesMu.Unlock()
+
+	extraProbes[group] = append(extraProbes[group], probe)
+}
+
+// newGroupProbes creates the configured and registered probes for the specified group.
+func newGroupProbes(group Group) []Probe {
+	var probes []Probe
+
+	// viper keys are case insensitive
+	if conf, ok := cfg.Monitor.Probe.Groups[strings.ToLower(group.String())]; ok {
+		probes = newConfiguredProbes(group, conf)
+	}
+
+	extraProbesMu.Lock()
+	defer extraProbesMu.Unlock()
+
+	return append(probes, extraProbes[group]...)
+}
+
+func newConfiguredProbes(group Group, conf probeConfig) []Probe {
+	var probes []Probe
+
+	if conf.ChainId > 0 {
+		probes = append(probes, &chainIdProbe{space: group.Space(), expected: conf.ChainId})
+	}
+
+	if conf.Syncing || conf.MinPeers > 0 {
+		if group.Space() != "eth" {
+			logrus.WithField("group", group).Warn("Syncing and peer count probes are only supported in evm space")
+		} else {
+			if conf.Syncing {
+				probes = append(probes, &syncingProbe{})
+			}
+
+			if conf.MinPeers > 0 {
+				probes = append(probes, &peerCountProbe{minPeers: conf.MinPeers})
+			}
+		}
+	}
+
+	for _, c := range conf.Custom {
+		probes = append(probes, newCustomProbe(c))
+	}
+
+	return probes
+}
+
+// chainIdProbe checks the chain ID of fullnode, in case of misconfigured to another chain.
+type chainIdProbe struct {
+	space    string
+	expected uint64
+}
+
+func (p *chainIdProbe) Name() string { return "chainId" }
+
+func (p *chainIdProbe) Probe(ctx context.Context, caller RpcCaller) error {
+	var chainId hexutil.Uint64
+
+	if p.space == "eth" {
+		if err := caller.CallContext(ctx, &chainId, "eth_chainId"); err != nil {
+			return err
+		}
+	} else {
+		var status struct {
+			ChainId hexutil.Uint64 `json:"chainId"`
+		}
+
+		if err := caller.CallContext(ctx, &status, "cfx_getStatus"); err != nil {
+			return err
+		}
+
+		chainId = status.ChainId
+	}
+
+	if uint64(chainId) != p.expected {
+		return errors.Errorf("chain ID mismatch (expected %v, actual %v)", p.expected, uint64(chainId))
+	}
+
+	return nil
+}
+
+// syncingProbe checks if fullnode is still syncing.
+type syncingProbe struct{}
+
+func (p *syncingProbe) Name() string { return "syncing" }
+
+func (p *syncingProbe) Probe(ctx context.Context, caller RpcCaller) error {
+	var result json.RawMessage
+	if err := caller.CallContext(ctx, &result, "eth_syncing"); err != nil {
+		return err
+	}
+
+	// false if not syncing, otherwise syncing progress object
+	if string(result) != "false" {
+		return errors.Errorf("still syncing (%v)", string(result))
+	}
+
+	return nil
+}
+
+// peerCountProbe checks if fullnode has enough peers.
+type peerCountProbe struct {
+	minPeers uint64
+}
+
+func (p *peerCountProbe) Name() string { return "peerCount" }
+
+func (p *peerCountProbe) Probe(ctx context.Context, caller RpcCaller) error {
+	var peers hexutil.Uint64
+	if err := caller.CallContext(ctx, &peers, "net_peerCount"); err != nil {
+		return err
+	}
+
+	if uint64(peers) < p.minPeers {
+		return errors.Errorf("too few peers (%v)", uint64(peers))
+	}
+
+	return nil
+}
+
+// customProbe calls the configured JSON-RPC method and compares with the expected result.
+type customProbe struct {
+	method   string
+	params   []interface{}
+	expected interface{} // normalized in JSON way
+}
+
+func newCustomProbe(conf customProbeConfig) *customProbe {
+	probe := customProbe{method: conf.Method, params: conf.Params}
+
+	if conf.Result != nil {
+		// normalize to compare with the result decoded from JSON, e.g. int vs float64
+		data, err := json.Marshal(conf.Result)
+		if err == nil {
+			err = json.Unmarshal(data, &probe.expected)
+		}
+
+		if err != nil {
+			logrus.WithError(err).WithField("method", conf.Method).Fatal("Invalid expected result of custom probe")
+		}
+	}
+
+	return &probe
+}
+
+func (p *customProbe) Name() string { return p.method }
+
+func (p *customProbe) Probe(ctx context.Context, caller RpcCaller) error {
+	var result interface{}
+	if err := caller.CallContext(ctx, &result, p.method, p.params...); err != nil {
+		return err
+	}
+
+	if p.expected != nil && !reflect.DeepEqual(p.expected, result) {
+		return errors.Errorf("unexpected result (%v)", result)
+	}
+
+	return nil
+}
+
+// runProbes runs all the probes against fullnode, and returns the failure reasons if any.
+func runProbes(probes []Probe, caller RpcCaller, timeout time.Duration) []error {
+	var failures []error
+
+	for _, p := range probes {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := p.Probe(ctx, caller)
+		cancel()
+
+		if err != nil {
+			failures = append(failures, errors.WithMessagef(err, "probe %v", p.Name()))
+		}
+	}
+
+	return failures
+}
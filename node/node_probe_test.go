@@ -0,0 +1,74 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCaller responds raw JSON results by method.
+type mockCaller map[string]string
+
+func (c mockCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	raw, ok := c[method]
+	if !ok {
+		return errors.Errorf("method %v not found", method)
+	}
+
+	return json.Unmarshal([]byte(raw), result)
+}
+
+func TestProbes(t *testing.T) {
+	healthy := mockCaller{
+		"eth_chainId":        `"0x47"`,
+		"cfx_getStatus":      `{"chainId":"0x1"}`,
+		"eth_syncing":        `false`,
+		"net_peerCount":      `"0x5"`,
+		"web3_clientVersion": `"geth/v1.10"`,
+	}
+
+	unhealthy := mockCaller{
+		"eth_chainId":   `"0x1"`,
+		"cfx_getStatus": `{"chainId":"0x2"}`,
+		"eth_syncing":   `{"currentBlock":"0x1","highestBlock":"0x10"}`,
+		"net_peerCount": `"0x0"`,
+	}
+
+	ethProbes := newConfiguredProbes(GroupEthHttp, probeConfig{
+		ChainId:  71,
+		Syncing:  true,
+		MinPeers: 1,
+		Custom: []customProbeConfig{
+			{Method: "web3_clientVersion", Result: "geth/v1.10"},
+		},
+	})
+	assert.Len(t, ethProbes, 4)
+
+	assert.Empty(t, runProbes(ethProbes, healthy, time.Second))
+	assert.Len(t, runProbes(ethProbes, unhealthy, time.Second), 4)
+
+	// syncing and peer count probes are not supported in core space
+	cfxProbes := newConfiguredProbes(GroupCfxHttp, probeConfig{ChainId: 1, Syncing: true, MinPeers: 1})
+	assert.Len(t, cfxProbes, 1)
+
+	assert.Empty(t, runProbes(cfxProbes, healthy, time.Second))
+	assert.Len(t, runProbes(cfxProbes, unhealthy, time.Second), 1)
+}
+
+func TestCheckHealthWithProbeFailures(t *testing.T) {
+	status := NewStatus(GroupEthHttp, "node1")
+	defer status.Close()
+
+	assert.NoError(t, status.checkHealth(0))
+
+	status.probeFailures = []error{errors.New("probe chainId: chain ID mismatch")}
+	assert.Error(t, status.checkHealth(0))
+
+	data, err := status.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "chain ID mismatch")
+}
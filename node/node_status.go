@@ -3,6 +3,7 @@ package node
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -41,6 +42,10 @@ type Status struct {
 	unhealthReportAt time.Time
 
 	latestHeartBeatErrs *ring.Ring
+
+	probes        []Probe
+	probeFailures []error // failures of the latest probes
+	probedAt      time.Time
 }
 
 func NewStatus(group Group, nodeName string) Status {
@@ -54,12 +59,14 @@ func NewStatus(group Group, nodeName string) Status {
 			metrics.Registry.Nodes.NodeAvailability(group.Space(), group.String(), nodeName),
 		),
 		latestHeartBeatErrs: hbErrRingBuf,
+		probes:              newGroupProbes(group),
 	}
 }
 
 // Update heartbeats with node and updates health status.
 func (s *Status) Update(n Node, monitor HealthMonitor) {
 	s.heartbeat(n)
+	s.probe(n)
 	s.updateHealth(monitor)
 }
 
@@ -81,6 +88,8 @@ func (s *Status) MarshalJSON() ([]byte, error) {
 		UnhealthReportAt string `json:"unhealthReportAt"`
 
 		LatestHeartBeatErrs []string `json:"latestHeartBeatErrs"`
+		ProbeFailures       []string `json:"probeFailures"`
+		CircuitBreaker      string   `json:"circuitBreaker,omitempty"`
 	}
 
 	availability := metrics.GetOrRegisterTimeWindowPercentageDefault(s.metric.availability).Value()
@@ -104,6 +113,14 @@ func (s *Status) MarshalJSON() ([]byte, error) {
 		scopy.LatestHeartBeatErrs = append(scopy.LatestHeartBeatErrs, e.(error).Error())
 	}
 
+	for _, e := range s.probeFailures {
+		scopy.ProbeFailures = append(scopy.ProbeFailures, e.Error())
+	}
+
+	if state, ok := BreakerStateOf(s.nodeName); ok {
+		scopy.CircuitBreaker = state.String()
+	}
+
 	return json.Marshal(&scopy)
 }
 
@@ -127,6 +144,25 @@ func (s *Status) heartbeat(n Node) {
 	}
 }
 
+// probe runs the health probes with node periodically to update status.
+func (s *Status) probe(n Node) {
+	if len(s.probes) == 0 || time.Since(s.probedAt) < cfg.Monitor.Probe.Interval {
+		return
+	}
+
+	caller, ok := n.(RpcCaller)
+	if !ok {
+		return
+	}
+
+	s.probedAt = time.Now()
+	s.probeFailures = runProbes(s.probes, caller, cfg.Monitor.Probe.Timeout)
+
+	for _, err := range s.probeFailures {
+		logrus.WithField("status", s).WithError(err).Info("Failed to probe node")
+	}
+}
+
 // updateHealth reports health status to monitor.
 func (s *Status) updateHealth(monitor HealthMonitor) {
 	reason := s.checkHealth(monitor.HealthyEpoch())
@@ -166,6 +202,16 @@ func (s *Status) checkHealth(targetEpoch uint64) error {
 		return errors.Errorf("RPC failures (%v)", s.failureCounter)
 	}
 
+	// health probe failures, e.g. chain ID mismatch
+	if len(s.probeFailures) > 0 {
+		var reasons []string
+		for _, e := range s.probeFailures {
+			reasons = append(reasons, e.Error())
+		}
+
+		return errors.Errorf("Probe failures (%v)", strings.Join(reasons, "; "))
+	}
+
 	// epoch fall behind
 	if s.latestStateEpoch+cfg.Monitor.Unhealth.EpochsFallBehind < targetEpoch {
 		return errors.Errorf("Epoch fall behind (%v)", targetEpoch-s.latestStateEpoch)
@@ -0,0 +1,56 @@
+package node
+
+import (
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+// SyncStatus represents the sync status of fullnode tracked by node manager. Fullnode is regarded
+// as syncing if unhealthy or fall behind the healthy epoch of the node group.
+type SyncStatus struct {
+	Syncing      bool   `json:"syncing"`
+	CurrentBlock uint64 `json:"currentBlock"`
+	HighestBlock uint64 `json:"highestBlock"`
+}
+
+// SyncStatus returns the sync status against the healthy epoch of the node group.
+func (s *Status) SyncStatus(healthyEpoch uint64) SyncStatus {
+	highest := healthyEpoch
+	if s.latestStateEpoch > highest {
+		highest = s.latestStateEpoch
+	}
+
+	return SyncStatus{
+		Syncing:      s.unhealthy || s.latestStateEpoch+cfg.Monitor.Unhealth.EpochsFallBehind < healthyEpoch,
+		CurrentBlock: s.latestStateEpoch,
+		HighestBlock: highest,
+	}
+}
+
+// SyncStatusProvider provides the sync status of fullnodes tracked by node manager.
+type SyncStatusProvider interface {
+	// SyncStatus returns the sync status of the specified fullnode.
+	SyncStatus(group Group, url string) (*SyncStatus, error)
+}
+
+// NodeRpcSyncStatusProvider queries the sync status of fullnodes from node manager RPC.
+type NodeRpcSyncStatusProvider struct {
+	client *rpc.Client
+}
+
+func NewNodeRpcSyncStatusProvider(client *rpc.Client) *NodeRpcSyncStatusProvider {
+	return &NodeRpcSyncStatusProvider{client: client}
+}
+
+func (p *NodeRpcSyncStatusProvider) SyncStatus(group Group, url string) (*SyncStatus, error) {
+	var status *SyncStatus
+	if err := p.client.Call(&status, "node_syncing", group, url); err != nil {
+		return nil, err
+	}
+
+	if status == nil {
+		return nil, errors.Errorf("node %v not managed in group %v", url, group)
+	}
+
+	return status, nil
+}
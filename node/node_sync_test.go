@@ -0,0 +1,30 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusSyncStatus(t *testing.T) {
+	oldFallBehind := cfg.Monitor.Unhealth.EpochsFallBehind
+	defer func() { cfg.Monitor.Unhealth.EpochsFallBehind = oldFallBehind }()
+
+	cfg.Monitor.Unhealth.EpochsFallBehind = 5
+
+	// within tolerance of the healthy epoch
+	status := Status{latestStateEpoch: 96}
+	assert.Equal(t, SyncStatus{Syncing: false, CurrentBlock: 96, HighestBlock: 100}, status.SyncStatus(100))
+
+	// ahead of the healthy epoch
+	status = Status{latestStateEpoch: 102}
+	assert.Equal(t, SyncStatus{Syncing: false, CurrentBlock: 102, HighestBlock: 102}, status.SyncStatus(100))
+
+	// fall behind too much
+	status = Status{latestStateEpoch: 90}
+	assert.Equal(t, SyncStatus{Syncing: true, CurrentBlock: 90, HighestBlock: 100}, status.SyncStatus(100))
+
+	// unhealthy
+	status = Status{latestStateEpoch: 100, unhealthy: true}
+	assert.True(t, status.SyncStatus(100).Syncing)
+}
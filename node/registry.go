@@ -0,0 +1,123 @@
+package node
+
+import (
+	"sort"
+
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	RegistryStorageRedis = "redis"
+	RegistryStorageMysql = "mysql"
+)
+
+// RegistryConfig node registry configurations.
+type RegistryConfig struct {
+	// persistent storage of node registry, either `redis` or `mysql`, disabled if empty
+	Storage string
+	// redis URL if persisted in redis, e.g. redis://<user>:<password>@<host>:<port>/<db_number>
+	RedisURL string
+}
+
+// NodeRegistryConfig returns the node registry configurations loaded from viper.
+func NodeRegistryConfig() RegistryConfig {
+	return cfg.Registry
+}
+
+// NodeRecord represents a fullnode persisted in node registry.
+type NodeRecord struct {
+	Group    Group             `json:"group"`
+	Url      string            `json:"url"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Weight   float64           `json:"weight,omitempty"`
+	Disabled bool              `json:"disabled"`
+}
+
+// NodeName returns the node name of fullnode URL.
+func (r *NodeRecord) NodeName() string {
+	return rpc.Url2NodeName(r.Url)
+}
+
+// NodeUpdate represents the fields to update for a registered fullnode, nil to keep unchanged.
+type NodeUpdate struct {
+	Labels   map[string]string `json:"labels"`
+	Weight   *float64          `json:"weight"`
+	Disabled *bool             `json:"disabled"`
+}
+
+// Registry is implemented by any persistent storage of fullnodes managed by node manager, so
+// that dynamically added or removed fullnodes survive the restart of node manager.
+type Registry interface {
+	// Load loads all the registered fullnodes.
+	Load() ([]NodeRecord, error)
+
+	// Put adds or overwrites the registered fullnode of the same group and node name.
+	Put(record NodeRecord) error
+
+	// Delete removes the registered fullnode, and it's fine if not registered.
+	Delete(group Group, url string) error
+}
+
+// noopRegistry registry that persists nothing.
+type noopRegistry struct{}
+
+func (r *noopRegistry) Load() ([]NodeRecord, error)          { return nil, nil }
+func (r *noopRegistry) Put(record NodeRecord) error          { return nil }
+func (r *noopRegistry) Delete(group Group, url string) error { return nil }
+
+// mergeRegistry merges the registered fullnodes with the configured ones, and returns node
+// records by group. Registered fullnodes take precedence over the configured ones of the same
+// node name, e.g. disabled to remove a configured fullnode.
+func mergeRegistry(groupConf map[Group]UrlConfig, records []NodeRecord) map[Group][]NodeRecord {
+	group2Records := make(map[Group]map[string]NodeRecord)
+
+	for group, conf := range groupConf {
+		group2Records[group] = make(map[string]NodeRecord)
+
+		for _, url := range conf.Nodes {
+			record := NodeRecord{Group: group, Url: url}
+			group2Records[group][record.NodeName()] = record
+		}
+	}
+
+	for _, record := range records {
+		nodes, ok := group2Records[record.Group]
+		if !ok {
+			logrus.WithField("record", record).Warn("Ignore registered fullnode of unknown group")
+			continue
+		}
+
+		nodes[record.NodeName()] = record
+	}
+
+	result := make(map[Group][]NodeRecord)
+
+	for group, nodes := range group2Records {
+		result[group] = []NodeRecord{}
+
+		for _, record := range nodes {
+			result[group] = append(result[group], record)
+		}
+
+		// in deterministic order
+		sort.Slice(result[group], func(i, j int) bool {
+			return result[group][i].Url < result[group][j].Url
+		})
+	}
+
+	return result
+}
+
+// enabledUrls returns URLs of the enabled fullnodes.
+func enabledUrls(records []NodeRecord) []string {
+	var urls []string
+
+	for _, record := range records {
+		if !record.Disabled {
+			urls = append(urls, record.Url)
+		}
+	}
+
+	return urls
+}
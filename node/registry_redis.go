@@ -0,0 +1,73 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
+)
+
+// RedisRegistry implements Registry to persist fullnodes in a redis hash.
+type RedisRegistry struct {
+	client *redis.Client
+	ctx    context.Context
+	key    string
+}
+
+func NewRedisRegistry(client *redis.Client, keyPrefix string) *RedisRegistry {
+	return &RedisRegistry{
+		client: client,
+		ctx:    context.Background(),
+		key:    fmt.Sprintf("node:registry:%v", keyPrefix),
+	}
+}
+
+func (r *RedisRegistry) Load() ([]NodeRecord, error) {
+	values, err := r.client.HGetAll(r.ctx, r.key).Result()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load node registry from redis")
+	}
+
+	var records []NodeRecord
+
+	for field, value := range values {
+		var record NodeRecord
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			return nil, errors.WithMessagef(err, "failed to decode node record %v", field)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func (r *RedisRegistry) Put(record NodeRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return errors.WithMessage(err, "failed to encode node record")
+	}
+
+	field := redisRegistryField(record.Group, record.Url)
+	if err := r.client.HSet(r.ctx, r.key, field, value).Err(); err != nil {
+		return errors.WithMessage(err, "failed to write node record to redis")
+	}
+
+	return nil
+}
+
+func (r *RedisRegistry) Delete(group Group, url string) error {
+	field := redisRegistryField(group, url)
+	if err := r.client.HDel(r.ctx, r.key, field).Err(); err != nil {
+		return errors.WithMessage(err, "failed to delete node record from redis")
+	}
+
+	return nil
+}
+
+func redisRegistryField(group Group, url string) string {
+	return fmt.Sprintf("%v:%v", group, rpc.Url2NodeName(url))
+}
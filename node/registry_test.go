@@ -0,0 +1,127 @@
+package node
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryRegistry struct {
+	records map[string]NodeRecord // group:node name => record
+}
+
+func newMemoryRegistry(records ...NodeRecord) *memoryRegistry {
+	r := memoryRegistry{records: make(map[string]NodeRecord)}
+	for _, record := range records {
+		r.Put(record)
+	}
+
+	return &r
+}
+
+func (r *memoryRegistry) Load() (records []NodeRecord, err error) {
+	for _, record := range r.records {
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func (r *memoryRegistry) Put(record NodeRecord) error {
+	r.records[redisRegistryField(record.Group, record.Url)] = record
+	return nil
+}
+
+func (r *memoryRegistry) Delete(group Group, url string) error {
+	delete(r.records, redisRegistryField(group, url))
+	return nil
+}
+
+type testNode struct {
+	*baseNode
+}
+
+func (n *testNode) LatestEpochNumber() (uint64, error) { return 0, nil }
+func (n *testNode) Close()                             {}
+
+func newTestNode(group Group, name, url string, hm HealthMonitor) (Node, error) {
+	return &testNode{newBaseNode(name, url, nil)}, nil
+}
+
+func listUrls(m *Manager) []string {
+	var urls []string
+	for _, n := range m.List() {
+		urls = append(urls, n.Url())
+	}
+
+	sort.Strings(urls)
+
+	return urls
+}
+
+func TestMergeRegistry(t *testing.T) {
+	groupConf := map[Group]UrlConfig{
+		GroupEthHttp: {Nodes: []string{"http://node1:8545", "http://node2:8545"}},
+		GroupEthLogs: {},
+	}
+
+	records := []NodeRecord{
+		{Group: GroupEthHttp, Url: "http://node2:8545", Disabled: true},
+		{Group: GroupEthHttp, Url: "http://node3:8545", Weight: 2},
+		{Group: GroupEthLogs, Url: "http://node4:8545"},
+		{Group: "ethunknown", Url: "http://node5:8545"},
+	}
+
+	merged := mergeRegistry(groupConf, records)
+	assert.Len(t, merged, 2)
+
+	assert.Equal(t, []NodeRecord{
+		{Group: GroupEthHttp, Url: "http://node1:8545"},
+		{Group: GroupEthHttp, Url: "http://node2:8545", Disabled: true},
+		{Group: GroupEthHttp, Url: "http://node3:8545", Weight: 2},
+	}, merged[GroupEthHttp])
+	assert.Equal(t, []string{"http://node1:8545", "http://node3:8545"}, enabledUrls(merged[GroupEthHttp]))
+
+	assert.Equal(t, []string{"http://node4:8545"}, enabledUrls(merged[GroupEthLogs]))
+}
+
+func TestApiWriteThrough(t *testing.T) {
+	groupConf := map[Group]UrlConfig{
+		GroupEthHttp: {Nodes: []string{"http://node1:8545", "http://node2:8545"}},
+	}
+
+	registry := newMemoryRegistry(NodeRecord{Group: GroupEthHttp, Url: "http://node2:8545", Disabled: true})
+	api := mustNewApi(newTestNode, groupConf, registry)
+	assert.Equal(t, []string{"http://node1:8545"}, listUrls(api.managers[GroupEthHttp]))
+
+	// add a new node
+	assert.NoError(t, api.Add(context.Background(), GroupEthHttp, "http://node3:8545"))
+	assert.Len(t, registry.records, 2)
+
+	// remove a configured node, which should be persisted as disabled
+	assert.NoError(t, api.Remove(context.Background(), GroupEthHttp, "http://node1:8545"))
+	assert.True(t, registry.records["ethhttp:node1:8545"].Disabled)
+
+	// remove a dynamically added node
+	assert.NoError(t, api.Remove(context.Background(), GroupEthHttp, "http://node3:8545"))
+	assert.NotContains(t, registry.records, "ethhttp:node3:8545")
+
+	// enable with labels and weight
+	weight, disabled := 3.0, false
+	assert.NoError(t, api.Update(context.Background(), GroupEthHttp, "http://node2:8545", NodeUpdate{
+		Labels: map[string]string{"region": "us"}, Weight: &weight, Disabled: &disabled,
+	}))
+	assert.Equal(t, NodeRecord{
+		Group: GroupEthHttp, Url: "http://node2:8545", Labels: map[string]string{"region": "us"}, Weight: 3,
+	}, registry.records["ethhttp:node2:8545"])
+	assert.Equal(t, []string{"http://node2:8545"}, listUrls(api.managers[GroupEthHttp]))
+
+	assert.Error(t, api.Add(context.Background(), "ethunknown", "http://node3:8545"))
+
+	// restart and load from registry
+	api = mustNewApi(newTestNode, groupConf, registry)
+	assert.Equal(t, []string{"http://node2:8545"}, listUrls(api.managers[GroupEthHttp]))
+	assert.Len(t, api.Registry(GroupEthHttp), 2)
+}
@@ -11,12 +11,16 @@ import (
 type RepartitionResolver interface {
 	Get(key uint64) (string, bool)
 	Put(key uint64, value string)
+	// Invalidate evicts all the keys resolved to the specified node, e.g. node removed or
+	// became unhealthy, so that keys could be distributed to other nodes.
+	Invalidate(node string)
 }
 
 type noopRepartitionResolver struct{}
 
 func (r *noopRepartitionResolver) Get(key uint64) (string, bool) { return "", false }
 func (r *noopRepartitionResolver) Put(key uint64, value string)  {}
+func (r *noopRepartitionResolver) Invalidate(node string)        {}
 
 type partitionInfo struct {
 	key      uint64
@@ -25,16 +29,18 @@ type partitionInfo struct {
 }
 
 type SimpleRepartitionResolver struct {
-	key2Items sync.Map
-	items     *list.List
-	ttl       time.Duration
-	mu        sync.Mutex
+	key2Items  sync.Map
+	node2Items map[string]map[uint64]*list.Element // node => key => item
+	items      *list.List
+	ttl        time.Duration
+	mu         sync.Mutex
 }
 
 func NewSimpleRepartitionResolver(ttl time.Duration) *SimpleRepartitionResolver {
 	return &SimpleRepartitionResolver{
-		items: list.New(),
-		ttl:   ttl,
+		node2Items: make(map[string]map[uint64]*list.Element),
+		items:      list.New(),
+		ttl:        ttl,
 	}
 }
 
@@ -47,14 +53,18 @@ func (r *SimpleRepartitionResolver) Get(key uint64) (string, bool) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	// removed concurrently, e.g. invalidated
+	if current, ok := r.key2Items.Load(key); !ok || current != value {
+		return "", false
+	}
+
 	item := value.(*list.Element)
 	info := item.Value.(partitionInfo)
 	now := time.Now()
 
 	// passively check expiration
 	if info.deadline.Before(now) {
-		r.items.Remove(item)
-		r.key2Items.Delete(key)
+		r.remove(item)
 		return "", false
 	}
 
@@ -81,13 +91,57 @@ func (r *SimpleRepartitionResolver) Put(key uint64, value string) {
 	if value, ok := r.key2Items.Load(key); ok {
 		// update item value
 		item := value.(*list.Element)
+		r.unindex(item.Value.(partitionInfo))
 		item.Value = info
 		r.items.MoveToBack(item)
+		r.index(item)
 	} else {
 		// add new item
 		item := r.items.PushBack(info)
 		r.key2Items.Store(key, item)
+		r.index(item)
+	}
+}
+
+func (r *SimpleRepartitionResolver) Invalidate(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, item := range r.node2Items[node] {
+		r.items.Remove(item)
+		r.key2Items.Delete(item.Value.(partitionInfo).key)
 	}
+
+	delete(r.node2Items, node)
+}
+
+func (r *SimpleRepartitionResolver) index(item *list.Element) {
+	info := item.Value.(partitionInfo)
+
+	items, ok := r.node2Items[info.node]
+	if !ok {
+		items = make(map[uint64]*list.Element)
+		r.node2Items[info.node] = items
+	}
+
+	items[info.key] = item
+}
+
+func (r *SimpleRepartitionResolver) unindex(info partitionInfo) {
+	if items, ok := r.node2Items[info.node]; ok {
+		if delete(items, info.key); len(items) == 0 {
+			delete(r.node2Items, info.node)
+		}
+	}
+}
+
+// remove removes the item along with indices.
+func (r *SimpleRepartitionResolver) remove(item *list.Element) {
+	info := item.Value.(partitionInfo)
+
+	r.items.Remove(item)
+	r.key2Items.Delete(info.key)
+	r.unindex(info)
 }
 
 // gc removes the expired items.
@@ -105,7 +159,6 @@ func (r *SimpleRepartitionResolver) gc() {
 			break
 		}
 
-		r.items.Remove(front)
-		r.key2Items.Delete(info.key)
+		r.remove(front)
 	}
 }
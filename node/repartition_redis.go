@@ -10,6 +10,31 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// getScript reads the key along with ttl renewed, which is resolved only if generation of the
+// node not changed since put, i.e. node not invalidated.
+var getScript = redis.NewScript(`
+local val = redis.call('GETEX', KEYS[1], 'PX', ARGV[1])
+if not val then
+	return false
+end
+local sep = string.find(val, '|', 1, true)
+if not sep then
+	return false
+end
+local node = string.sub(val, sep + 1)
+local gen = redis.call('GET', ARGV[2] .. node) or '0'
+if string.sub(val, 1, sep - 1) ~= gen then
+	return false
+end
+return node
+`)
+
+// putScript sets the key with the current generation of the node.
+var putScript = redis.NewScript(`
+local gen = redis.call('GET', KEYS[2]) or '0'
+return redis.call('SET', KEYS[1], gen .. '|' .. ARGV[1], 'PX', ARGV[2])
+`)
+
 // RedisRepartitionResolver implements RepartitionResolver
 type RedisRepartitionResolver struct {
 	client    *redis.Client
@@ -31,7 +56,9 @@ func NewRedisRepartitionResolver(client *redis.Client, ttl time.Duration, keyPre
 
 func (r *RedisRepartitionResolver) Get(key uint64) (string, bool) {
 	redisKey := redisRepartitionKey(key, r.keyPrefix)
-	node, err := r.client.GetEx(r.ctx, redisKey, r.ttl).Result()
+	genKeyPrefix := redisRepartitionGenKey("", r.keyPrefix)
+
+	node, err := getScript.Run(r.ctx, r.client, []string{redisKey}, r.ttl.Milliseconds(), genKeyPrefix).Text()
 	if err == redis.Nil {
 		return "", false
 	}
@@ -46,14 +73,40 @@ func (r *RedisRepartitionResolver) Get(key uint64) (string, bool) {
 
 func (r *RedisRepartitionResolver) Put(key uint64, value string) {
 	redisKey := redisRepartitionKey(key, r.keyPrefix)
-	if err := r.client.Set(r.ctx, redisKey, value, r.ttl).Err(); err != nil {
+	genKey := redisRepartitionGenKey(value, r.keyPrefix)
+
+	err := putScript.Run(r.ctx, r.client, []string{redisKey, genKey}, value, r.ttl.Milliseconds()).Err()
+	if err != nil {
 		r.logger.WithError(err).WithField("key", redisKey).Error("Failed to set key-value to redis")
 	}
 }
 
+// Invalidate bumps the generation of node, so that all the keys put before are not resolved any
+// more and will be overwritten or expired.
+func (r *RedisRepartitionResolver) Invalidate(node string) {
+	genKey := redisRepartitionGenKey(node, r.keyPrefix)
+
+	gen, err := r.client.Incr(r.ctx, genKey).Result()
+	if err != nil {
+		r.logger.WithError(err).WithField("node", node).Error("Failed to invalidate node keys in redis")
+		return
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"node": node, "generation": gen,
+	}).Debug("Node keys invalidated in redis")
+}
+
 func redisRepartitionKey(key uint64, prefixs ...string) string {
 	prefixs = append(prefixs, "key")
 	prefixStr := strings.Join(prefixs, ":")
 
 	return fmt.Sprintf("node:repartition:%v:%v", prefixStr, key)
 }
+
+func redisRepartitionGenKey(node string, prefixs ...string) string {
+	prefixs = append(prefixs, "gen")
+	prefixStr := strings.Join(prefixs, ":")
+
+	return fmt.Sprintf("node:repartition:%v:%v", prefixStr, node)
+}
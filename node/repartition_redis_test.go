@@ -0,0 +1,73 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRedisRepartitionResolver(t *testing.T, ttl time.Duration) (*RedisRepartitionResolver, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewRedisRepartitionResolver(client, ttl, "test"), server
+}
+
+func TestRedisRepartitionResolverInvalidate(t *testing.T) {
+	resolver, _ := newTestRedisRepartitionResolver(t, time.Minute)
+
+	resolver.Put(1, "node1")
+	resolver.Put(2, "node1")
+	resolver.Put(3, "node2")
+
+	// key reassigned to another node
+	resolver.Put(2, "node2")
+
+	resolver.Invalidate("node1")
+
+	_, ok := resolver.Get(1)
+	assert.False(t, ok)
+
+	node, ok := resolver.Get(2)
+	assert.True(t, ok)
+	assert.Equal(t, "node2", node)
+
+	resolver.Invalidate("node2")
+
+	_, ok = resolver.Get(2)
+	assert.False(t, ok)
+	_, ok = resolver.Get(3)
+	assert.False(t, ok)
+}
+
+func TestRedisRepartitionResolverKeepAlive(t *testing.T) {
+	resolver, server := newTestRedisRepartitionResolver(t, time.Minute)
+
+	resolver.Put(1, "node1")
+
+	// key kept alive by steady traffic beyond the ttl since put
+	for i := 0; i < 3; i++ {
+		server.FastForward(40 * time.Second)
+
+		node, ok := resolver.Get(1)
+		assert.True(t, ok)
+		assert.Equal(t, "node1", node)
+	}
+
+	resolver.Invalidate("node1")
+
+	_, ok := resolver.Get(1)
+	assert.False(t, ok)
+
+	// keys put after invalidated are resolved again
+	resolver.Put(1, "node1")
+
+	node, ok := resolver.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "node1", node)
+}
@@ -0,0 +1,64 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cespare/xxhash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimpleRepartitionResolverInvalidate(t *testing.T) {
+	resolver := NewSimpleRepartitionResolver(time.Minute)
+
+	resolver.Put(1, "node1")
+	resolver.Put(2, "node1")
+	resolver.Put(3, "node2")
+
+	// key reassigned to another node
+	resolver.Put(2, "node2")
+
+	resolver.Invalidate("node1")
+
+	_, ok := resolver.Get(1)
+	assert.False(t, ok)
+
+	node, ok := resolver.Get(2)
+	assert.True(t, ok)
+	assert.Equal(t, "node2", node)
+
+	resolver.Invalidate("node2")
+
+	_, ok = resolver.Get(2)
+	assert.False(t, ok)
+	_, ok = resolver.Get(3)
+	assert.False(t, ok)
+
+	assert.Empty(t, resolver.node2Items)
+	assert.Zero(t, resolver.items.Len())
+}
+
+func TestManagerInvalidateRepartition(t *testing.T) {
+	resolver := NewSimpleRepartitionResolver(time.Minute)
+	urls := []string{"http://node1:8545", "http://node2:8545"}
+	m := NewManagerWithRepartition(GroupEthHttp, newTestNode, urls, resolver)
+
+	key := []byte("key")
+	url := m.Route(key)
+
+	name, ok := resolver.Get(xxhash.Sum64(key))
+	assert.True(t, ok)
+
+	// unhealthy node evicted
+	m.ReportUnhealthy(name, false, nil)
+	_, ok = resolver.Get(xxhash.Sum64(key))
+	assert.False(t, ok)
+
+	other := m.Route(key)
+	assert.NotEqual(t, url, other)
+
+	// removed node evicted
+	m.Remove(other)
+	_, ok = resolver.Get(xxhash.Sum64(key))
+	assert.False(t, ok)
+}
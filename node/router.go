@@ -27,9 +27,10 @@ const (
 	GroupCfxArchives = "cfxarchives"
 
 	// evm space fullnode groups
-	GroupEthHttp = "ethhttp"
-	GroupEthWs   = "ethws"
-	GroupEthLogs = "ethlogs"
+	GroupEthHttp     = "ethhttp"
+	GroupEthWs       = "ethws"
+	GroupEthLogs     = "ethlogs"
+	GroupEthArchives = "etharchives"
 
 	// debug space fullnode groups
 	GroupDebugHttp = "debughttp"
@@ -54,8 +55,33 @@ type Router interface {
 	Route(group Group, key []byte) string
 }
 
-// MustNewRouter creates an instance of Router.
-func MustNewRouter(redisURL string, nodeRPCURL string, groupConf map[Group]UrlConfig) Router {
+// SuccessorRouter is implemented by routers that could route a key to the successor fullnodes on
+// hash ring, e.g. to retry on the next fullnode of the same group.
+type SuccessorRouter interface {
+	// RouteSuccessors returns the full node URLs for specified group and key in hash ring order,
+	// beginning with the one that the key located on the hash ring.
+	RouteSuccessors(group Group, key []byte) []string
+}
+
+// ringSuccessors returns all the members of hash ring in ring order beginning with the one that
+// the specified key located on.
+func ringSuccessors(hashRing *consistent.Consistent, key []byte) []string {
+	members, err := hashRing.GetClosestN(key, len(hashRing.GetMembers()))
+	if err != nil {
+		return nil
+	}
+
+	var urls []string
+	for _, member := range members {
+		urls = append(urls, member.(interface{ Url() string }).Url())
+	}
+
+	return urls
+}
+
+// MustNewRouter creates an instance of Router, and the background routines, e.g. to sync with node
+// manager RPC, stop once the specified context done.
+func MustNewRouter(ctx context.Context, redisURL string, nodeRPCURL string, groupConf map[Group]UrlConfig) Router {
 	var routers []Router
 
 	// Add redis router if configured
@@ -73,7 +99,7 @@ func MustNewRouter(redisURL string, nodeRPCURL string, groupConf map[Group]UrlCo
 	// Add node rpc router if configured
 	if len(nodeRPCURL) > 0 {
 		// http://127.0.0.1:22530
-		client, err := rpc.DialHTTP(nodeRPCURL)
+		client, err := DialNodeRPC(nodeRPCURL)
 		if err != nil {
 			logrus.WithError(err).Fatal("Failed to create rpc client")
 		}
@@ -86,6 +112,14 @@ func MustNewRouter(redisURL string, nodeRPCURL string, groupConf map[Group]UrlCo
 			logrus.WithError(err).Fatal("Failed to new local router with node rpc")
 		}
 		routers = append(routers, localRouter)
+
+		// sync the weight of registered fullnodes for load balancing
+		go syncRegisteredWeights(ctx, client, groupConf)
+
+		// report circuit breaker states to expose via node status
+		if cfg.Breaker.Enabled {
+			go reportBreakerStates(ctx, client)
+		}
 	}
 
 	// If redis and node rpc not configured, add local router for failover.
@@ -135,6 +169,32 @@ func (r *chainedRouter) Route(group Group, key []byte) string {
 	return config.Failover
 }
 
+// RouteSuccessors implements the SuccessorRouter interface by the first router that supports, or
+// falls back to the configured fullnodes beginning with the one that the key hashed to, e.g. routed
+// by redis only.
+func (r *chainedRouter) RouteSuccessors(group Group, key []byte) []string {
+	for _, r := range r.routers {
+		if sr, ok := r.(SuccessorRouter); ok {
+			if urls := sr.RouteSuccessors(group, key); len(urls) > 0 {
+				return urls
+			}
+		}
+	}
+
+	nodes := r.groupConf[group].Nodes
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	offset := int(xxhash.Sum64(key) % uint64(len(nodes)))
+
+	urls := make([]string, 0, len(nodes))
+	urls = append(urls, nodes[offset:]...)
+	urls = append(urls, nodes[:offset]...)
+
+	return urls
+}
+
 // RedisRouter routes RPC requests via redis.
 // It should be used together with RedisRepartitionResolver.
 type RedisRouter struct {
@@ -185,9 +245,20 @@ func (r *NodeRpcRouter) Route(group Group, key []byte) string {
 	return result
 }
 
+func (r *NodeRpcRouter) RouteSuccessors(group Group, key []byte) []string {
+	var result []string
+	if err := r.client.Call(&result, "node_routeSuccessors", group, hexutil.Bytes(key)); err != nil {
+		logrus.WithError(err).Error("Failed to route key successors from node RPC")
+		return nil
+	}
+
+	return result
+}
+
 type localNode string
 
 func (n localNode) String() string { return string(n) }
+func (n localNode) Url() string    { return string(n) }
 
 type hasher struct{}
 
@@ -248,6 +319,15 @@ func (r *LocalRouter) Route(group Group, key []byte) string {
 	return ""
 }
 
+func (r *LocalRouter) RouteSuccessors(group Group, key []byte) []string {
+	item, ok := r.groups[group]
+	if !ok {
+		return nil
+	}
+
+	return ringSuccessors(item.hashRing, key)
+}
+
 func NewLocalRouterFromNodeRPC(client *rpc.Client, groupConf map[Group]UrlConfig) (*LocalRouter, error) {
 	group2Urls := make(map[Group][]string)
 
@@ -286,6 +366,31 @@ func (r *LocalRouter) update(client *rpc.Client) {
 	}
 }
 
+// syncRegisteredWeights periodically syncs the weight of fullnodes registered in node manager, so
+// that the weight updated at runtime also takes effect for load balancing of RPC clients.
+func syncRegisteredWeights(ctx context.Context, client *rpc.Client, groupConf map[Group]UrlConfig) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		for grp := range groupConf {
+			var records []NodeRecord
+			if err := client.Call(&records, "node_registry", grp); err != nil {
+				logrus.WithError(err).WithField("group", grp).Debug("Failed to get node registry from node manager RPC periodically")
+				continue
+			}
+
+			setRegisteredWeights(grp, records)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (r *LocalRouter) updateOnce(urls []string, group Group) {
 	fnNodes, hashRing := r.groups[group].nodes, r.groups[group].hashRing
 
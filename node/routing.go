@@ -0,0 +1,127 @@
+package node
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RouteConfig routes RPC methods to the specified node group.
+type RouteConfig struct {
+	// method patterns, e.g. `eth_getLogs` for exact match, `debug_*` for all the methods of
+	// namespace `debug`, or `*` for any method.
+	Methods []string
+	// node group to route to
+	Group Group
+}
+
+// RoutingConfig routing table configurations of a space.
+type RoutingConfig struct {
+	// routes matched in order, which take precedence over the built-in routes
+	Routes []RouteConfig
+	// namespaces whose unknown methods are forwarded verbatim to the routed node group
+	Passthrough []string
+}
+
+var (
+	cfxBuiltinRoutes = []RouteConfig{
+		{Methods: []string{"cfx_getLogs"}, Group: GroupCfxLogs},
+	}
+
+	ethBuiltinRoutes = []RouteConfig{
+		{Methods: []string{"eth_getLogs", "eth_getFilterLogs", "eth_getFilterChanges"}, Group: GroupEthLogs},
+	}
+
+	cfxRoutingTable *RoutingTable
+	ethRoutingTable *RoutingTable
+)
+
+// CfxRoutingTable returns the core space routing table loaded from viper.
+func CfxRoutingTable() *RoutingTable {
+	return cfxRoutingTable
+}
+
+// EthRoutingTable returns the evm space routing table loaded from viper.
+func EthRoutingTable() *RoutingTable {
+	return ethRoutingTable
+}
+
+type methodRoute struct {
+	pattern string
+	group   Group
+}
+
+func (r *methodRoute) match(method string) bool {
+	if r.pattern == "*" {
+		return true
+	}
+
+	if strings.HasSuffix(r.pattern, "*") {
+		return strings.HasPrefix(method, strings.TrimSuffix(r.pattern, "*"))
+	}
+
+	return method == r.pattern
+}
+
+// RoutingTable routes RPC methods to node groups.
+type RoutingTable struct {
+	routes       []methodRoute
+	defaultGroup Group
+	passthrough  map[string]bool // namespace => true
+}
+
+// NewRoutingTable creates a routing table with the configured routes, built-in routes and
+// default node group in order of precedence. Note, all the routed node groups must be
+// included in the specified group configurations.
+func NewRoutingTable(
+	config RoutingConfig, builtins []RouteConfig, defaultGroup Group, groupConf map[Group]UrlConfig,
+) (*RoutingTable, error) {
+	table := RoutingTable{
+		defaultGroup: defaultGroup,
+		passthrough:  make(map[string]bool),
+	}
+
+	for _, rc := range append(config.Routes, builtins...) {
+		if _, ok := groupConf[rc.Group]; !ok {
+			return nil, errors.Errorf("unknown node group %v", rc.Group)
+		}
+
+		for _, pattern := range rc.Methods {
+			if len(pattern) == 0 || strings.Contains(strings.TrimSuffix(pattern, "*"), "*") {
+				return nil, errors.Errorf("invalid method pattern %v", pattern)
+			}
+
+			table.routes = append(table.routes, methodRoute{pattern, rc.Group})
+		}
+	}
+
+	for _, ns := range config.Passthrough {
+		table.passthrough[strings.TrimSuffix(ns, "_")] = true
+	}
+
+	return &table, nil
+}
+
+// Route returns the node group that the specified RPC method is routed to.
+func (t *RoutingTable) Route(method string) Group {
+	for i := range t.routes {
+		if t.routes[i].match(method) {
+			return t.routes[i].group
+		}
+	}
+
+	return t.defaultGroup
+}
+
+// IsPassthrough returns true if the namespace of the specified RPC method is passthrough, in
+// which case unknown methods could be forwarded to fullnode verbatim.
+func (t *RoutingTable) IsPassthrough(method string) bool {
+	ns := strings.SplitN(method, "_", 2)[0]
+
+	// subscription is stateful and could not be forwarded as a single call
+	if strings.HasSuffix(method, "_subscribe") || strings.HasSuffix(method, "_unsubscribe") {
+		return false
+	}
+
+	return t.passthrough[ns]
+}
@@ -0,0 +1,44 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutingTable(t *testing.T) {
+	groupConf := map[Group]UrlConfig{
+		GroupEthHttp: {},
+		GroupEthLogs: {},
+		"ethdebug":   {},
+	}
+
+	config := RoutingConfig{
+		Routes: []RouteConfig{
+			{Methods: []string{"debug_*", "txpool_content"}, Group: "ethdebug"},
+			{Methods: []string{"eth_getFilterLogs"}, Group: GroupEthHttp},
+		},
+		Passthrough: []string{"debug", "txpool_"},
+	}
+
+	table, err := NewRoutingTable(config, ethBuiltinRoutes, GroupEthHttp, groupConf)
+	assert.NoError(t, err)
+
+	assert.Equal(t, Group("ethdebug"), table.Route("debug_traceTransaction"))
+	assert.Equal(t, Group("ethdebug"), table.Route("txpool_content"))
+	assert.Equal(t, Group(GroupEthHttp), table.Route("txpool_status"))
+	// configured routes take precedence over built-in ones
+	assert.Equal(t, Group(GroupEthHttp), table.Route("eth_getFilterLogs"))
+	assert.Equal(t, Group(GroupEthLogs), table.Route("eth_getLogs"))
+	assert.Equal(t, Group(GroupEthHttp), table.Route("eth_call"))
+
+	assert.True(t, table.IsPassthrough("debug_traceCall"))
+	assert.True(t, table.IsPassthrough("txpool_inspect"))
+	assert.False(t, table.IsPassthrough("eth_foo"))
+	assert.False(t, table.IsPassthrough("debug_subscribe"))
+
+	// unknown node group
+	config.Routes = append(config.Routes, RouteConfig{Methods: []string{"trace_*"}, Group: "ethtrace"})
+	_, err = NewRoutingTable(config, nil, GroupEthHttp, groupConf)
+	assert.Error(t, err)
+}
@@ -1,38 +1,288 @@
 package node
 
 import (
+	"context"
+	"sync"
+
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/util"
 	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/scroll-tech/rpc-gateway/util/rpc/handlers"
+	"github.com/sirupsen/logrus"
 )
 
-// NewServer creates node management RPC server
-func NewServer(nf nodeFactory, groupConf map[Group]UrlConfig) *rpc.Server {
-	managers := make(map[Group]*Manager)
-	for k, v := range groupConf {
-		managers[k] = NewManager(k, nf, v.Nodes)
+// NewServer creates node management RPC server. Note, the registered fullnodes will be merged
+// with the configured ones if registry specified, and changes will be written through.
+//
+// If authentication enabled, callers must be authenticated by bearer token or client certificate,
+// and only admin is allowed to mutate nodes.
+func NewServer(nf nodeFactory, groupConf map[Group]UrlConfig, registry Registry) *rpc.Server {
+	var middlewares []handlers.Middleware
+
+	if cfg.Auth.Enabled {
+		auth, err := newAuthenticator(cfg.Auth.Users)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to create node manager authenticator")
+		}
+
+		middlewares = append(middlewares, auth.middleware)
 	}
 
-	return rpc.MustNewServer("node", map[string]interface{}{
-		"node": &api{managers},
-	})
+	server := rpc.MustNewServer("node", map[string]interface{}{
+		"node": mustNewApi(nf, groupConf, registry),
+	}, middlewares...)
+
+	if tlsConfig := mustNewServerTLSConfig(); tlsConfig != nil {
+		server.SetTLSConfig(tlsConfig)
+	}
+
+	return server
+}
+
+func mustNewApi(nf nodeFactory, groupConf map[Group]UrlConfig, registry Registry) *api {
+	if registry == nil {
+		registry = &noopRegistry{}
+	}
+
+	records, err := registry.Load()
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to load node registry")
+	}
+
+	api := api{
+		managers:  make(map[Group]*Manager),
+		groupConf: groupConf,
+		store:     registry,
+		nodes:     make(map[Group]map[string]NodeRecord),
+		audit:     mustNewAuditLogger(cfg.Auth.AuditLog),
+	}
+
+	for k, v := range mergeRegistry(groupConf, records) {
+		api.managers[k] = NewManager(k, nf, enabledUrls(v))
+		api.nodes[k] = make(map[string]NodeRecord)
+
+		for _, record := range v {
+			api.nodes[k][record.NodeName()] = record
+		}
+
+		setRegisteredWeights(k, v)
+	}
+
+	return &api
 }
 
 // api node management RPC APIs.
 type api struct {
-	managers map[Group]*Manager
+	managers  map[Group]*Manager
+	groupConf map[Group]UrlConfig
+
+	mu    sync.Mutex
+	store Registry                        // persistent node registry
+	nodes map[Group]map[string]NodeRecord // group => node name => record
+
+	audit *logrus.Logger // audit log of mutations
 }
 
-func (api *api) Add(group Group, url string) {
-	if m, ok := api.managers[group]; ok {
-		m.Add(url)
+// Add adds fullnode into the specified group, or enables it if already registered.
+func (api *api) Add(ctx context.Context, group Group, url string) error {
+	return api.mutate(ctx, "node_add", group, url, nil, func() error {
+		disabled := false
+		return api.update(group, url, NodeUpdate{Disabled: &disabled})
+	})
+}
+
+// Remove removes fullnode from the specified group.
+func (api *api) Remove(ctx context.Context, group Group, url string) error {
+	return api.mutate(ctx, "node_remove", group, url, nil, func() error {
+		return api.remove(group, url)
+	})
+}
+
+// Update updates the labels, weight or disabled flag of fullnode, which will be registered if
+// not registered yet.
+func (api *api) Update(ctx context.Context, group Group, url string, update NodeUpdate) error {
+	return api.mutate(ctx, "node_update", group, url, &update, func() error {
+		return api.update(group, url, update)
+	})
+}
+
+// mutate authorizes the caller as admin to mutate nodes, and writes audit log.
+func (api *api) mutate(
+	ctx context.Context, method string, group Group, url string, update *NodeUpdate, fn func() error,
+) error {
+	err := authorize(ctx, RoleAdmin)
+	if err == nil {
+		err = fn()
+	}
+
+	caller := Identity{Name: "anonymous"}
+	if identity, ok := identityFromContext(ctx); ok {
+		caller = identity
+	}
+
+	ip, _ := handlers.GetIPAddressFromContext(ctx)
+
+	entry := api.audit.WithFields(logrus.Fields{
+		"audit":      true,
+		"method":     method,
+		"group":      group,
+		"url":        url,
+		"caller":     caller.Name,
+		"callerRole": caller.Role,
+		"callerIp":   ip,
+	})
+
+	if update != nil && update.Labels != nil {
+		entry = entry.WithField("labels", update.Labels)
+	}
+
+	if update != nil && update.Weight != nil {
+		entry = entry.WithField("weight", *update.Weight)
+	}
+
+	if update != nil && update.Disabled != nil {
+		entry = entry.WithField("disabled", *update.Disabled)
+	}
+
+	if err != nil {
+		entry.WithError(err).Warn("Node mutation rejected or failed")
+	} else {
+		entry.Info("Node mutated")
 	}
+
+	return err
 }
 
-func (api *api) Remove(group Group, url string) {
-	if m, ok := api.managers[group]; ok {
-		m.Remove(url)
+func (api *api) remove(group Group, url string) error {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	m, ok := api.managers[group]
+	if !ok {
+		return errors.Errorf("unknown node group %v", group)
 	}
+
+	record := NodeRecord{Group: group, Url: url}
+	nodeName := record.NodeName()
+
+	var err error
+	if api.isConfigured(group, nodeName) {
+		// keep it disabled, otherwise configured fullnode comes back again upon restart
+		if old, ok := api.nodes[group][nodeName]; ok {
+			record = old
+		}
+
+		record.Disabled = true
+		if err = api.store.Put(record); err == nil {
+			api.nodes[group][nodeName] = record
+		}
+	} else if err = api.store.Delete(group, url); err == nil {
+		delete(api.nodes[group], nodeName)
+	}
+
+	if err != nil {
+		return errors.WithMessage(err, "failed to write node registry")
+	}
+
+	api.updateWeights(group)
+
+	m.Remove(url)
+
+	return nil
+}
+
+func (api *api) update(group Group, url string, update NodeUpdate) error {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	m, ok := api.managers[group]
+	if !ok {
+		return errors.Errorf("unknown node group %v", group)
+	}
+
+	record := NodeRecord{Group: group, Url: url}
+	if old, ok := api.nodes[group][record.NodeName()]; ok {
+		record = old
+	}
+
+	if update.Labels != nil {
+		record.Labels = update.Labels
+	}
+
+	if update.Weight != nil {
+		record.Weight = *update.Weight
+	}
+
+	if update.Disabled != nil {
+		record.Disabled = *update.Disabled
+	}
+
+	if err := api.store.Put(record); err != nil {
+		return errors.WithMessage(err, "failed to write node registry")
+	}
+
+	api.nodes[group][record.NodeName()] = record
+	api.updateWeights(group)
+
+	if record.Disabled {
+		m.Remove(record.Url)
+	} else {
+		m.Add(record.Url)
+	}
+
+	return nil
+}
+
+// updateWeights updates the weight of registered fullnodes for load balancing of the specified
+// group, which should be called with lock held.
+func (api *api) updateWeights(group Group) {
+	records := make([]NodeRecord, 0, len(api.nodes[group]))
+	for _, record := range api.nodes[group] {
+		records = append(records, record)
+	}
+
+	setRegisteredWeights(group, records)
+}
+
+// ReportBreakers reports the circuit breaker states of fullnodes by node name from the RPC service
+// instance, which are exposed via node status. Note, only RPC services of `service` role are allowed
+// to report if authentication enabled, otherwise anyone could fake the states.
+func (api *api) ReportBreakers(ctx context.Context, instance string, states map[string]BreakerState) error {
+	if err := authorize(ctx, RoleService); err != nil {
+		return err
+	}
+
+	if len(instance) == 0 {
+		return errors.New("instance ID of RPC service not specified")
+	}
+
+	reportedBreakers.report(instance, states)
+
+	return nil
+}
+
+// Registry returns the registered fullnodes of the specified group, including the disabled ones.
+func (api *api) Registry(group Group) []NodeRecord {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	records := []NodeRecord{}
+	for _, record := range api.nodes[group] {
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func (api *api) isConfigured(group Group, nodeName string) bool {
+	for _, url := range api.groupConf[group].Nodes {
+		if rpc.Url2NodeName(url) == nodeName {
+			return true
+		}
+	}
+
+	return false
 }
 
 // List returns the URL list of all nodes.
@@ -73,6 +323,24 @@ func (api *api) Status(group Group, url *string) (res []Status) {
 	return
 }
 
+// Syncing returns the sync status of the specified node, or nil if not found.
+func (api *api) Syncing(group Group, url string) *SyncStatus {
+	mgr := api.managers[group]
+	if mgr == nil { // no group found
+		return nil
+	}
+
+	n := mgr.Get(url)
+	if util.IsInterfaceValNil(n) {
+		return nil
+	}
+
+	status := n.Status()
+	syncStatus := status.SyncStatus(mgr.HealthyEpoch())
+
+	return &syncStatus
+}
+
 // List returns the URL list of all nodes.
 func (api *api) ListAll() map[Group][]string {
 	result := make(map[Group][]string)
@@ -93,3 +361,13 @@ func (api *api) Route(group Group, key hexutil.Bytes) string {
 
 	return ""
 }
+
+// RouteSuccessors returns the node URLs in hash ring order beginning with the one that the
+// specified key located on.
+func (api *api) RouteSuccessors(group Group, key hexutil.Bytes) []string {
+	if m, ok := api.managers[group]; ok {
+		return m.RouteSuccessors(key)
+	}
+
+	return nil
+}
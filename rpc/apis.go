@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"context"
+
 	sdk "github.com/Conflux-Chain/go-conflux-sdk"
 	"github.com/openweb3/web3go"
 	"github.com/pkg/errors"
@@ -28,12 +30,12 @@ func filterExposedApis(allApis []API, exposedModules []string) (map[string]inter
 	for _, api := range allApis {
 		if len(exposedModules) == 0 { // empty module list, use all public RPC APIs
 			if api.Public {
-				servedApis[api.Namespace] = api.Service
+				addService(servedApis, api.Namespace, api.Service)
 			}
 			continue
 		}
 
-		servedApis[api.Namespace] = api.Service
+		addService(servedApis, api.Namespace, api.Service)
 	}
 
 	if len(exposedModules) == 0 {
@@ -54,6 +56,19 @@ func filterExposedApis(allApis []API, exposedModules []string) (map[string]inter
 	return filteredApis, nil
 }
 
+// addService adds service under the specified namespace, and services of the same namespace are
+// merged into a slice to register altogether.
+func addService(services map[string]interface{}, namespace string, service interface{}) {
+	switch existing := services[namespace].(type) {
+	case nil:
+		services[namespace] = service
+	case []interface{}:
+		services[namespace] = append(existing, service)
+	default:
+		services[namespace] = []interface{}{existing, service}
+	}
+}
+
 // nativeSpaceApis returns the collection of built-in RPC APIs for core space.
 func nativeSpaceApis(
 	clientProvider *node.CfxClientProvider, gashandler *handler.GasStationHandler, option ...CfxAPIOption,
@@ -93,13 +108,26 @@ func nativeSpaceApis(
 	}
 }
 
-// evmSpaceApis returns the collection of built-in RPC APIs for EVM space.
-func evmSpaceApis(clientProvider *node.EthClientProvider, option ...EthAPIOption) ([]API, error) {
-	return []API{
+// evmSpaceApis returns the collection of built-in RPC APIs for EVM space, whose background tasks
+// stop once the specified context is done.
+func evmSpaceApis(
+	ctx context.Context, clientProvider *node.EthClientProvider, option ...EthAPIOption,
+) ([]API, error) {
+	var opt EthAPIOption
+	if len(option) > 0 {
+		opt = option[0]
+	}
+
+	apis := []API{
 		{
 			Namespace: "eth",
 			Version:   "1.0",
-			Service:   mustNewEthAPI(clientProvider, option...),
+			Service:   mustNewEthAPI(ctx, clientProvider, option...),
+			Public:    true,
+		}, {
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   mustNewEthSyncingAPI(clientProvider, opt.SyncStatusProvider),
 			Public:    true,
 		}, {
 			Namespace: "web3",
@@ -119,10 +147,30 @@ func evmSpaceApis(clientProvider *node.EthClientProvider, option ...EthAPIOption
 		}, {
 			Namespace: "parity",
 			Version:   "1.0",
-			Service:   &parityAPI{},
+			Service:   &parityAPI{storeHandler: opt.StoreHandler},
 			Public:    false,
 		},
-	}, nil
+	}
+
+	if opt.GasStationHandler != nil {
+		apis = append(apis, API{
+			Namespace: "gasstation",
+			Version:   "1.0",
+			Service:   newEthGasStationAPI(opt.GasStationHandler),
+			Public:    true,
+		})
+	}
+
+	if opt.TxTracker != nil {
+		apis = append(apis, API{
+			Namespace: "confura",
+			Version:   "1.0",
+			Service:   newConfuraAPI(opt.TxTracker),
+			Public:    true,
+		})
+	}
+
+	return apis, nil
 }
 
 // nativeSpaceBridgeApis adapts evm space RPCs to core space RPCs.
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+type lruEntry struct {
+	key      string
+	value    []byte
+	blockNum uint64 // block number on which the value depends, 0 means never reverted
+}
+
+func (e *lruEntry) size() int {
+	return len(e.key) + len(e.value)
+}
+
+// sizedLruCache is a LRU cache bounded by the total size in bytes of cached keys and values.
+type sizedLruCache struct {
+	mu      sync.Mutex
+	maxSize int
+	size    int
+	ll      *list.List               // most recently used at front
+	items   map[string]*list.Element // key => element of *lruEntry
+}
+
+func newSizedLruCache(maxSize int) *sizedLruCache {
+	return &sizedLruCache{
+		maxSize: maxSize,
+		ll:      list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+func (c *sizedLruCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.ll.MoveToFront(elem)
+
+	return elem.Value.(*lruEntry).value, true
+}
+
+func (c *sizedLruCache) add(key string, value []byte, blockNum uint64) {
+	entry := &lruEntry{key: key, value: value, blockNum: blockNum}
+	if entry.size() > c.maxSize {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+
+	c.items[key] = c.ll.PushFront(entry)
+	c.size += entry.size()
+
+	// evict the least recently used entries if size overflows
+	for c.size > c.maxSize {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// removeFrom removes all the entries that depend on blocks since the specified block number.
+func (c *sizedLruCache) removeFrom(blockNum uint64) (removed int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.ll.Front(); elem != nil; {
+		next := elem.Next()
+
+		if bn := elem.Value.(*lruEntry).blockNum; bn > 0 && bn >= blockNum {
+			c.removeElement(elem)
+			removed++
+		}
+
+		elem = next
+	}
+
+	return removed
+}
+
+func (c *sizedLruCache) removeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+	c.size = 0
+}
+
+func (c *sizedLruCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+func (c *sizedLruCache) removeElement(elem *list.Element) {
+	entry := c.ll.Remove(elem).(*lruEntry)
+	delete(c.items, entry.key)
+	c.size -= entry.size()
+}
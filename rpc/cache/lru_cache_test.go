@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSizedLruCacheEvict(t *testing.T) {
+	cache := newSizedLruCache(10)
+
+	cache.add("k1", []byte("v1"), 1) // size 4
+	cache.add("k2", []byte("v2"), 2) // size 4
+
+	// k1 becomes the most recently used
+	_, ok := cache.get("k1")
+	assert.True(t, ok)
+
+	// k2 evicted due to size overflow
+	cache.add("k3", []byte("v3"), 3)
+	assert.Equal(t, 2, cache.len())
+
+	_, ok = cache.get("k2")
+	assert.False(t, ok)
+
+	// too large to cache
+	cache.add("k4", []byte("too large value"), 4)
+	_, ok = cache.get("k4")
+	assert.False(t, ok)
+}
+
+func TestSizedLruCacheRemoveFrom(t *testing.T) {
+	cache := newSizedLruCache(1024)
+
+	cache.add("hash", []byte("v0"), 0) // never reverted
+	for i, k := range []string{"k1", "k2", "k3"} {
+		cache.add(k, []byte("v"), uint64(i+1))
+	}
+
+	assert.Equal(t, 2, cache.removeFrom(2))
+
+	for k, expected := range map[string]bool{"hash": true, "k1": true, "k2": false, "k3": false} {
+		_, ok := cache.get(k)
+		assert.Equal(t, expected, ok, k)
+	}
+}
@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/go-redis/redis/v8"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+const redisKeyPrefixResponseCache = "rpc:cache"
+
+// ResponseCacheConfig configurations to cache RPC responses of immutable historical queries.
+type ResponseCacheConfig struct {
+	// whether to enable response cache
+	Enabled bool
+	// max total size in MB of the in-memory cached responses
+	MaxSizeMB int `default:"64"`
+	// max size in KB of a single response to cache
+	MaxEntrySizeKB int `default:"512"`
+	// number of blocks below the latest block, which are regarded as immutable
+	Confirmations uint64 `default:"64"`
+	// optional redis to share cached responses across processes,
+	// e.g. redis://<user>:<password>@<host>:<port>/<db_number>
+	RedisUrl string
+	// expiration of the responses cached in redis
+	RedisTTL time.Duration `default:"1h"`
+}
+
+// ResponseCache caches RPC responses in memory (LRU), and optionally in redis as the second layer.
+type ResponseCache struct {
+	config ResponseCacheConfig
+
+	lru *sizedLruCache
+	rdb *redis.Client // optional
+
+	// generation of the responses cached in redis, which is bumped on chain reorg so that
+	// stale responses won't be hit anymore but expire later.
+	generation int64
+}
+
+// MustNewEthResponseCacheFromViper creates an evm space response cache from viper settings,
+// or returns false if not enabled.
+func MustNewEthResponseCacheFromViper() (*ResponseCache, bool) {
+	var config ResponseCacheConfig
+	viper.MustUnmarshalKey("ethrpc.responseCache", &config)
+
+	if !config.Enabled {
+		return nil, false
+	}
+
+	var rdb *redis.Client
+	if len(config.RedisUrl) > 0 {
+		opt, err := redis.ParseURL(config.RedisUrl)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to parse redis URL for response cache")
+		}
+
+		rdb = redis.NewClient(opt)
+	}
+
+	return NewResponseCache(config, rdb), true
+}
+
+func NewResponseCache(config ResponseCacheConfig, rdb *redis.Client) *ResponseCache {
+	return &ResponseCache{
+		config: config,
+		lru:    newSizedLruCache(config.MaxSizeMB * 1024 * 1024),
+		rdb:    rdb,
+	}
+}
+
+// Confirmations returns the number of blocks below the latest block, which are regarded
+// as immutable.
+func (c *ResponseCache) Confirmations() uint64 {
+	return c.config.Confirmations
+}
+
+// Get gets the cached response of the specified RPC method from memory first, then redis if any.
+func (c *ResponseCache) Get(ctx context.Context, method, key string) ([]byte, bool) {
+	val, ok := c.lru.get(key)
+	metrics.Registry.RPC.CacheHit(method, "memory").Mark(ok)
+
+	if ok || c.rdb == nil {
+		return val, ok
+	}
+
+	val, err := c.rdb.Get(ctx, c.redisKey(key)).Bytes()
+	if err != nil && err != redis.Nil {
+		logrus.WithError(err).WithField("method", method).Info("Failed to get response from redis cache")
+	}
+
+	ok = err == nil
+	metrics.Registry.RPC.CacheHit(method, "redis").Mark(ok)
+
+	if ok { // populate memory cache
+		// block number is unknown, so leaves it to redis generation for invalidation
+		c.lru.add(key, val, 0)
+	}
+
+	return val, ok
+}
+
+// Set caches the response of the specified RPC method, which depends on the specified block
+// number (0 for none) for invalidation on chain reorg.
+func (c *ResponseCache) Set(ctx context.Context, method, key string, val []byte, blockNum uint64) {
+	if len(val) > c.config.MaxEntrySizeKB*1024 {
+		return
+	}
+
+	c.lru.add(key, val, blockNum)
+
+	if c.rdb == nil {
+		return
+	}
+
+	if err := c.rdb.Set(ctx, c.redisKey(key), val, c.config.RedisTTL).Err(); err != nil {
+		logrus.WithError(err).WithField("method", method).Info("Failed to set response into redis cache")
+	}
+}
+
+// Invalidate removes the cached responses that depend on blocks since the reverted block
+// number due to chain reorg, and switches to the new redis generation.
+func (c *ResponseCache) Invalidate(revertTo uint64, generation int64) {
+	removed := c.lru.removeFrom(revertTo)
+
+	if c.rdb != nil {
+		// bump at least by one if generation not provided
+		if old := atomic.LoadInt64(&c.generation); generation <= old {
+			generation = old + 1
+		}
+
+		atomic.StoreInt64(&c.generation, generation)
+
+		// purge the whole memory cache, since entries populated from redis are unknown
+		// of block number.
+		c.lru.removeAll()
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"revertTo":   revertTo,
+		"generation": generation,
+		"removed":    removed,
+	}).Info("Response cache invalidated due to chain reorg")
+}
+
+// SetGeneration sets the redis generation, e.g. loaded from reorg version on startup.
+func (c *ResponseCache) SetGeneration(generation int64) {
+	atomic.StoreInt64(&c.generation, generation)
+}
+
+func (c *ResponseCache) redisKey(key string) string {
+	return fmt.Sprintf("%v:%v:%v", redisKeyPrefixResponseCache, atomic.LoadInt64(&c.generation), key)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/scroll-tech/rpc-gateway/util"
 	"github.com/scroll-tech/rpc-gateway/util/metrics"
 	"github.com/scroll-tech/rpc-gateway/util/relay"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
 	"github.com/sirupsen/logrus"
 )
 
@@ -136,7 +137,7 @@ func (api *cfxAPI) GetBlockByHash(ctx context.Context, blockHash types.Hash, inc
 		block, err := api.StoreHandler.GetBlockByHash(ctx, blockHash, includeTxs)
 
 		logger.WithError(err).Debug("Delegated `cfx_getBlockByHash` to store handler")
-		api.collectHitStats("cfx_getBlockByHash", err == nil)
+		api.collectHitStats(ctx, "cfx_getBlockByHash", err == nil)
 
 		if err == nil {
 			return block, nil
@@ -174,7 +175,7 @@ func (api *cfxAPI) GetBlockByEpochNumber(ctx context.Context, epoch types.Epoch,
 		block, err := api.StoreHandler.GetBlockByEpochNumber(ctx, &epoch, includeTxs)
 
 		logger.WithError(err).Debug("Delegated `cfx_getBlockByEpochNumber` to store handler")
-		api.collectHitStats("cfx_getBlockByEpochNumber", err == nil)
+		api.collectHitStats(ctx, "cfx_getBlockByEpochNumber", err == nil)
 
 		if err == nil {
 			return block, nil
@@ -200,7 +201,7 @@ func (api *cfxAPI) GetBlockByBlockNumber(
 		block, err := api.StoreHandler.GetBlockByBlockNumber(ctx, blockNumer, includeTxs)
 
 		logger.WithError(err).Debug("Delegated `cfx_getBlockByBlockNumber` to store handler")
-		api.collectHitStats("cfx_getBlockByBlockNumber", err == nil)
+		api.collectHitStats(ctx, "cfx_getBlockByBlockNumber", err == nil)
 
 		if err == nil {
 			return block, nil
@@ -275,7 +276,7 @@ func (api *cfxAPI) GetLogs(ctx context.Context, filter types.LogFilter) ([]types
 			"filter": filter, "hitStore": hitStore,
 		}).WithError(err).Debug("Delegated `cfx_getLogs` to log api handler")
 
-		api.collectHitStats("cfx_getLogs", hitStore)
+		api.collectHitStats(ctx, "cfx_getLogs", hitStore)
 
 		if logs == nil { // uniform empty logs
 			logs = emptyLogs
@@ -362,7 +363,7 @@ func (api *cfxAPI) GetTransactionByHash(ctx context.Context, txHash types.Hash)
 		txn, err := api.StoreHandler.GetTransactionByHash(ctx, txHash)
 
 		logger.WithError(err).Debug("Delegated `cfx_getTransactionByHash` to store handler")
-		api.collectHitStats("cfx_getTransactionByHash", err == nil)
+		api.collectHitStats(ctx, "cfx_getTransactionByHash", err == nil)
 
 		if err == nil {
 			return txn, nil
@@ -399,7 +400,7 @@ func (api *cfxAPI) GetBlocksByEpoch(ctx context.Context, epoch types.Epoch) ([]t
 		blocks, err := api.StoreHandler.GetBlocksByEpoch(ctx, &epoch)
 
 		logger.WithError(err).Debug("Delegated `cfx_getBlocksByEpoch` to store handler")
-		api.collectHitStats("cfx_getBlocksByEpoch", err == nil)
+		api.collectHitStats(ctx, "cfx_getBlocksByEpoch", err == nil)
 
 		if err == nil {
 			return blocks, nil
@@ -424,7 +425,7 @@ func (api *cfxAPI) GetTransactionReceipt(ctx context.Context, txHash types.Hash)
 		rcpt, err := api.StoreHandler.GetTransactionReceipt(ctx, txHash)
 
 		logger.WithError(err).Debug("Delegated `cfx_getTransactionReceipt` to store handler")
-		api.collectHitStats("cfx_getTransactionReceipt", err == nil)
+		api.collectHitStats(ctx, "cfx_getTransactionReceipt", err == nil)
 
 		if err == nil {
 			return rcpt, nil
@@ -532,6 +533,7 @@ func (api *cfxAPI) metricLogFilter(cfx sdk.ClientOperator, filter *types.LogFilt
 	}
 }
 
-func (h *cfxAPI) collectHitStats(method string, hit bool) {
+func (h *cfxAPI) collectHitStats(ctx context.Context, method string, hit bool) {
 	metrics.Registry.RPC.StoreHit(method, "store").Mark(hit)
+	tracing.SetAttributes(ctx, tracing.AttrStoreHit.Bool(hit))
 }
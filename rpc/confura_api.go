@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/rpc-gateway/rpc/handler"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/types"
+)
+
+// confuraAPI provides gateway specific evm space API.
+type confuraAPI struct {
+	txTracker *handler.EthTxTracker
+}
+
+func newConfuraAPI(txTracker *handler.EthTxTracker) *confuraAPI {
+	return &confuraAPI{txTracker: txTracker}
+}
+
+// GetTransactionStatus returns the tracked status of transaction submitted via gateway, or nil if
+// not submitted via gateway.
+func (api *confuraAPI) GetTransactionStatus(ctx context.Context, txHash common.Hash) (*types.TransactionStatus, error) {
+	sub, ok, err := api.txTracker.GetStatus(txHash)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return convertTxSubmission(sub), nil
+}
+
+func convertTxSubmission(sub *store.TxSubmission) *types.TransactionStatus {
+	status := &types.TransactionStatus{
+		Hash:         common.HexToHash(sub.Hash),
+		Status:       string(sub.Status),
+		Nodes:        strings.Split(sub.Nodes, ","),
+		Rebroadcasts: sub.Rebroadcasts,
+		SubmittedAt:  sub.SubmittedAt,
+		BroadcastAt:  sub.BroadcastAt,
+		PendingAt:    sub.PendingAt,
+		ConcludedAt:  sub.ConcludedAt,
+		CheckedAt:    sub.CheckedAt,
+	}
+
+	if sub.Status == store.TxStatusMined {
+		status.BlockNumber = (*hexutil.Uint64)(&sub.BlockNumber)
+	}
+
+	if len(sub.ReplacedBy) > 0 {
+		replacedBy := common.HexToHash(sub.ReplacedBy)
+		status.ReplacedBy = &replacedBy
+	}
+
+	return status
+}
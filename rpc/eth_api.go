@@ -18,6 +18,9 @@ import (
 	"github.com/scroll-tech/rpc-gateway/store"
 	"github.com/scroll-tech/rpc-gateway/util"
 	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/scroll-tech/rpc-gateway/util/pubsub"
+	"github.com/scroll-tech/rpc-gateway/util/relay"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
 	"github.com/sirupsen/logrus"
 )
 
@@ -34,10 +37,56 @@ var (
 type EthAPIOption struct {
 	StoreHandler  *handler.EthStoreHandler
 	LogApiHandler *handler.EthLogsApiHandler
+	// broker to subscribe synced event logs, which are used to serve `logs` subscription
+	// by the gateway rather than the fullnode.
+	LogsBroker pubsub.Broker
+	// gas station to serve fee history and priority fee from the blocks and receipts persisted
+	// in store rather than the fullnode.
+	GasStationHandler *handler.EthGasStationHandler
+	// relayer to broadcast raw transactions to node pool asynchronously
+	Relayer *relay.TxnRelayer
+	// tracker to track the status of raw transactions submitted via gateway
+	TxTracker *handler.EthTxTracker
+	// node manager to provide the sync status of fullnodes for `syncing` subscription
+	SyncStatusProvider node.SyncStatusProvider
+}
+
+func updateEthStoreHitRatio(ctx context.Context, method string, hit bool) {
+	metrics.Registry.RPC.StoreHit(method, "store").Mark(hit)
+	tracing.SetAttributes(ctx, tracing.AttrStoreHit.Bool(hit))
 }
 
-func updateEthStoreHitRatio(method string, hit bool) {
-	metrics.Registry.RPC.StoreHit(method, "store").Mark(hit)
+// isEthStoreTxnEnabled returns true if both blocks and transactions persisted in store, since
+// transactions are indexed by block summary.
+func isEthStoreTxnEnabled() bool {
+	conf := store.EthStoreConfig()
+	return !conf.IsChainBlockDisabled() && !conf.IsChainTxnDisabled()
+}
+
+// getEthBlockReceipts returns block receipts from store if hit, otherwise delegates to fullnode.
+func getEthBlockReceipts(
+	ctx context.Context, storeHandler *handler.EthStoreHandler,
+	method string, blockNumOrHash *web3Types.BlockNumberOrHash,
+) ([]web3Types.Receipt, error) {
+	logger := logrus.WithField("blockNumOrHash", blockNumOrHash)
+
+	conf := store.EthStoreConfig()
+	storeEnabled := !conf.IsChainBlockDisabled() && !conf.IsChainReceiptDisabled()
+
+	if storeEnabled && !util.IsInterfaceValNil(storeHandler) {
+		receipts, err := storeHandler.GetBlockReceipts(ctx, blockNumOrHash)
+		updateEthStoreHitRatio(ctx, method, err == nil)
+		if err == nil {
+			logger.Debugf("Loading eth data for %v hit in the store", method)
+			return receipts, nil
+		}
+
+		logger.WithError(err).Debugf("Loading eth data for %v missed from the ethstore", method)
+	}
+
+	logger.Debugf("Delegating %v rpc request to fullnode", method)
+
+	return GetEthClientFromContext(ctx).Parity.BlockReceipts(blockNumOrHash)
 }
 
 // ethAPI provides Ethereum relative API within evm space according to:
@@ -47,11 +96,15 @@ type ethAPI struct {
 
 	provider         *node.EthClientProvider
 	inputBlockMetric metrics.InputBlockMetric
+	filterManager    *ethFilterManager // gateway managed filters
 
 	hardforkBlockNumber *rpc.BlockNumber // return default value before eSpace hardfork
+
+	txPrechecker *ethTxPrechecker  // nil if precheck disabled
+	pendingTxs   *ethPendingTxFeed // feed for `newPendingTransactions` subscription
 }
 
-func mustNewEthAPI(provider *node.EthClientProvider, option ...EthAPIOption) *ethAPI {
+func mustNewEthAPI(ctx context.Context, provider *node.EthClientProvider, option ...EthAPIOption) *ethAPI {
 	client, err := provider.GetClientRandom()
 	if err != nil {
 		logrus.WithError(err).Fatal("Failed to get eth client randomly")
@@ -76,11 +129,20 @@ func mustNewEthAPI(provider *node.EthClientProvider, option ...EthAPIOption) *et
 		opt = option[0]
 	}
 
-	return &ethAPI{
+	api := &ethAPI{
 		EthAPIOption:        opt,
 		provider:            provider,
+		filterManager:       newEthFilterManager(ctx),
 		hardforkBlockNumber: hardforkBlockNumber,
+		pendingTxs:          newEthPendingTxFeed(ctx, mustNewEthPubSubConfigFromViper(), provider),
+	}
+
+	if prechecker, ok := mustNewEthTxPrecheckerFromViper(*chainId); ok {
+		logrus.Info("EVM space transaction precheck enabled")
+		api.txPrechecker = prechecker
 	}
+
+	return api
 }
 
 // GetBlockByHash returns the requested block. When fullTx is true all transactions in
@@ -96,7 +158,7 @@ func (api *ethAPI) GetBlockByHash(
 
 	if !store.EthStoreConfig().IsChainBlockDisabled() && !util.IsInterfaceValNil(api.StoreHandler) {
 		block, err := api.StoreHandler.GetBlockByHash(ctx, blockHash, fullTx)
-		updateEthStoreHitRatio("eth_getBlockByHash", err == nil)
+		updateEthStoreHitRatio(ctx, "eth_getBlockByHash", err == nil)
 		if err == nil {
 			logger.Debug("Loading eth data for eth_getBlockByHash hit in the store")
 			return block, nil
@@ -152,7 +214,7 @@ func (api *ethAPI) GetBlockByNumber(
 
 	if !store.EthStoreConfig().IsChainBlockDisabled() && !util.IsInterfaceValNil(api.StoreHandler) {
 		block, err := api.StoreHandler.GetBlockByNumber(ctx, &blockNum, fullTx)
-		updateEthStoreHitRatio("eth_getBlockByNumber", err == nil)
+		updateEthStoreHitRatio(ctx, "eth_getBlockByNumber", err == nil)
 		if err == nil {
 			logger.Debug("Loading eth data for eth_getBlockByNumber hit in the store")
 			return block, nil
@@ -232,13 +294,74 @@ func (api *ethAPI) GetTransactionCount(
 // contract address after the transaction has been mined.
 func (api *ethAPI) SendRawTransaction(ctx context.Context, signedTx hexutil.Bytes) (common.Hash, error) {
 	w3c := GetEthClientFromContext(ctx)
-	return w3c.Eth.SendRawTransaction(signedTx)
+	if err := api.precheckTransaction(w3c, signedTx); err != nil {
+		return common.Hash{}, err
+	}
+
+	txHash, err := w3c.Eth.SendRawTransaction(signedTx)
+	api.relayTransaction(signedTx, err)
+	api.publishPendingTransaction(signedTx, err)
+	api.trackTransaction(w3c, signedTx, txHash, err)
+
+	return txHash, err
 }
 
 // SubmitTransaction is an alias of `SendRawTransaction` method.
 func (api *ethAPI) SubmitTransaction(ctx context.Context, signedTx hexutil.Bytes) (common.Hash, error) {
 	w3c := GetEthClientFromContext(ctx)
-	return w3c.Eth.SubmitTransaction(signedTx)
+	if err := api.precheckTransaction(w3c, signedTx); err != nil {
+		return common.Hash{}, err
+	}
+
+	txHash, err := w3c.Eth.SubmitTransaction(signedTx)
+	api.relayTransaction(signedTx, err)
+	api.publishPendingTransaction(signedTx, err)
+	api.trackTransaction(w3c, signedTx, txHash, err)
+
+	return txHash, err
+}
+
+// precheckTransaction validates raw transaction by gateway before forwarding to fullnode.
+func (api *ethAPI) precheckTransaction(w3c *node.Web3goClient, signedTx hexutil.Bytes) error {
+	if api.txPrechecker == nil {
+		return nil
+	}
+
+	return api.txPrechecker.Check(w3c, signedTx)
+}
+
+// relayTransaction relays raw transaction broadcasting asynchronously if accepted by fullnode.
+func (api *ethAPI) relayTransaction(signedTx hexutil.Bytes, err error) {
+	if err == nil && api.Relayer != nil {
+		if !api.Relayer.AsyncRelay(signedTx) {
+			logrus.Info("EVM space transaction relay pool is full, dropping transaction relay")
+		}
+	}
+}
+
+// publishPendingTransaction publishes raw transaction to `newPendingTransactions` subscriptions
+// if accepted by fullnode.
+func (api *ethAPI) publishPendingTransaction(signedTx hexutil.Bytes, err error) {
+	if err != nil {
+		return
+	}
+
+	if err := api.pendingTxs.PublishRaw(signedTx); err != nil {
+		logrus.WithError(err).Debug("Failed to publish EVM space pending transaction")
+	}
+}
+
+// trackTransaction records raw transaction to track its status if accepted by fullnode.
+func (api *ethAPI) trackTransaction(
+	w3c *node.Web3goClient, signedTx hexutil.Bytes, txHash common.Hash, err error,
+) {
+	if err != nil || api.TxTracker == nil {
+		return
+	}
+
+	if err := api.TxTracker.Track(signedTx, txHash, clientNodeName(w3c)); err != nil {
+		logrus.WithField("txHash", txHash).WithError(err).Info("Failed to track EVM space transaction")
+	}
 }
 
 // Call executes a new message call immediately without creating a transaction on the block chain.
@@ -269,7 +392,7 @@ func (api *ethAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (
 
 	if !store.EthStoreConfig().IsChainTxnDisabled() && !util.IsInterfaceValNil(api.StoreHandler) {
 		tx, err := api.StoreHandler.GetTransactionByHash(ctx, hash)
-		updateEthStoreHitRatio("eth_getTransactionByHash", err == nil)
+		updateEthStoreHitRatio(ctx, "eth_getTransactionByHash", err == nil)
 		if err == nil {
 			logger.Debug("Loading eth data for eth_getTransactionByHash hit in the store")
 			return tx, nil
@@ -291,7 +414,7 @@ func (api *ethAPI) GetTransactionReceipt(ctx context.Context, txHash common.Hash
 
 	if !store.EthStoreConfig().IsChainReceiptDisabled() && !util.IsInterfaceValNil(api.StoreHandler) {
 		tx, err := api.StoreHandler.GetTransactionReceipt(ctx, txHash)
-		updateEthStoreHitRatio("eth_getTransactionReceipt", err == nil)
+		updateEthStoreHitRatio(ctx, "eth_getTransactionReceipt", err == nil)
 		if err == nil {
 			logger.Debug("Loading eth data for eth_getTransactionReceipt hit in the ethstore")
 			return tx, nil
@@ -316,17 +439,23 @@ func (api *ethAPI) GetLogs(ctx context.Context, filter web3Types.FilterQuery) ([
 	w3c := GetEthClientFromContext(ctx)
 	api.metricLogFilter(w3c.Eth, &filter)
 
-	flag, ok := store.ParseEthLogFilterType(&filter)
+	return api.getLogs(ctx, w3c, &filter)
+}
+
+func (api *ethAPI) getLogs(
+	ctx context.Context, w3c *node.Web3goClient, filter *web3Types.FilterQuery,
+) ([]web3Types.Log, error) {
+	flag, ok := store.ParseEthLogFilterType(filter)
 	if !ok {
 		return ethEmptyLogs, errInvalidEthLogFilter
 	}
 
-	if err := api.normalizeLogFilter(w3c.Client, flag, &filter); err != nil {
+	if err := api.normalizeLogFilter(w3c.Client, flag, filter); err != nil {
 		return ethEmptyLogs, err
 	}
 
-	if err := api.validateLogFilter(flag, &filter); err != nil {
-		api.filterLogger(&filter).
+	if err := api.validateLogFilter(flag, filter); err != nil {
+		api.filterLogger(filter).
 			WithError(err).
 			Debug("Invalid log filter parameter for eth_getLogs rpc request")
 
@@ -339,13 +468,13 @@ func (api *ethAPI) GetLogs(ctx context.Context, filter web3Types.FilterQuery) ([
 	}
 
 	if api.LogApiHandler != nil {
-		logs, hitStore, err := api.LogApiHandler.GetLogs(ctx, w3c.Client.Eth, &filter)
+		logs, hitStore, err := api.LogApiHandler.GetLogs(ctx, w3c.Client.Eth, filter)
 
-		api.filterLogger(&filter).WithField("hitStore", hitStore).
+		api.filterLogger(filter).WithField("hitStore", hitStore).
 			WithError(err).
 			Debug("Delegated `eth_getLogs` to log api handler")
 
-		updateEthStoreHitRatio("eth_getLogs", hitStore)
+		updateEthStoreHitRatio(ctx, "eth_getLogs", hitStore)
 
 		if logs == nil { // uniform empty logs
 			logs = ethEmptyLogs
@@ -356,13 +485,28 @@ func (api *ethAPI) GetLogs(ctx context.Context, filter web3Types.FilterQuery) ([
 
 	// fail over to fullnode if no handler configured
 
-	api.filterLogger(&filter).
+	api.filterLogger(filter).
 		Debug("Fail over `eth_getLogs` to fullnode due to no API handler configured")
-	return w3c.Eth.Logs(filter)
+	return w3c.Eth.Logs(*filter)
 }
 
 // GetBlockTransactionCountByHash returns the total number of transactions in the given block.
 func (api *ethAPI) GetBlockTransactionCountByHash(ctx context.Context, blockHash common.Hash) (*hexutil.Big, error) {
+	logger := logrus.WithField("blockHash", blockHash.Hex())
+
+	if !store.EthStoreConfig().IsChainBlockDisabled() && !util.IsInterfaceValNil(api.StoreHandler) {
+		count, err := api.StoreHandler.GetBlockTransactionCountByHash(ctx, blockHash)
+		updateEthStoreHitRatio(ctx, "eth_getBlockTransactionCountByHash", err == nil)
+		if err == nil {
+			logger.Debug("Loading eth data for eth_getBlockTransactionCountByHash hit in the store")
+			return count, nil
+		}
+
+		logger.WithError(err).Debug("Loading eth data for eth_getBlockTransactionCountByHash missed from the ethstore")
+	}
+
+	logger.Debug("Delegating eth_getBlockTransactionCountByHash rpc request to fullnode")
+
 	w3c := GetEthClientFromContext(ctx)
 	count, err := w3c.Eth.BlockTransactionCountByHash(blockHash)
 	return (*hexutil.Big)(count), err
@@ -373,8 +517,24 @@ func (api *ethAPI) GetBlockTransactionCountByHash(ctx context.Context, blockHash
 func (api *ethAPI) GetBlockTransactionCountByNumber(ctx context.Context, blockNum web3Types.BlockNumber) (
 	*hexutil.Big, error,
 ) {
+	logger := logrus.WithField("blockNum", blockNum)
+
 	w3c := GetEthClientFromContext(ctx)
 	api.inputBlockMetric.Update1(&blockNum, "eth_getBlockTransactionCountByNumber", w3c.Eth)
+
+	if !store.EthStoreConfig().IsChainBlockDisabled() && !util.IsInterfaceValNil(api.StoreHandler) {
+		count, err := api.StoreHandler.GetBlockTransactionCountByNumber(ctx, &blockNum)
+		updateEthStoreHitRatio(ctx, "eth_getBlockTransactionCountByNumber", err == nil)
+		if err == nil {
+			logger.Debug("Loading eth data for eth_getBlockTransactionCountByNumber hit in the store")
+			return count, nil
+		}
+
+		logger.WithError(err).Debug("Loading eth data for eth_getBlockTransactionCountByNumber missed from the ethstore")
+	}
+
+	logger.Debug("Delegating eth_getBlockTransactionCountByNumber rpc request to fullnode")
+
 	count, err := w3c.Eth.BlockTransactionCountByNumber(blockNum)
 	return (*hexutil.Big)(count), err
 }
@@ -409,6 +569,15 @@ func (api *ethAPI) Mining(ctx context.Context) (bool, error) {
 // MaxPriorityFeePerGas returns a fee per gas that is an estimate of how much you can pay as
 // a priority fee, or "tip", to get a transaction included in the current block.
 func (api *ethAPI) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	if !util.IsInterfaceValNil(api.GasStationHandler) {
+		priorityFee, err := api.GasStationHandler.MaxPriorityFeePerGas()
+		updateEthStoreHitRatio(ctx, "eth_maxPriorityFeePerGas", err == nil)
+
+		if err == nil {
+			return priorityFee, nil
+		}
+	}
+
 	w3c := GetEthClientFromContext(ctx)
 	priorityFee, err := w3c.Eth.MaxPriorityFeePerGas()
 	return (*hexutil.Big)(priorityFee), err
@@ -442,6 +611,21 @@ func (api *ethAPI) GetUncleByBlockHashAndIndex(
 func (api *ethAPI) GetTransactionByBlockHashAndIndex(
 	ctx context.Context, hash common.Hash, index hexutil.Uint,
 ) (*web3Types.TransactionDetail, error) {
+	logger := logrus.WithFields(logrus.Fields{"blockHash": hash.Hex(), "index": index})
+
+	if isEthStoreTxnEnabled() && !util.IsInterfaceValNil(api.StoreHandler) {
+		tx, err := api.StoreHandler.GetTransactionByBlockHashAndIndex(ctx, hash, index)
+		updateEthStoreHitRatio(ctx, "eth_getTransactionByBlockHashAndIndex", err == nil)
+		if err == nil {
+			logger.Debug("Loading eth data for eth_getTransactionByBlockHashAndIndex hit in the store")
+			return tx, nil
+		}
+
+		logger.WithError(err).Debug("Loading eth data for eth_getTransactionByBlockHashAndIndex missed from the ethstore")
+	}
+
+	logger.Debug("Delegating eth_getTransactionByBlockHashAndIndex rpc request to fullnode")
+
 	w3c := GetEthClientFromContext(ctx)
 	return w3c.Eth.TransactionByBlockHashAndIndex(hash, uint(index))
 }
@@ -451,11 +635,35 @@ func (api *ethAPI) GetTransactionByBlockHashAndIndex(
 func (api *ethAPI) GetTransactionByBlockNumberAndIndex(
 	ctx context.Context, blockNum web3Types.BlockNumber, index hexutil.Uint,
 ) (*web3Types.TransactionDetail, error) {
+	logger := logrus.WithFields(logrus.Fields{"blockNum": blockNum, "index": index})
+
 	w3c := GetEthClientFromContext(ctx)
 	api.inputBlockMetric.Update1(&blockNum, "eth_getTransactionByBlockNumberAndIndex", w3c.Eth)
+
+	if isEthStoreTxnEnabled() && !util.IsInterfaceValNil(api.StoreHandler) {
+		tx, err := api.StoreHandler.GetTransactionByBlockNumberAndIndex(ctx, &blockNum, index)
+		updateEthStoreHitRatio(ctx, "eth_getTransactionByBlockNumberAndIndex", err == nil)
+		if err == nil {
+			logger.Debug("Loading eth data for eth_getTransactionByBlockNumberAndIndex hit in the store")
+			return tx, nil
+		}
+
+		logger.WithError(err).Debug("Loading eth data for eth_getTransactionByBlockNumberAndIndex missed from the ethstore")
+	}
+
+	logger.Debug("Delegating eth_getTransactionByBlockNumberAndIndex rpc request to fullnode")
+
 	return w3c.Eth.TransactionByBlockNumberAndIndex(blockNum, uint(index))
 }
 
+// GetBlockReceipts returns all the transaction receipts of the given block, which is the
+// same as `parity_getBlockReceipts`.
+func (api *ethAPI) GetBlockReceipts(
+	ctx context.Context, blockNumOrHash *web3Types.BlockNumberOrHash,
+) ([]web3Types.Receipt, error) {
+	return getEthBlockReceipts(ctx, api.StoreHandler, "eth_getBlockReceipts", blockNumOrHash)
+}
+
 func (api *ethAPI) normalizeLogFilter(w3c *web3go.Client, flag store.LogFilterType, filter *web3Types.FilterQuery) error {
 	// set default block range if not set and normalize block number if necessary
 	if flag&store.LogFilterTypeBlockRange != 0 {
@@ -536,6 +744,16 @@ func (api *ethAPI) FeeHistory(
 ) (*web3Types.FeeHistoryResult, error) {
 	w3c := GetEthClientFromContext(ctx)
 	api.inputBlockMetric.Update1(&lastBlock, "eth_feeHistory/lastBlock", w3c.Eth)
+
+	if !util.IsInterfaceValNil(api.GasStationHandler) {
+		result, err := api.GasStationHandler.FeeHistory(uint64(blockCount), lastBlock, rewardPercentiles)
+		updateEthStoreHitRatio(ctx, "eth_feeHistory", err == nil)
+
+		if err == nil {
+			return result, nil
+		}
+	}
+
 	return w3c.Eth.FeeHistory(blockCount, lastBlock, rewardPercentiles)
 }
 
@@ -546,11 +764,3 @@ func (api *ethAPI) GetProof(ctx context.Context, addr common.Address, storageKey
 	api.inputBlockMetric.Update2(&blockNrOrHash, "eth_getProof", w3c.Eth)
 	return w3c.Eth.GetProof(addr, storageKeys, &blockNrOrHash)
 }
-
-// The following RPC methods are not supported yet by the fullnode:
-// `eth_getFilterChanges`
-// `eth_getFilterLogs`
-// `eth_newBlockFilter`
-// `eth_newFilter`
-// `eth_newPendingTransactionFilter`
-// `eth_uninstallFilter`
@@ -0,0 +1,346 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	web3Types "github.com/openweb3/web3go/types"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/rpc/cache"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/scroll-tech/rpc-gateway/util/pubsub"
+	"github.com/sirupsen/logrus"
+)
+
+// eSpace filters are managed by the gateway rather than the fullnode, so that the filter
+// changes are consistent even if the client requests are routed to different fullnodes.
+
+const (
+	// max number of blocks to poll once for block filter changes, the rest will
+	// be polled next time.
+	maxEthFilterBlocksPerPoll = 32
+)
+
+var (
+	ethEmptyHashes = []common.Hash{}
+
+	errEthFilterBlockHashUnsupported = errors.New("block hash is not supported for filter")
+)
+
+// NewFilter creates a new filter and returns the filter id. It can be used to retrieve logs
+// when the state changes. This method cannot be used to fetch logs that are already stored
+// in the state.
+//
+// Note, the polled logs reverted due to chain reorg are polled again with `removed` marked
+// as true only if pub/sub broker configured, and the reverted logs are buffered between
+// polls, so that the overflowed ones will be dropped if not polled in time.
+func (api *ethAPI) NewFilter(ctx context.Context, crit web3Types.FilterQuery) (rpc.ID, error) {
+	flag, ok := store.ParseEthLogFilterType(&crit)
+	if !ok {
+		return "", errInvalidEthLogFilter
+	}
+
+	if flag&store.LogFilterTypeBlockHash != 0 {
+		return "", errEthFilterBlockHashUnsupported
+	}
+
+	if crit.FromBlock != nil && crit.ToBlock != nil &&
+		*crit.FromBlock > 0 && *crit.ToBlock > 0 && *crit.FromBlock > *crit.ToBlock {
+		return "", errInvalidLogFilterBlockRange
+	}
+
+	w3c := GetEthClientFromContext(ctx)
+
+	latestBlock, err := api.latestBlockNumber(w3c)
+	if err != nil {
+		return "", err
+	}
+
+	// only logs of blocks mined after the filter installed will be polled
+	cursor := latestBlock + 1
+	if crit.FromBlock != nil && *crit.FromBlock > 0 && uint64(*crit.FromBlock) > cursor {
+		cursor = uint64(*crit.FromBlock)
+	}
+
+	filter := &ethFilter{
+		typ: ethFilterTypeLog, owner: ethFilterOwner(ctx), crit: crit, cursor: cursor,
+	}
+
+	// watch the synced event logs and chain reorg to poll the reverted logs
+	if api.LogsBroker != nil {
+		filter.sub = api.LogsBroker.Subscribe(pubsub.TopicEthLogs)
+		filter.reorgSub = api.LogsBroker.Subscribe(pubsub.TopicEthReorg)
+	}
+
+	id, err := api.filterManager.install(filter)
+	if err != nil && filter.sub != nil {
+		filter.sub.Unsubscribe()
+		filter.reorgSub.Unsubscribe()
+	}
+
+	return id, err
+}
+
+// NewBlockFilter creates a filter that fetches blocks that are imported into the chain.
+func (api *ethAPI) NewBlockFilter(ctx context.Context) (rpc.ID, error) {
+	w3c := GetEthClientFromContext(ctx)
+
+	latestBlock, err := api.latestBlockNumber(w3c)
+	if err != nil {
+		return "", err
+	}
+
+	return api.filterManager.install(&ethFilter{
+		typ: ethFilterTypeBlock, owner: ethFilterOwner(ctx), cursor: latestBlock + 1,
+	})
+}
+
+// NewPendingTransactionFilter creates a filter that fetches pending transaction hashes
+// as transactions enter the pending state, which is fed by the same pending transactions
+// as the `newPendingTransactions` subscription.
+//
+// Note the pending transactions are buffered between polls, and the overflowed ones
+// will be dropped if not polled in time.
+func (api *ethAPI) NewPendingTransactionFilter(ctx context.Context) (rpc.ID, error) {
+	sub := api.pendingTxs.Subscribe()
+
+	id, err := api.filterManager.install(&ethFilter{
+		typ: ethFilterTypePendingTxn, owner: ethFilterOwner(ctx), sub: sub,
+	})
+	if err != nil {
+		sub.Unsubscribe()
+	}
+
+	return id, err
+}
+
+// GetFilterChanges returns the logs or hashes for the filter with the given id since
+// last time it was called. This can be used for polling.
+//
+// For pending transaction and block filters the result is []common.Hash.
+// For log filters the result is []Log.
+func (api *ethAPI) GetFilterChanges(ctx context.Context, id rpc.ID) (interface{}, error) {
+	filter, ok := api.filterManager.get(id, ethFilterOwner(ctx))
+	if !ok {
+		return nil, errEthFilterNotFound
+	}
+
+	// serialize polling to ensure the filter cursor moves forward consistently
+	filter.mu.Lock()
+	defer filter.mu.Unlock()
+
+	switch filter.typ {
+	case ethFilterTypeLog:
+		return api.getLogFilterChanges(ctx, GetEthClientFromContext(ctx), filter)
+	case ethFilterTypeBlock:
+		return api.getBlockFilterChanges(ctx, GetEthClientFromContext(ctx), filter)
+	case ethFilterTypePendingTxn:
+		return getPendingTxFilterChanges(filter), nil
+	default:
+		return ethEmptyHashes, nil
+	}
+}
+
+// GetFilterLogs returns the logs for the filter with the given id.
+func (api *ethAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]web3Types.Log, error) {
+	filter, ok := api.filterManager.get(id, ethFilterOwner(ctx))
+	if !ok || filter.typ != ethFilterTypeLog {
+		return ethEmptyLogs, errEthFilterNotFound
+	}
+
+	// make a copy since log filter will be normalized
+	crit := filter.crit
+
+	w3c := GetEthClientFromContext(ctx)
+	return api.getLogs(ctx, w3c, &crit)
+}
+
+// UninstallFilter removes the filter with the given filter id.
+func (api *ethAPI) UninstallFilter(ctx context.Context, id rpc.ID) (bool, error) {
+	return api.filterManager.uninstall(id, ethFilterOwner(ctx)), nil
+}
+
+func (api *ethAPI) getLogFilterChanges(
+	ctx context.Context, w3c *node.Web3goClient, filter *ethFilter,
+) ([]web3Types.Log, error) {
+	latestBlock, err := api.latestBlockNumber(w3c)
+	if err != nil {
+		return nil, err
+	}
+
+	// the polled logs reverted due to chain reorg if any, and the reverted blocks will be polled again
+	removedLogs := getRemovedLogFilterChanges(filter)
+
+	fromBlock, toBlock := filter.cursor, latestBlock
+	if filter.crit.ToBlock != nil && *filter.crit.ToBlock >= 0 {
+		toBlock = util.MinUint64(toBlock, uint64(*filter.crit.ToBlock))
+	}
+
+	// no new blocks, or the fullnode routed to falls behind
+	if fromBlock > toBlock {
+		if len(removedLogs) > 0 {
+			return removedLogs, nil
+		}
+
+		return ethEmptyLogs, nil
+	}
+
+	// restrict the block range to poll once, the rest will be polled next time
+	if toBlock-fromBlock+1 > store.MaxLogBlockRange {
+		toBlock = fromBlock + store.MaxLogBlockRange - 1
+	}
+
+	bnFrom, bnTo := web3Types.BlockNumber(fromBlock), web3Types.BlockNumber(toBlock)
+	logs, err := api.getLogs(ctx, w3c, &web3Types.FilterQuery{
+		FromBlock: &bnFrom,
+		ToBlock:   &bnTo,
+		Addresses: filter.crit.Addresses,
+		Topics:    filter.crit.Topics,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	filter.cursor = toBlock + 1
+	return append(removedLogs, logs...), nil
+}
+
+// getRemovedLogFilterChanges drains the synced event logs and chain reorg buffered since last poll,
+// and returns the polled logs that have been reverted, with the filter cursor rewound to poll the
+// reverted blocks again.
+func getRemovedLogFilterChanges(filter *ethFilter) []web3Types.Log {
+	if filter.sub == nil {
+		return nil
+	}
+
+	var removedLogs []web3Types.Log
+
+	// the reverted logs are published prior to chain reorg
+	drainEthFilterSub(filter.sub, func(msg []byte) {
+		var logs []web3Types.Log
+		if err := json.Unmarshal(msg, &logs); err != nil {
+			logrus.WithError(err).Error("Failed to unmarshal event logs for filter changes")
+			return
+		}
+
+		for i := range logs {
+			// only the logs of polled blocks
+			if logs[i].Removed && logs[i].BlockNumber < filter.cursor &&
+				matchEthPubSubLogFilter(&logs[i], &filter.crit) {
+				removedLogs = append(removedLogs, logs[i])
+			}
+		}
+	})
+
+	drainEthFilterSub(filter.reorgSub, func(msg []byte) {
+		var reorg pubsub.ReorgMessage
+		if err := json.Unmarshal(msg, &reorg); err != nil {
+			logrus.WithError(err).Error("Failed to unmarshal chain reorg for filter changes")
+			return
+		}
+
+		revertTo := reorg.RevertTo
+		if filter.crit.FromBlock != nil && *filter.crit.FromBlock > 0 {
+			revertTo = util.MaxUint64(revertTo, uint64(*filter.crit.FromBlock))
+		}
+
+		filter.cursor = util.MinUint64(filter.cursor, revertTo)
+	})
+
+	return removedLogs
+}
+
+func (api *ethAPI) getBlockFilterChanges(
+	ctx context.Context, w3c *node.Web3goClient, filter *ethFilter,
+) ([]common.Hash, error) {
+	latestBlock, err := api.latestBlockNumber(w3c)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := []common.Hash{}
+	for bn := filter.cursor; bn <= latestBlock && len(hashes) < maxEthFilterBlocksPerPoll; bn++ {
+		block, err := api.getBlockSummaryByNumber(ctx, w3c, web3Types.BlockNumber(bn))
+		if err != nil {
+			if len(hashes) > 0 { // return polled changes, the rest will be polled next time
+				break
+			}
+
+			return nil, err
+		}
+
+		if block == nil { // the fullnode routed to falls behind
+			break
+		}
+
+		hashes = append(hashes, block.Hash)
+		filter.cursor = bn + 1
+	}
+
+	return hashes, nil
+}
+
+// getPendingTxFilterChanges drains the pending transactions buffered since last poll.
+func getPendingTxFilterChanges(filter *ethFilter) []common.Hash {
+	hashes := []common.Hash{}
+
+	drainEthFilterSub(filter.sub, func(msg []byte) {
+		var tx struct{ Hash common.Hash }
+		if err := json.Unmarshal(msg, &tx); err != nil {
+			logrus.WithError(err).Error("Failed to unmarshal pending transaction for filter changes")
+			return
+		}
+
+		hashes = append(hashes, tx.Hash)
+	})
+
+	return hashes
+}
+
+// drainEthFilterSub handles the messages buffered in subscription without blocking.
+func drainEthFilterSub(sub pubsub.Subscription, handle func(msg []byte)) {
+	for {
+		select {
+		case msg, ok := <-sub.Chan():
+			if !ok {
+				return
+			}
+
+			handle(msg)
+		default:
+			return
+		}
+	}
+}
+
+// getBlockSummaryByNumber gets block summary from store at first, otherwise delegates to fullnode.
+func (api *ethAPI) getBlockSummaryByNumber(
+	ctx context.Context, w3c *node.Web3goClient, blockNum web3Types.BlockNumber,
+) (*web3Types.Block, error) {
+	if !store.EthStoreConfig().IsChainBlockDisabled() && !util.IsInterfaceValNil(api.StoreHandler) {
+		block, err := api.StoreHandler.GetBlockByNumber(ctx, &blockNum, false)
+		if err == nil {
+			return block, nil
+		}
+
+		logrus.WithField("blockNum", blockNum).
+			WithError(err).
+			Debug("Loading eth block summary for filter changes missed from the ethstore")
+	}
+
+	return w3c.Eth.BlockByNumber(blockNum, false)
+}
+
+// latestBlockNumber returns the latest block number of the fullnode routed to.
+func (api *ethAPI) latestBlockNumber(w3c *node.Web3goClient) (uint64, error) {
+	blockNum, err := cache.EthDefault.GetBlockNumber(w3c)
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get latest block number")
+	}
+
+	return blockNum.ToInt().Uint64(), nil
+}
@@ -2,12 +2,15 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/openweb3/go-rpc-provider"
 	"github.com/openweb3/web3go/types"
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/node"
 	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/scroll-tech/rpc-gateway/util/pubsub"
 	rpcutil "github.com/scroll-tech/rpc-gateway/util/rpc"
 	"github.com/sirupsen/logrus"
 )
@@ -15,8 +18,7 @@ import (
 // eSpace PubSub notification
 // TODO:
 // 1. restrict total sessions and sessions per IP, otherwise it maybe susceptible
-// to flooding attack;
-// 2. `newPendingTransactions` and `syncing` are not implemented in the fullnode yet.
+// to flooding attack.
 
 // NewHeads send a notification each time a new header (block) is appended to the chain.
 func (api *ethAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
@@ -82,6 +84,10 @@ func (api *ethAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 func (api *ethAPI) Logs(ctx context.Context, filter types.FilterQuery) (*rpc.Subscription, error) {
 	metrics.Registry.PubSub.InputLogFilter("eth").Mark(!isEmptyEthLogFilter(filter))
 
+	if api.LogsBroker != nil { // served by the gateway
+		return api.serveLogs(ctx, filter)
+	}
+
 	psCtx, supported, err := api.pubsubCtxFromContext(ctx)
 	if !supported {
 		logrus.WithError(err).Error("Logs pubsub notification unsupported")
@@ -139,6 +145,120 @@ func (api *ethAPI) Logs(ctx context.Context, filter types.FilterQuery) (*rpc.Sub
 	return rpcSub, nil
 }
 
+// serveLogs serves logs subscription with the synced event logs dispatched by the broker, including
+// the reverted event logs with `removed` field marked as true due to chain reorg.
+func (api *ethAPI) serveLogs(ctx context.Context, filter types.FilterQuery) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		logrus.Error("Logs pubsub notification unsupported")
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	bSub := api.LogsBroker.Subscribe(pubsub.TopicEthLogs)
+
+	logger := logrus.WithField("rpcSubID", rpcSub.ID)
+
+	counter := metrics.Registry.PubSub.Sessions("eth", "logs", "gateway")
+	counter.Inc(1)
+
+	go func() {
+		defer bSub.Unsubscribe()
+		defer counter.Dec(1)
+
+		for {
+			select {
+			case msg, ok := <-bSub.Chan():
+				if !ok {
+					logger.Debug("Logs pubsub broker subscription closed")
+					return
+				}
+
+				var logs []types.Log
+				if err := json.Unmarshal(msg, &logs); err != nil {
+					logger.WithError(err).Error("Failed to unmarshal logs from pubsub broker")
+					continue
+				}
+
+				for i := range logs {
+					if matchEthPubSubLogFilter(&logs[i], &filter) {
+						notifier.Notify(rpcSub.ID, &logs[i])
+					}
+				}
+
+			case err := <-rpcSub.Err():
+				logger.WithError(err).Debug("Logs pubsub subscription error")
+				return
+
+			case <-notifier.Closed():
+				logger.Debug("Logs pubsub connection closed")
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// NewPendingTransactions creates a subscription that is triggered each time a transaction enters
+// the transaction pool, which is served by the gateway with the transactions relayed by itself
+// and polled from the pool of fullnodes. If fullTx is true the full transaction is sent to the
+// client, otherwise the hash only.
+func (api *ethAPI) NewPendingTransactions(ctx context.Context, fullTx *bool) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		logrus.Error("NewPendingTransactions pubsub notification unsupported")
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	fSub := api.pendingTxs.Subscribe()
+	full := fullTx != nil && *fullTx
+
+	logger := logrus.WithField("rpcSubID", rpcSub.ID)
+
+	counter := metrics.Registry.PubSub.Sessions("eth", "new_pending_txs", "gateway")
+	counter.Inc(1)
+
+	go func() {
+		defer fSub.Unsubscribe()
+		defer counter.Dec(1)
+
+		for {
+			select {
+			case msg, ok := <-fSub.Chan():
+				if !ok {
+					logger.Debug("NewPendingTransactions pubsub feed subscription closed")
+					return
+				}
+
+				if full {
+					notifier.Notify(rpcSub.ID, json.RawMessage(msg))
+					continue
+				}
+
+				var tx struct{ Hash common.Hash }
+				if err := json.Unmarshal(msg, &tx); err != nil {
+					logger.WithError(err).Error("Failed to unmarshal pending transaction from pubsub feed")
+					continue
+				}
+
+				notifier.Notify(rpcSub.ID, tx.Hash)
+
+			case err := <-rpcSub.Err():
+				logger.WithError(err).Debug("NewPendingTransactions pubsub subscription error")
+				return
+
+			case <-notifier.Closed():
+				logger.Debug("NewPendingTransactions pubsub connection closed")
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 type epubsubContext struct {
 	notifier  *rpc.Notifier
 	rpcClient *rpc.Client
@@ -0,0 +1,145 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/openweb3/go-rpc-provider"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/rpc/cache"
+	"github.com/sirupsen/logrus"
+)
+
+type ethArchiveConfig struct {
+	// whether to route historical state queries to archive fullnodes
+	Enabled bool
+	// number of blocks below the latest block, beyond which state might be pruned by fullnodes
+	Depth uint64 `default:"128"`
+	// error messages that indicate the historical state is pruned by fullnode
+	StateMissingErrors []string `default:"[missing trie node,state is not available,required historical state unavailable]"`
+}
+
+// ethArchiveConf is the archive routing configurations of evm space, nil if disabled.
+var ethArchiveConf *ethArchiveConfig
+
+// ethArchiveMethods are evm space RPC methods that query historical state, along with the
+// index of block number or hash parameter.
+var ethArchiveMethods = map[string]int{
+	"eth_call":                1,
+	"eth_estimateGas":         1,
+	"eth_getBalance":          1,
+	"eth_getCode":             1,
+	"eth_getTransactionCount": 1,
+	"eth_getStorageAt":        2,
+	"eth_getProof":            2,
+}
+
+func mustNewEthArchiveConfigFromViper() (*ethArchiveConfig, bool) {
+	var config ethArchiveConfig
+	viper.MustUnmarshalKey("ethrpc.archive", &config)
+
+	if !config.Enabled {
+		return nil, false
+	}
+
+	return &config, true
+}
+
+// ethArchiveMiddleware routes evm space historical state queries to archive fullnodes if the
+// requested block is deep below the latest block, or retries on archive fullnodes if the
+// requested fullnode responds that historical state is missing.
+func ethArchiveMiddleware(next rpc.HandleCallMsgFunc) rpc.HandleCallMsgFunc {
+	return func(ctx context.Context, msg *rpc.JsonRpcMessage) *rpc.JsonRpcMessage {
+		index, ok := ethArchiveMethods[msg.Method]
+		if !ok {
+			return next(ctx, msg)
+		}
+
+		provider, ok := ctx.Value(ctxKeyClientProvider).(*node.EthClientProvider)
+		if !ok {
+			return next(ctx, msg)
+		}
+
+		archived := false
+		if isEthArchiveBlock(ctx, msg, index) {
+			ctx, archived = withEthArchiveClient(ctx, provider)
+		}
+
+		resp := next(ctx, msg)
+		if archived || resp == nil || resp.Error == nil || !isEthStateMissingError(resp.Error) {
+			return resp
+		}
+
+		if ctx, archived = withEthArchiveClient(ctx, provider); !archived {
+			return resp
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"method": msg.Method,
+			"params": string(msg.Params),
+		}).WithError(resp.Error).Debug("Retry historical state query on archive fullnode")
+
+		return next(ctx, msg)
+	}
+}
+
+// isEthArchiveBlock checks if the requested block is deep enough below the latest block of the
+// requested fullnode, whose state might have been pruned.
+func isEthArchiveBlock(ctx context.Context, msg *rpc.JsonRpcMessage, index int) bool {
+	var params []json.RawMessage
+	if err := json.Unmarshal(msg.Params, &params); err != nil || index >= len(params) {
+		return false
+	}
+
+	var bnh rpc.BlockNumberOrHash
+	if err := json.Unmarshal(params[index], &bnh); err != nil {
+		return false
+	}
+
+	// block tags other than `earliest` are regarded as the latest
+	bn, ok := bnh.Number()
+	if !ok || (bn < 0 && bn != rpc.EarliestBlockNumber) {
+		return false
+	}
+
+	if bn == rpc.EarliestBlockNumber {
+		return true
+	}
+
+	w3c, ok := ctx.Value(ctxKeyClient).(*node.Web3goClient)
+	if !ok {
+		return false
+	}
+
+	latestBlock, err := cache.EthDefault.GetBlockNumber(w3c)
+	if err != nil {
+		return false
+	}
+
+	return uint64(bn)+ethArchiveConf.Depth < latestBlock.ToInt().Uint64()
+}
+
+// withEthArchiveClient replaces the client in context with the one of archive fullnode.
+func withEthArchiveClient(ctx context.Context, provider *node.EthClientProvider) (context.Context, bool) {
+	client, err := provider.GetClientByIPGroup(ctx, node.GroupEthArchives)
+	if err != nil {
+		logrus.WithError(err).Debug("Failed to get archive fullnode client")
+		return ctx, false
+	}
+
+	return withClient(ctx, client), true
+}
+
+func isEthStateMissingError(err error) bool {
+	msg := err.Error()
+
+	for _, pattern := range ethArchiveConf.StateMissingErrors {
+		if strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
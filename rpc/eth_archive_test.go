@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/openweb3/go-rpc-provider"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEthArchive(t *testing.T) {
+	oldConf := ethArchiveConf
+	defer func() { ethArchiveConf = oldConf }()
+
+	ethArchiveConf = &ethArchiveConfig{
+		Enabled:            true,
+		Depth:              128,
+		StateMissingErrors: []string{"missing trie node"},
+	}
+
+	assert.True(t, isEthStateMissingError(errors.New("missing trie node 1e2f (path )")))
+	assert.False(t, isEthStateMissingError(errors.New("execution reverted")))
+
+	isArchive := func(params string) bool {
+		msg := &rpc.JsonRpcMessage{Method: "eth_getBalance", Params: json.RawMessage(params)}
+		return isEthArchiveBlock(context.Background(), msg, ethArchiveMethods[msg.Method])
+	}
+
+	addr := `"0x0000000000000000000000000000000000000001"`
+	assert.True(t, isArchive(`[`+addr+`,"earliest"]`))
+	assert.False(t, isArchive(`[`+addr+`,"latest"]`))
+	assert.False(t, isArchive(`[`+addr+`,"pending"]`))
+	assert.False(t, isArchive(`[`+addr+`]`))
+	assert.False(t, isArchive(`[`+addr+`,{"blockHash":"0x0000000000000000000000000000000000000000000000000000000000000001"}]`))
+	// no client to get the latest block
+	assert.False(t, isArchive(`[`+addr+`,"0x1"]`))
+}
+
+func TestEthArchiveClientWithSpan(t *testing.T) {
+	oldTracerProvider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(oldTracerProvider)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	archive := newTestEthNode(t, 16)
+	provider := node.NewEthClientProvider(node.NewLocalRouter(map[node.Group][]string{
+		node.GroupEthArchives: {archive.URL},
+	}))
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "eth_getBalance")
+	defer span.End()
+
+	ctx, ok := withEthArchiveClient(ctx, provider)
+	require.True(t, ok)
+
+	_, err := GetEthClientFromContext(ctx).Eth.BlockNumber()
+	require.NoError(t, err)
+
+	// fullnode calls of archive client traced under request span
+	spans := recorder.Ended()
+	if assert.Equal(t, 1, len(spans)) {
+		assert.Equal(t, "fullnode eth_blockNumber", spans[0].Name())
+		assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	}
+}
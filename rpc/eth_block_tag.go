@@ -0,0 +1,164 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3/go-rpc-provider"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/util/finality"
+)
+
+// ethBlockTagFields are fields of object parameters that may be block tags, e.g. block number
+// or hash, and log filter.
+var ethBlockTagFields = []string{"blockNumber", "fromBlock", "toBlock"}
+
+// ethBlockTagMiddleware resolves the `safe` and `finalized` block tags of evm space RPC params
+// into block numbers, by the tracked chain finality if any, otherwise by the requested fullnode.
+// So that the block tags are resolved consistently for all methods and log filters.
+func ethBlockTagMiddleware(next rpc.HandleCallMsgFunc) rpc.HandleCallMsgFunc {
+	return func(ctx context.Context, msg *rpc.JsonRpcMessage) *rpc.JsonRpcMessage {
+		if !hasEthBlockTag(msg.Params) || !isEthBlockTagNamespace(msg.Method) {
+			return next(ctx, msg)
+		}
+
+		if _, ok := ctx.Value(ctxKeyClientProvider).(*node.EthClientProvider); !ok {
+			return next(ctx, msg)
+		}
+
+		resolver := func(tag string) (uint64, error) {
+			return resolveEthBlockTag(ctx, tag)
+		}
+
+		params, err := resolveEthBlockTagParams(msg.Params, resolver)
+		if err != nil {
+			return msg.ErrorResponse(err)
+		}
+
+		resolved := *msg
+		resolved.Params = params
+
+		return next(ctx, &resolved)
+	}
+}
+
+func hasEthBlockTag(params json.RawMessage) bool {
+	return bytes.Contains(params, []byte(finality.TagSafe)) ||
+		bytes.Contains(params, []byte(finality.TagFinalized))
+}
+
+func isEthBlockTagNamespace(method string) bool {
+	return strings.HasPrefix(method, "eth_") ||
+		strings.HasPrefix(method, "parity_") ||
+		strings.HasPrefix(method, "trace_")
+}
+
+func isEthBlockTag(param json.RawMessage) (string, bool) {
+	var tag string
+	if err := json.Unmarshal(param, &tag); err != nil {
+		return "", false
+	}
+
+	return tag, tag == finality.TagSafe || tag == finality.TagFinalized
+}
+
+// resolveEthBlockTag resolves block tag by the tracked chain finality if any, otherwise by the
+// requested fullnode.
+func resolveEthBlockTag(ctx context.Context, tag string) (uint64, error) {
+	if blockNum, ok := finality.Resolve(tag); ok {
+		return blockNum, nil
+	}
+
+	w3c, ok := ctx.Value(ctxKeyClient).(*node.Web3goClient)
+	if !ok {
+		return 0, errors.Errorf("unknown block tag %v", tag)
+	}
+
+	blockNum, err := finality.QueryHead(ctx, w3c.Client, tag)
+	if err != nil {
+		return 0, errors.WithMessagef(err, "failed to resolve %v block", tag)
+	}
+
+	if blockNum == 0 {
+		return 0, errors.Errorf("%v block not found", tag)
+	}
+
+	return blockNum, nil
+}
+
+// resolveEthBlockTagParams replaces the `safe` and `finalized` block tags in RPC params,
+// including the block tags in object params, e.g. `{"blockNumber": "safe"}` or log filter.
+func resolveEthBlockTagParams(
+	data json.RawMessage, resolver func(tag string) (uint64, error),
+) (json.RawMessage, error) {
+	var params []json.RawMessage
+	if err := json.Unmarshal(data, &params); err != nil {
+		return data, nil
+	}
+
+	resolve := func(param json.RawMessage) (json.RawMessage, bool, error) {
+		tag, ok := isEthBlockTag(param)
+		if !ok {
+			return param, false, nil
+		}
+
+		blockNum, err := resolver(tag)
+		if err != nil {
+			return nil, false, err
+		}
+
+		resolved, err := json.Marshal(hexutil.Uint64(blockNum))
+		return resolved, true, err
+	}
+
+	changed := false
+
+	for i := range params {
+		resolved, ok, err := resolve(params[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			params[i], changed = resolved, true
+			continue
+		}
+
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(params[i], &obj); err != nil {
+			continue
+		}
+
+		objChanged := false
+		for _, field := range ethBlockTagFields {
+			if val, exists := obj[field]; exists {
+				resolved, ok, err := resolve(val)
+				if err != nil {
+					return nil, err
+				}
+
+				if ok {
+					obj[field], objChanged = resolved, true
+				}
+			}
+		}
+
+		if objChanged {
+			if params[i], err = json.Marshal(obj); err != nil {
+				return nil, err
+			}
+
+			changed = true
+		}
+	}
+
+	if !changed {
+		return data, nil
+	}
+
+	return json.Marshal(params)
+}
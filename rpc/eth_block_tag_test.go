@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/util/finality"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveEthBlockTagParams(t *testing.T) {
+	resolver := func(tag string) (uint64, error) {
+		if tag == finality.TagSafe {
+			return 16, nil
+		}
+
+		return 8, nil
+	}
+
+	testCases := []struct {
+		params   string
+		expected string
+	}{
+		{`["0xabcd","latest"]`, `["0xabcd","latest"]`},
+		{`["0xabcd","safe"]`, `["0xabcd","0x10"]`},
+		{`["finalized",false]`, `["0x8",false]`},
+		{`["0xabcd",{"blockNumber":"safe"}]`, `["0xabcd",{"blockNumber":"0x10"}]`},
+		{`[{"fromBlock":"finalized","toBlock":"safe"}]`, `[{"fromBlock":"0x8","toBlock":"0x10"}]`},
+		{`[{"data":"safe"}]`, `[{"data":"safe"}]`},
+	}
+
+	for _, tc := range testCases {
+		params, err := resolveEthBlockTagParams(json.RawMessage(tc.params), resolver)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, string(params))
+	}
+
+	_, err := resolveEthBlockTagParams(json.RawMessage(`["safe"]`), func(string) (uint64, error) {
+		return 0, errors.New("safe block not found")
+	})
+	assert.Error(t, err)
+}
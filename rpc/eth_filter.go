@@ -0,0 +1,219 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/ethereum/go-ethereum/rpc"
+	web3Types "github.com/openweb3/web3go/types"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/util/pubsub"
+	"github.com/sirupsen/logrus"
+)
+
+type ethFilterType int
+
+const (
+	ethFilterTypeLog ethFilterType = iota
+	ethFilterTypeBlock
+	ethFilterTypePendingTxn
+)
+
+func (t ethFilterType) String() string {
+	switch t {
+	case ethFilterTypeLog:
+		return "log"
+	case ethFilterTypeBlock:
+		return "block"
+	case ethFilterTypePendingTxn:
+		return "pendingTxn"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	errEthFilterNotFound = errors.New("filter not found")
+	errEthFilterTooMany  = errors.New("too many filters installed, please uninstall unused filters")
+
+	errEthFilterTooManyPerClient = errors.New("too many filters installed by client, please uninstall unused filters")
+)
+
+// ethFilterConfig filter configurations for evm space.
+type ethFilterConfig struct {
+	// inactive filters which are not polled within timeout will be uninstalled
+	Timeout time.Duration `default:"5m"`
+	// max number of filters allowed to be installed at the same time
+	MaxFilters int `default:"10000"`
+	// max number of filters allowed to be installed at the same time by the same client, which
+	// is identified by access token, or remote IP address if absent
+	MaxFiltersPerClient int `default:"100"`
+}
+
+// ethFilter is a gateway managed filter, which keeps a cursor of the next block number
+// to poll changes from, so that the filter changes are irrelevant to the fullnode that
+// the client request is routed to.
+type ethFilter struct {
+	mu sync.Mutex
+
+	typ ethFilterType
+	// client that installed the filter, empty if unidentified
+	owner string
+	// log filter criteria, valid only for log filter
+	crit web3Types.FilterQuery
+	// subscription of pending transactions for pending transaction filter, or synced event logs
+	// for log filter to poll the reverted ones, if any
+	sub pubsub.Subscription
+	// subscription of chain reorg for log filter to poll logs of the reverted blocks again, if any
+	reorgSub pubsub.Subscription
+	// next block number to poll changes from
+	cursor uint64
+	// last time that the filter is polled
+	lastPollAt time.Time
+}
+
+// ethFilterManager manages the lifecycle of installed evm space filters.
+type ethFilterManager struct {
+	mu      sync.Mutex
+	config  ethFilterConfig
+	filters map[rpc.ID]*ethFilter
+	owners  map[string]int // owner => number of filters installed
+}
+
+func newEthFilterManager(ctx context.Context) *ethFilterManager {
+	var config ethFilterConfig
+	viper.MustUnmarshalKey("ethrpc.filter", &config)
+
+	m := &ethFilterManager{
+		config:  config,
+		filters: make(map[rpc.ID]*ethFilter),
+		owners:  make(map[string]int),
+	}
+
+	go m.evictLoop(ctx)
+
+	return m
+}
+
+// install installs a new filter and returns the filter ID.
+func (m *ethFilterManager) install(filter *ethFilter) (rpc.ID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.filters) >= m.config.MaxFilters {
+		return "", errEthFilterTooMany
+	}
+
+	if len(filter.owner) > 0 {
+		if m.owners[filter.owner] >= m.config.MaxFiltersPerClient {
+			return "", errEthFilterTooManyPerClient
+		}
+
+		m.owners[filter.owner]++
+	}
+
+	id := rpc.NewID()
+	filter.lastPollAt = time.Now()
+	m.filters[id] = filter
+
+	return id, nil
+}
+
+// get returns the filter with specified ID and refreshes the last poll time. Note, filter
+// installed by another client is regarded as not found.
+func (m *ethFilterManager) get(id rpc.ID, owner string) (*ethFilter, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	filter, ok := m.filters[id]
+	if !ok || filter.owner != owner {
+		return nil, false
+	}
+
+	filter.lastPollAt = time.Now()
+
+	return filter, true
+}
+
+// uninstall removes the filter with specified ID, and returns false if not found or installed
+// by another client.
+func (m *ethFilterManager) uninstall(id rpc.ID, owner string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	filter, ok := m.filters[id]
+	if !ok || filter.owner != owner {
+		return false
+	}
+
+	m.remove(id, filter)
+
+	return true
+}
+
+// remove removes the filter, which should be called with lock held.
+func (m *ethFilterManager) remove(id rpc.ID, filter *ethFilter) {
+	delete(m.filters, id)
+
+	if filter.sub != nil {
+		filter.sub.Unsubscribe()
+	}
+
+	if filter.reorgSub != nil {
+		filter.reorgSub.Unsubscribe()
+	}
+
+	if len(filter.owner) == 0 {
+		return
+	}
+
+	if m.owners[filter.owner]--; m.owners[filter.owner] <= 0 {
+		delete(m.owners, filter.owner)
+	}
+}
+
+// evictLoop periodically uninstalls the inactive filters which are timed out until the
+// context is done.
+func (m *ethFilterManager) evictLoop(ctx context.Context) {
+	ticker := time.NewTicker(m.config.Timeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.evict()
+		}
+	}
+}
+
+func (m *ethFilterManager) evict() (numEvicted int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, filter := range m.filters {
+		if time.Since(filter.lastPollAt) > m.config.Timeout {
+			m.remove(id, filter)
+			numEvicted++
+		}
+	}
+
+	if numEvicted > 0 {
+		logrus.WithFields(logrus.Fields{
+			"numEvicted": numEvicted,
+			"numFilters": len(m.filters),
+		}).Debug("Inactive eth filters evicted")
+	}
+
+	return numEvicted
+}
+
+// ethFilterOwner identifies the client that installs filter the same as client session, or
+// empty if unidentified.
+func ethFilterOwner(ctx context.Context) string {
+	owner, _ := ethSessionKey(ctx)
+	return owner
+}
@@ -0,0 +1,215 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	web3Types "github.com/openweb3/web3go/types"
+	"github.com/scroll-tech/rpc-gateway/util/pubsub"
+	"github.com/scroll-tech/rpc-gateway/util/rpc/handlers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEthFilterManager(timeout time.Duration, maxFilters int) *ethFilterManager {
+	return &ethFilterManager{
+		config:  ethFilterConfig{Timeout: timeout, MaxFilters: maxFilters, MaxFiltersPerClient: maxFilters},
+		filters: make(map[rpc.ID]*ethFilter),
+		owners:  make(map[string]int),
+	}
+}
+
+func TestEthFilterManagerInstall(t *testing.T) {
+	m := newTestEthFilterManager(time.Minute, 2)
+
+	id1, err := m.install(&ethFilter{typ: ethFilterTypeBlock, cursor: 100})
+	assert.NoError(t, err)
+
+	id2, err := m.install(&ethFilter{typ: ethFilterTypeLog})
+	assert.NoError(t, err)
+	assert.NotEqual(t, id1, id2)
+
+	// exceeds the max number of filters
+	_, err = m.install(&ethFilter{typ: ethFilterTypePendingTxn})
+	assert.Equal(t, errEthFilterTooMany, err)
+
+	filter, ok := m.get(id1, "")
+	assert.True(t, ok)
+	assert.Equal(t, ethFilterTypeBlock, filter.typ)
+	assert.Equal(t, uint64(100), filter.cursor)
+
+	assert.True(t, m.uninstall(id1, ""))
+	assert.False(t, m.uninstall(id1, ""))
+
+	_, ok = m.get(id1, "")
+	assert.False(t, ok)
+}
+
+func TestEthFilterManagerEvict(t *testing.T) {
+	m := newTestEthFilterManager(time.Minute, 10)
+
+	id1, _ := m.install(&ethFilter{typ: ethFilterTypeBlock})
+	id2, _ := m.install(&ethFilter{typ: ethFilterTypeBlock})
+
+	// mark the first filter inactive
+	m.filters[id1].lastPollAt = time.Now().Add(-2 * time.Minute)
+
+	assert.Equal(t, 1, m.evict())
+
+	_, ok := m.get(id1, "")
+	assert.False(t, ok)
+
+	_, ok = m.get(id2, "")
+	assert.True(t, ok)
+}
+
+func TestEthFilterManagerInstallPerClient(t *testing.T) {
+	m := newTestEthFilterManager(time.Minute, 10)
+	m.config.MaxFiltersPerClient = 2
+
+	id1, err := m.install(&ethFilter{typ: ethFilterTypeBlock, owner: "ip:127.0.0.1"})
+	assert.NoError(t, err)
+
+	_, err = m.install(&ethFilter{typ: ethFilterTypeBlock, owner: "ip:127.0.0.1"})
+	assert.NoError(t, err)
+
+	// exceeds the max number of filters per client
+	_, err = m.install(&ethFilter{typ: ethFilterTypeBlock, owner: "ip:127.0.0.1"})
+	assert.Equal(t, errEthFilterTooManyPerClient, err)
+
+	// other clients are not affected
+	_, err = m.install(&ethFilter{typ: ethFilterTypeBlock, owner: "key:apikey"})
+	assert.NoError(t, err)
+
+	// quota released once uninstalled or evicted
+	assert.True(t, m.uninstall(id1, "ip:127.0.0.1"))
+
+	id3, err := m.install(&ethFilter{typ: ethFilterTypeBlock, owner: "ip:127.0.0.1"})
+	assert.NoError(t, err)
+
+	m.filters[id3].lastPollAt = time.Now().Add(-2 * time.Minute)
+	assert.Equal(t, 1, m.evict())
+
+	_, err = m.install(&ethFilter{typ: ethFilterTypeBlock, owner: "ip:127.0.0.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, m.owners["ip:127.0.0.1"])
+}
+
+func TestEthPendingTxFilter(t *testing.T) {
+	api := &ethAPI{
+		filterManager: newTestEthFilterManager(time.Minute, 10),
+		pendingTxs: newEthPendingTxFeed(context.Background(), &ethPubSubConfig{
+			PendingTxCacheSize: 10, PendingTxCacheTTL: time.Minute,
+		}, nil),
+	}
+
+	ctx := context.Background()
+
+	id, err := api.NewPendingTransactionFilter(ctx)
+	require.NoError(t, err)
+
+	changes, err := api.GetFilterChanges(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	hash1, hash2 := common.HexToHash("0x01"), common.HexToHash("0x02")
+	api.pendingTxs.Publish(&web3Types.TransactionDetail{Hash: hash1})
+	api.pendingTxs.Publish(&web3Types.TransactionDetail{Hash: hash2})
+
+	changes, err = api.GetFilterChanges(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{hash1, hash2}, changes)
+
+	// drained since last poll
+	changes, err = api.GetFilterChanges(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// unsubscribed once uninstalled
+	ok, err := api.UninstallFilter(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int32(0), api.pendingTxs.subscribers)
+}
+
+func TestEthFilterOwner(t *testing.T) {
+	api := &ethAPI{
+		filterManager: newTestEthFilterManager(time.Minute, 10),
+		pendingTxs: newEthPendingTxFeed(context.Background(), &ethPubSubConfig{
+			PendingTxCacheSize: 10, PendingTxCacheTTL: time.Minute,
+		}, nil),
+	}
+
+	owner := context.WithValue(context.Background(), handlers.CtxKeyRealIP, "127.0.0.1")
+	other := context.WithValue(context.Background(), handlers.CtxKeyRealIP, "127.0.0.2")
+
+	id, err := api.NewPendingTransactionFilter(owner)
+	require.NoError(t, err)
+
+	// filter installed by another client is not accessible
+	_, err = api.GetFilterChanges(other, id)
+	assert.Equal(t, errEthFilterNotFound, err)
+
+	_, err = api.GetFilterChanges(context.Background(), id)
+	assert.Equal(t, errEthFilterNotFound, err)
+
+	ok, err := api.UninstallFilter(other, id)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = api.GetFilterChanges(owner, id)
+	assert.NoError(t, err)
+
+	ok, err = api.UninstallFilter(owner, id)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestEthLogFilterRemovedChanges(t *testing.T) {
+	broker := pubsub.NewMemoryBroker()
+
+	addr1, addr2 := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	fromBlock := web3Types.BlockNumber(18)
+
+	filter := &ethFilter{
+		typ:      ethFilterTypeLog,
+		crit:     web3Types.FilterQuery{Addresses: []common.Address{addr1}},
+		cursor:   20,
+		sub:      broker.Subscribe(pubsub.TopicEthLogs),
+		reorgSub: broker.Subscribe(pubsub.TopicEthReorg),
+	}
+
+	// nothing reverted
+	assert.Empty(t, getRemovedLogFilterChanges(filter))
+	assert.Equal(t, uint64(20), filter.cursor)
+
+	logs, _ := json.Marshal([]web3Types.Log{
+		{Address: addr1, BlockNumber: 15, Removed: true},
+		{Address: addr2, BlockNumber: 16, Removed: true}, // unmatched
+		{Address: addr1, BlockNumber: 17},                // not reverted
+		{Address: addr1, BlockNumber: 20, Removed: true}, // not polled yet
+	})
+	require.NoError(t, broker.Publish(pubsub.TopicEthLogs, logs))
+
+	reorg, _ := json.Marshal(pubsub.ReorgMessage{RevertTo: 15})
+	require.NoError(t, broker.Publish(pubsub.TopicEthReorg, reorg))
+
+	// only the polled logs reverted, and the reverted blocks polled again
+	removed := getRemovedLogFilterChanges(filter)
+	if assert.Equal(t, 1, len(removed)) {
+		assert.Equal(t, uint64(15), removed[0].BlockNumber)
+		assert.True(t, removed[0].Removed)
+	}
+	assert.Equal(t, uint64(15), filter.cursor)
+
+	// never rewound beyond the from block of filter
+	filter.cursor, filter.crit.FromBlock = 20, &fromBlock
+	require.NoError(t, broker.Publish(pubsub.TopicEthReorg, reorg))
+
+	assert.Empty(t, getRemovedLogFilterChanges(filter))
+	assert.Equal(t, uint64(18), filter.cursor)
+}
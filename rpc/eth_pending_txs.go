@@ -0,0 +1,224 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	web3Types "github.com/openweb3/web3go/types"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/scroll-tech/rpc-gateway/util/pubsub"
+	"github.com/sirupsen/logrus"
+)
+
+// topic to dispatch evm space pending transactions within process
+const topicEthPendingTxs = "eth:pendingTxs"
+
+type ethPubSubConfig struct {
+	// interval to poll `txpool_content` of fullnode for `newPendingTransactions` subscription,
+	// which is polled only if subscribed, and disabled if 0
+	TxPoolPollInterval time.Duration `default:"1s"`
+	// max number of recent pending transactions to deduplicate
+	PendingTxCacheSize int `default:"100000"`
+	// expiration of the recent pending transactions to deduplicate
+	PendingTxCacheTTL time.Duration `default:"10m"`
+	// interval to poll the sync status of fullnode for `syncing` subscription
+	SyncingPollInterval time.Duration `default:"5s"`
+}
+
+func mustNewEthPubSubConfigFromViper() *ethPubSubConfig {
+	var conf ethPubSubConfig
+	viper.MustUnmarshalKey("ethrpc.pubsub", &conf)
+	return &conf
+}
+
+// ethPendingTxFeed dispatches evm space pending transactions to `newPendingTransactions`
+// subscriptions, which are fed by the transactions relayed by gateway and polled from the
+// `txpool_content` of fullnodes.
+type ethPendingTxFeed struct {
+	ctx      context.Context // stop polling txpool once done
+	conf     *ethPubSubConfig
+	provider *node.EthClientProvider
+	broker   *pubsub.MemoryBroker
+
+	seen *util.ExpirableLruCache // tx hash => struct{}
+
+	subscribers int32     // number of active subscriptions
+	primed      int32     // 1 if pending transactions of the current pool marked as seen
+	pollStarted sync.Once // start to poll txpool upon the first subscription
+}
+
+func newEthPendingTxFeed(
+	ctx context.Context, conf *ethPubSubConfig, provider *node.EthClientProvider,
+) *ethPendingTxFeed {
+	return &ethPendingTxFeed{
+		ctx:      ctx,
+		conf:     conf,
+		provider: provider,
+		broker:   pubsub.NewMemoryBroker(),
+		seen:     util.NewExpirableLruCache(conf.PendingTxCacheSize, conf.PendingTxCacheTTL),
+	}
+}
+
+// Subscribe subscribes pending transactions, which are JSON encoded `TransactionDetail`.
+func (f *ethPendingTxFeed) Subscribe() pubsub.Subscription {
+	atomic.AddInt32(&f.subscribers, 1)
+
+	if f.conf.TxPoolPollInterval > 0 && f.provider != nil {
+		f.pollStarted.Do(func() { go f.poll() })
+	}
+
+	return &ethPendingTxSubscription{Subscription: f.broker.Subscribe(topicEthPendingTxs), feed: f}
+}
+
+// PublishRaw publishes the raw transaction relayed by gateway.
+func (f *ethPendingTxFeed) PublishRaw(signedTx hexutil.Bytes) error {
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(signedTx); err != nil {
+		return errors.WithMessage(err, "failed to decode raw transaction")
+	}
+
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), &tx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to recover transaction sender")
+	}
+
+	f.Publish(newEthPendingTx(&tx, sender))
+
+	return nil
+}
+
+// Publish publishes pending transaction if not published recently.
+func (f *ethPendingTxFeed) Publish(tx *web3Types.TransactionDetail) {
+	if _, ok := f.seen.Get(tx.Hash); ok {
+		return
+	}
+
+	f.seen.Add(tx.Hash, struct{}{})
+
+	if atomic.LoadInt32(&f.subscribers) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(tx)
+	if err != nil {
+		logrus.WithField("txHash", tx.Hash).WithError(err).Error("Failed to marshal pending transaction")
+		return
+	}
+
+	f.broker.Publish(topicEthPendingTxs, data)
+}
+
+// poll polls the `txpool_content` of fullnode periodically while subscribed, until the context
+// is done.
+func (f *ethPendingTxFeed) poll() {
+	ticker := time.NewTicker(f.conf.TxPoolPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if atomic.LoadInt32(&f.subscribers) == 0 {
+			// the current pool will be marked as seen again once subscribed
+			atomic.StoreInt32(&f.primed, 0)
+			continue
+		}
+
+		if err := f.pollTxPool(); err != nil {
+			logrus.WithError(err).Debug("Failed to poll txpool content for pending transactions")
+		}
+	}
+}
+
+func (f *ethPendingTxFeed) pollTxPool() error {
+	w3c, err := f.provider.GetClientRandom()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(f.ctx, f.conf.TxPoolPollInterval)
+	defer cancel()
+
+	// status (pending or queued) => sender => nonce => transaction
+	var content map[string]map[string]map[string]*web3Types.TransactionDetail
+	if err := w3c.Provider().CallContext(ctx, &content, "txpool_content"); err != nil {
+		return err
+	}
+
+	// the transactions already in pool upon subscription are not new pending ones
+	primed := atomic.CompareAndSwapInt32(&f.primed, 0, 1)
+
+	for _, txs := range content["pending"] {
+		for _, tx := range txs {
+			if primed {
+				f.seen.Add(tx.Hash, struct{}{})
+			} else {
+				f.Publish(tx)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ethPendingTxSubscription decreases the number of subscribers of feed once unsubscribed.
+type ethPendingTxSubscription struct {
+	pubsub.Subscription
+	feed *ethPendingTxFeed
+	once sync.Once
+}
+
+func (s *ethPendingTxSubscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.Subscription.Unsubscribe()
+		atomic.AddInt32(&s.feed.subscribers, -1)
+	})
+}
+
+// newEthPendingTx converts the signed transaction into RPC transaction without block info.
+func newEthPendingTx(tx *types.Transaction, sender common.Address) *web3Types.TransactionDetail {
+	v, r, s := tx.RawSignatureValues()
+	txType := uint64(tx.Type())
+
+	result := web3Types.TransactionDetail{
+		From:     sender,
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Hash:     tx.Hash(),
+		Input:    tx.Data(),
+		Nonce:    tx.Nonce(),
+		To:       tx.To(),
+		Type:     &txType,
+		Value:    tx.Value(),
+		V:        v,
+		R:        r,
+		S:        s,
+	}
+
+	if tx.Type() != types.LegacyTxType {
+		result.Accesses = tx.AccessList()
+	}
+
+	if tx.Protected() {
+		result.ChainID = tx.ChainId()
+	}
+
+	if tx.Type() == types.DynamicFeeTxType {
+		result.MaxFeePerGas = tx.GasFeeCap()
+		result.MaxPriorityFeePerGas = tx.GasTipCap()
+	}
+
+	return &result
+}
@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	web3Types "github.com/openweb3/web3go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthPendingTxFeed(t *testing.T) {
+	feed := newEthPendingTxFeed(context.Background(), &ethPubSubConfig{
+		PendingTxCacheSize: 10, PendingTxCacheTTL: time.Minute,
+	}, nil)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(534352)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(534352), Nonce: 3, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2),
+		Gas: 21000, To: &to, Value: big.NewInt(1),
+	})
+	require.NoError(t, err)
+
+	rawTx, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	// not published without subscription, but remembered to deduplicate
+	require.NoError(t, feed.PublishRaw(rawTx))
+
+	sub := feed.Subscribe()
+	defer sub.Unsubscribe()
+
+	require.NoError(t, feed.PublishRaw(rawTx))
+	assert.Empty(t, sub.Chan())
+
+	// published once for the same transaction
+	tx2, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(534352)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(534352), Nonce: 4, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2),
+		Gas: 21000, To: &to, Value: big.NewInt(1),
+	})
+	require.NoError(t, err)
+
+	rawTx2, err := tx2.MarshalBinary()
+	require.NoError(t, err)
+
+	require.NoError(t, feed.PublishRaw(rawTx2))
+	require.NoError(t, feed.PublishRaw(rawTx2))
+	require.Len(t, sub.Chan(), 1)
+
+	var detail web3Types.TransactionDetail
+	require.NoError(t, json.Unmarshal(<-sub.Chan(), &detail))
+	assert.Equal(t, tx2.Hash(), detail.Hash)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), detail.From)
+	assert.Equal(t, uint64(4), detail.Nonce)
+	assert.Equal(t, big.NewInt(2), detail.MaxFeePerGas)
+	assert.Nil(t, detail.BlockNumber)
+
+	// no longer dispatched once unsubscribed
+	sub.Unsubscribe()
+	assert.Equal(t, int32(0), feed.subscribers)
+}
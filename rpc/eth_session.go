@@ -0,0 +1,292 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3/go-rpc-provider"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/rpc/cache"
+	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/scroll-tech/rpc-gateway/util/rpc/handlers"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// max number of times to route for another fullnode that catches up with the session head
+	maxSessionRerouteTimes = 3
+	// interval to poll the latest block of fullnode while waiting for it to catch up
+	sessionWaitPollInterval = 50 * time.Millisecond
+)
+
+// errEthSessionHeadLagging is returned if no fullnode caught up with the session head in time,
+// so that the client could retry later rather than seeing the chain head going backwards.
+var errEthSessionHeadLagging = errors.New("fullnode lags behind the chain head seen, please retry later")
+
+type ethSessionConfig struct {
+	// whether to keep the chain head monotonic for each client session
+	Enabled bool
+	// max number of client sessions to track
+	MaxSessions int `default:"100000"`
+	// expiration of inactive client session
+	TTL time.Duration `default:"1m"`
+	// max duration to wait for fullnode to catch up with the session head
+	WaitTimeout time.Duration `default:"300ms"`
+}
+
+// ethSessionMethods are evm space RPC methods that query the chain head by default, along with
+// the index of block number or hash parameter, or -1 if no such parameter.
+var ethSessionMethods = map[string]int{
+	"eth_blockNumber":         -1,
+	"eth_getBlockByNumber":    0,
+	"eth_call":                1,
+	"eth_estimateGas":         1,
+	"eth_getBalance":          1,
+	"eth_getCode":             1,
+	"eth_getTransactionCount": 1,
+	"eth_getStorageAt":        2,
+	"eth_getProof":            2,
+}
+
+// ethSessions tracks the highest chain head that each client (by access token or IP) has seen,
+// so that the chain head never goes backwards for the client even if routed to another fullnode
+// which lags behind a few blocks.
+type ethSessions struct {
+	conf *ethSessionConfig
+
+	mu    sync.Mutex
+	heads *util.ExpirableLruCache // session key => highest block number seen
+}
+
+// ethSessionStore is the client sessions of evm space, nil if disabled.
+var ethSessionStore *ethSessions
+
+func mustNewEthSessionsFromViper() (*ethSessions, bool) {
+	var config ethSessionConfig
+	viper.MustUnmarshalKey("ethrpc.session", &config)
+
+	if !config.Enabled {
+		return nil, false
+	}
+
+	return newEthSessions(&config), true
+}
+
+func newEthSessions(conf *ethSessionConfig) *ethSessions {
+	return &ethSessions{
+		conf:  conf,
+		heads: util.NewExpirableLruCache(conf.MaxSessions, conf.TTL),
+	}
+}
+
+// Head returns the highest block number seen by the specified session, or 0 if none.
+func (s *ethSessions) Head(key string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if val, ok := s.heads.Get(key); ok {
+		return val.(uint64)
+	}
+
+	return 0
+}
+
+// Observe advances the session head if the specified block number is higher, and returns the
+// session head afterwards.
+func (s *ethSessions) Observe(key string, blockNum uint64) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if val, ok := s.heads.Get(key); ok && val.(uint64) >= blockNum {
+		blockNum = val.(uint64)
+	}
+
+	// refresh expiration anyway
+	s.heads.Add(key, blockNum)
+
+	return blockNum
+}
+
+// ethSessionKey identifies client session by access token, or remote IP address if absent.
+// Returns false if neither available, e.g. internal calls.
+func ethSessionKey(ctx context.Context) (string, bool) {
+	if token, ok := handlers.GetAccessTokenFromContext(ctx); ok && len(token) > 0 {
+		return "key:" + token, true
+	}
+
+	if ip, ok := handlers.GetIPAddressFromContext(ctx); ok && len(ip) > 0 {
+		return "ip:" + ip, true
+	}
+
+	return "", false
+}
+
+// ethSessionMiddleware keeps the chain head monotonic for each client session. Requests at the
+// latest block are routed to a fullnode at or above the session head, or wait briefly for the
+// fullnode to catch up and are rejected if timed out, and `eth_blockNumber` never answers a
+// lower block than the session head.
+func ethSessionMiddleware(next rpc.HandleCallMsgFunc) rpc.HandleCallMsgFunc {
+	return func(ctx context.Context, msg *rpc.JsonRpcMessage) *rpc.JsonRpcMessage {
+		index, ok := ethSessionMethods[msg.Method]
+		if !ok || !isEthLatestBlockRequested(msg, index) {
+			return next(ctx, msg)
+		}
+
+		provider, ok := ctx.Value(ctxKeyClientProvider).(*node.EthClientProvider)
+		if !ok {
+			return next(ctx, msg)
+		}
+
+		// otherwise, all unidentified clients share the same session
+		key, ok := ethSessionKey(ctx)
+		if !ok {
+			return next(ctx, msg)
+		}
+
+		head := ethSessionStore.Head(key)
+
+		ctx, nodeHead, ok := withEthSessionClient(ctx, provider, msg.Method, head)
+		if !ok {
+			return next(ctx, msg)
+		}
+
+		// `eth_blockNumber` is answered with the session head if fullnode still lags behind
+		if nodeHead < head && msg.Method != "eth_blockNumber" {
+			return msg.ErrorResponse(errEthSessionHeadLagging)
+		}
+
+		resp := next(ctx, msg)
+		if resp == nil || resp.Error != nil {
+			return resp
+		}
+
+		if msg.Method != "eth_blockNumber" {
+			ethSessionStore.Observe(key, nodeHead)
+			return resp
+		}
+
+		var result hexutil.Big
+		if err := json.Unmarshal(resp.Result, &result); err != nil || !result.ToInt().IsUint64() {
+			return resp
+		}
+
+		// answer with the session head if fullnode still lags behind
+		if head = ethSessionStore.Observe(key, result.ToInt().Uint64()); head > result.ToInt().Uint64() {
+			data, _ := json.Marshal((*hexutil.Big)(new(big.Int).SetUint64(head)))
+			return &rpc.JsonRpcMessage{Version: msg.Version, ID: msg.ID, Result: data}
+		}
+
+		return resp
+	}
+}
+
+// isEthLatestBlockRequested checks if the chain head is requested, e.g. the `latest` or `pending`
+// block tag, or block parameter omitted.
+func isEthLatestBlockRequested(msg *rpc.JsonRpcMessage, index int) bool {
+	if index < 0 {
+		return true
+	}
+
+	var params []json.RawMessage
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return false
+	}
+
+	if index >= len(params) {
+		return true
+	}
+
+	var bnh rpc.BlockNumberOrHash
+	if err := json.Unmarshal(params[index], &bnh); err != nil {
+		return false
+	}
+
+	bn, ok := bnh.Number()
+	return ok && (bn == rpc.LatestBlockNumber || bn == rpc.PendingBlockNumber)
+}
+
+// withEthSessionClient makes sure the client in context is at or above the session head, by
+// routing for another fullnode or waiting briefly for the routed fullnode to catch up. Returns
+// the context with client and its latest block number.
+func withEthSessionClient(
+	ctx context.Context, provider *node.EthClientProvider, method string, head uint64,
+) (context.Context, uint64, bool) {
+	w3c, ok := ctx.Value(ctxKeyClient).(*node.Web3goClient)
+	if !ok {
+		return ctx, 0, false
+	}
+
+	nodeHead, err := ethLatestBlockOf(w3c)
+	if err != nil || nodeHead >= head {
+		return ctx, nodeHead, err == nil
+	}
+
+	group := node.EthRoutingTable().Route(method)
+
+	for i := 0; i < maxSessionRerouteTimes; i++ {
+		client, err := provider.GetClientRandomByGroup(group)
+		if err != nil {
+			break
+		}
+
+		if latest, err := ethLatestBlockOf(client); err == nil && latest >= head {
+			return withClient(ctx, client), latest, true
+		}
+	}
+
+	// wait for the routed fullnode to catch up
+	if nodeHead = waitEthSessionHead(ctx, w3c, nodeHead, head); nodeHead >= head {
+		return ctx, nodeHead, true
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"method":      method,
+		"node":        clientNodeName(w3c),
+		"nodeHead":    nodeHead,
+		"sessionHead": head,
+	}).Debug("No fullnode caught up with the session head")
+
+	return ctx, nodeHead, true
+}
+
+// waitEthSessionHead polls the latest block of fullnode until it catches up with the session
+// head, or timed out or request canceled. Returns the latest block number of fullnode.
+func waitEthSessionHead(ctx context.Context, w3c *node.Web3goClient, nodeHead, head uint64) uint64 {
+	ctx, cancel := context.WithTimeout(ctx, ethSessionStore.conf.WaitTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(sessionWaitPollInterval)
+	defer ticker.Stop()
+
+	for nodeHead < head {
+		select {
+		case <-ctx.Done():
+			return nodeHead
+		case <-ticker.C:
+		}
+
+		latest, err := w3c.Eth.BlockNumber()
+		if err != nil {
+			return nodeHead
+		}
+
+		nodeHead = latest.Uint64()
+	}
+
+	return nodeHead
+}
+
+func ethLatestBlockOf(w3c *node.Web3goClient) (uint64, error) {
+	latest, err := cache.EthDefault.GetBlockNumber(w3c)
+	if err != nil {
+		return 0, err
+	}
+
+	return latest.ToInt().Uint64(), nil
+}
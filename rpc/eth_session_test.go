@@ -0,0 +1,170 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3/go-rpc-provider"
+	"github.com/scroll-tech/rpc-gateway/node"
+	rpcutil "github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/scroll-tech/rpc-gateway/util/rpc/handlers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestEthNode creates evm space fullnode that always responds the specified latest block.
+func newTestEthNode(t *testing.T, latest uint64) *node.Web3goClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ ID json.RawMessage }
+		json.NewDecoder(r.Body).Decode(&req)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x%x"}`, req.ID, latest)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := rpcutil.NewEthClient(server.URL)
+	require.NoError(t, err)
+
+	return &node.Web3goClient{Client: client, URL: server.URL}
+}
+
+func TestEthSessionMonotonicHead(t *testing.T) {
+	oldStore := ethSessionStore
+	defer func() { ethSessionStore = oldStore }()
+
+	ethSessionStore = newEthSessions(&ethSessionConfig{
+		MaxSessions: 10, TTL: time.Minute, WaitTimeout: 100 * time.Millisecond,
+	})
+
+	ahead, behind := newTestEthNode(t, 16), newTestEthNode(t, 12)
+
+	// only the fullnode ahead available to reroute
+	provider := node.NewEthClientProvider(node.NewLocalRouter(map[node.Group][]string{
+		node.GroupEthHttp: {ahead.URL},
+	}))
+
+	// no fullnode available to reroute
+	lagging := node.NewEthClientProvider(node.NewLocalRouter(map[node.Group][]string{
+		node.GroupEthHttp: {behind.URL},
+	}))
+
+	// echo the latest block of the requested fullnode
+	var requested *node.Web3goClient
+	handler := ethSessionMiddleware(func(ctx context.Context, msg *rpc.JsonRpcMessage) *rpc.JsonRpcMessage {
+		requested = GetEthClientFromContext(ctx)
+		latest, err := requested.Eth.BlockNumber()
+		require.NoError(t, err)
+
+		data, _ := json.Marshal((*hexutil.Big)(latest))
+		return &rpc.JsonRpcMessage{Version: msg.Version, ID: msg.ID, Result: data}
+	})
+
+	callMsg := func(
+		provider *node.EthClientProvider, client *node.Web3goClient, method, params string,
+	) *rpc.JsonRpcMessage {
+		ctx := context.WithValue(context.Background(), handlers.CtxKeyRealIP, "127.0.0.1")
+		ctx = context.WithValue(ctx, ctxKeyClientProvider, provider)
+		ctx = context.WithValue(ctx, ctxKeyClient, client)
+
+		msg := &rpc.JsonRpcMessage{Method: method, Params: json.RawMessage(params)}
+		return handler(ctx, msg)
+	}
+
+	call := func(
+		provider *node.EthClientProvider, client *node.Web3goClient, method, params string,
+	) string {
+		return string(callMsg(provider, client, method, params).Result)
+	}
+
+	// no session yet
+	assert.Equal(t, `"0xc"`, call(lagging, behind, "eth_blockNumber", `[]`))
+	assert.Equal(t, `"0x10"`, call(provider, ahead, "eth_blockNumber", `[]`))
+
+	// answer with session head if no fullnode caught up
+	assert.Equal(t, `"0x10"`, call(lagging, behind, "eth_blockNumber", `[]`))
+	assert.Equal(t, behind.URL, requested.URL)
+
+	// error if no fullnode caught up for the latest state
+	requested = nil
+	resp := callMsg(lagging, behind, "eth_getBalance", `["0x0000000000000000000000000000000000000001","latest"]`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, errEthSessionHeadLagging.Error(), resp.Error.Error())
+	assert.Nil(t, requested)
+
+	// rerouted to the fullnode ahead
+	call(provider, behind, "eth_getBalance", `["0x0000000000000000000000000000000000000001","latest"]`)
+	assert.Equal(t, ahead.URL, requested.URL)
+
+	// not rerouted for specific block
+	call(provider, behind, "eth_getBalance", `["0x0000000000000000000000000000000000000001","0x1"]`)
+	assert.Equal(t, behind.URL, requested.URL)
+
+	// another session not affected
+	assert.Equal(t, uint64(0), ethSessionStore.Head("ip:127.0.0.2"))
+	assert.Equal(t, uint64(16), ethSessionStore.Head("ip:127.0.0.1"))
+
+	// not tracked for unidentified client
+	ctx := context.WithValue(context.Background(), ctxKeyClientProvider, lagging)
+	ctx = context.WithValue(ctx, ctxKeyClient, behind)
+	resp = handler(ctx, &rpc.JsonRpcMessage{Method: "eth_blockNumber", Params: json.RawMessage(`[]`)})
+	assert.Equal(t, `"0xc"`, string(resp.Result))
+}
+
+func TestEthSessionWaitCanceled(t *testing.T) {
+	oldStore := ethSessionStore
+	defer func() { ethSessionStore = oldStore }()
+
+	ethSessionStore = newEthSessions(&ethSessionConfig{
+		MaxSessions: 10, TTL: time.Minute, WaitTimeout: time.Minute,
+	})
+
+	behind := newTestEthNode(t, 12)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	assert.Equal(t, uint64(12), waitEthSessionHead(ctx, behind, 12, 16))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestEthSessionClientWithSpan(t *testing.T) {
+	oldTracerProvider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(oldTracerProvider)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ahead, behind := newTestEthNode(t, 16), newTestEthNode(t, 12)
+	provider := node.NewEthClientProvider(node.NewLocalRouter(map[node.Group][]string{
+		node.GroupEthHttp: {ahead.URL},
+	}))
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "eth_getBalance")
+	defer span.End()
+
+	ctx = context.WithValue(ctx, ctxKeyClient, behind)
+	ctx, latest, ok := withEthSessionClient(ctx, provider, "eth_getBalance", 16)
+	require.True(t, ok)
+	assert.Equal(t, uint64(16), latest)
+
+	_, err := GetEthClientFromContext(ctx).Eth.BlockNumber()
+	require.NoError(t, err)
+
+	// fullnode calls of rerouted client traced under request span
+	var traced []string
+	for _, s := range recorder.Ended() {
+		if s.Parent().SpanID() == span.SpanContext().SpanID() {
+			traced = append(traced, s.Name())
+		}
+	}
+	assert.Equal(t, []string{"fullnode eth_blockNumber"}, traced)
+}
@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3/go-rpc-provider"
+	web3Types "github.com/openweb3/web3go/types"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	rpcutil "github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+// ethSyncingAPI provides the `syncing` subscription of evm space, which is registered under the
+// `eth` namespace along with ethAPI, since the `eth_syncing` method occupies the same name.
+type ethSyncingAPI struct {
+	conf       *ethPubSubConfig
+	provider   *node.EthClientProvider
+	syncStatus node.SyncStatusProvider // nil if node manager RPC not configured
+}
+
+func mustNewEthSyncingAPI(provider *node.EthClientProvider, syncStatus node.SyncStatusProvider) *ethSyncingAPI {
+	return &ethSyncingAPI{
+		conf:       mustNewEthPubSubConfigFromViper(),
+		provider:   provider,
+		syncStatus: syncStatus,
+	}
+}
+
+// ethSyncingResult is the notification of `syncing` subscription while syncing, otherwise `false`
+// is notified once synced.
+type ethSyncingResult struct {
+	Syncing bool                   `json:"syncing"`
+	Status  web3Types.SyncProgress `json:"status"`
+}
+
+// Syncing creates a subscription that is triggered when the routed fullnode starts or stops
+// syncing, along with the sync progress while syncing.
+func (api *ethSyncingAPI) Syncing(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		logrus.Error("Syncing pubsub notification unsupported")
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	w3c, err := api.provider.GetClientByIP(ctx)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get eth client by ip for syncing pubsub")
+		return &rpc.Subscription{}, errSubscriptionProxyError
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	logger := logrus.WithField("rpcSubID", rpcSub.ID)
+
+	counter := metrics.Registry.PubSub.Sessions("eth", "syncing", rpcutil.Url2NodeName(w3c.URL))
+	counter.Inc(1)
+
+	go func() {
+		defer counter.Dec(1)
+
+		ticker := time.NewTicker(api.conf.SyncingPollInterval)
+		defer ticker.Stop()
+
+		var tracker ethSyncingTracker
+
+		for {
+			if status, err := api.querySyncStatus(w3c); err != nil {
+				logger.WithError(err).Debug("Failed to query sync status for syncing pubsub")
+			} else if result, changed := tracker.update(status); changed {
+				notifier.Notify(rpcSub.ID, result)
+			}
+
+			select {
+			case <-ticker.C:
+			case err := <-rpcSub.Err():
+				logger.WithError(err).Debug("Syncing pubsub subscription error")
+				return
+			case <-notifier.Closed():
+				logger.Debug("Syncing pubsub connection closed")
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// querySyncStatus queries the sync status tracked by node manager if available, otherwise from
+// the fullnode directly.
+func (api *ethSyncingAPI) querySyncStatus(w3c *node.Web3goClient) (web3Types.SyncStatus, error) {
+	if api.syncStatus != nil {
+		status, err := api.syncStatus.SyncStatus(node.GroupEthHttp, w3c.URL)
+		if err == nil {
+			return web3Types.SyncStatus{
+				IsSyncing: status.Syncing,
+				SyncInfo: &web3Types.SyncProgress{
+					CurrentBlock: hexutil.Uint64(status.CurrentBlock),
+					HighestBlock: hexutil.Uint64(status.HighestBlock),
+				},
+			}, nil
+		}
+
+		logrus.WithField("node", w3c.URL).WithError(err).Debug("Failed to get sync status from node manager")
+	}
+
+	return w3c.Eth.Syncing()
+}
+
+// ethSyncingTracker tracks the sync status of fullnode for `syncing` subscription.
+type ethSyncingTracker struct {
+	notified bool
+	syncing  bool
+	progress web3Types.SyncProgress
+}
+
+// update updates the sync status, and returns the notification if sync started, stopped or the
+// progress changed while syncing.
+func (t *ethSyncingTracker) update(status web3Types.SyncStatus) (interface{}, bool) {
+	if !status.IsSyncing {
+		changed := !t.notified || t.syncing
+		t.notified, t.syncing = true, false
+
+		return false, changed
+	}
+
+	var progress web3Types.SyncProgress
+	if status.SyncInfo != nil {
+		progress = *status.SyncInfo
+	}
+
+	// starting block is not tracked by node manager
+	if progress.StartingBlock == 0 {
+		if t.syncing {
+			progress.StartingBlock = t.progress.StartingBlock
+		} else {
+			progress.StartingBlock = progress.CurrentBlock
+		}
+	}
+
+	changed := !t.notified || !t.syncing || progress != t.progress
+	t.notified, t.syncing, t.progress = true, true, progress
+
+	return &ethSyncingResult{Syncing: true, Status: progress}, changed
+}
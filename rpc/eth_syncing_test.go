@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	web3Types "github.com/openweb3/web3go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthSyncingTracker(t *testing.T) {
+	var tracker ethSyncingTracker
+
+	syncing := func(current, highest uint64) web3Types.SyncStatus {
+		return web3Types.SyncStatus{IsSyncing: true, SyncInfo: &web3Types.SyncProgress{
+			CurrentBlock: hexutil.Uint64(current), HighestBlock: hexutil.Uint64(highest),
+		}}
+	}
+
+	// always notify the initial status
+	result, changed := tracker.update(web3Types.SyncStatus{})
+	assert.True(t, changed)
+	assert.Equal(t, false, result)
+
+	_, changed = tracker.update(web3Types.SyncStatus{})
+	assert.False(t, changed)
+
+	// sync started from the current block
+	result, changed = tracker.update(syncing(10, 100))
+	assert.True(t, changed)
+	assert.Equal(t, &ethSyncingResult{Syncing: true, Status: web3Types.SyncProgress{
+		StartingBlock: 10, CurrentBlock: 10, HighestBlock: 100,
+	}}, result)
+
+	_, changed = tracker.update(syncing(10, 100))
+	assert.False(t, changed)
+
+	// sync progress changed
+	result, changed = tracker.update(syncing(50, 100))
+	assert.True(t, changed)
+	assert.Equal(t, hexutil.Uint64(10), result.(*ethSyncingResult).Status.StartingBlock)
+
+	// sync stopped
+	result, changed = tracker.update(web3Types.SyncStatus{})
+	assert.True(t, changed)
+	assert.Equal(t, false, result)
+}
@@ -0,0 +1,231 @@
+package rpc
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	web3Types "github.com/openweb3/web3go/types"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// reasons of raw transactions rejected by gateway precheck
+const (
+	txRejectOversized         = "oversized"
+	txRejectDecode            = "decode"
+	txRejectChainId           = "chainId"
+	txRejectUnprotected       = "unprotected"
+	txRejectSignature         = "signature"
+	txRejectFeeCap            = "feeCap"
+	txRejectIntrinsicGas      = "intrinsicGas"
+	txRejectGasLimit          = "gasLimit"
+	txRejectNonceTooLow       = "nonceTooLow"
+	txRejectNonceTooHigh      = "nonceTooHigh"
+	txRejectInsufficientFunds = "insufficientFunds"
+)
+
+var (
+	errTxUnprotected  = errors.New("only replay-protected (EIP-155) transactions allowed")
+	errTxNonceTooHigh = errors.New("nonce too high")
+)
+
+// ethTxRejectedError is the JSON-RPC error of raw transaction rejected by gateway precheck, which
+// conforms to the error code of fullnode.
+type ethTxRejectedError struct {
+	reason string
+	err    error
+}
+
+func (e *ethTxRejectedError) Error() string { return e.err.Error() }
+
+func (e *ethTxRejectedError) ErrorCode() int { return -32000 }
+
+func rejectTx(reason string, err error) error {
+	metrics.Registry.RPC.TxPrecheckRejected("eth", reason).Mark(1)
+	return &ethTxRejectedError{reason: reason, err: err}
+}
+
+type ethTxPrecheckConfig struct {
+	// whether to validate raw transactions before forwarding to fullnode
+	Enabled bool
+	// max size in bytes of raw transaction
+	MaxTxSize int `default:"131072"`
+	// max gas limit of transaction, e.g. block gas limit, skip if 0
+	MaxGas uint64
+	// whether to allow legacy transactions without replay protection (EIP-155)
+	AllowUnprotected bool
+	// max nonce ahead of the account nonce
+	MaxNonceGap uint64 `default:"64"`
+	// max number of accounts to cache nonce and balance
+	StateCacheSize int `default:"10000"`
+	// expiration of the cached account nonce and balance
+	StateCacheTTL time.Duration `default:"1s"`
+}
+
+// ethAccountState is the account nonce and balance at the latest block.
+type ethAccountState struct {
+	nonce   uint64
+	balance *big.Int
+}
+
+// ethTxPrechecker validates evm space raw transactions before forwarding to fullnode, so that
+// invalid transactions are rejected by gateway without consuming fullnode resources.
+type ethTxPrechecker struct {
+	conf    *ethTxPrecheckConfig
+	chainId *big.Int
+	signer  types.Signer
+	states  *util.ExpirableLruCache // address => *ethAccountState
+}
+
+func mustNewEthTxPrecheckerFromViper(chainId uint64) (*ethTxPrechecker, bool) {
+	var conf ethTxPrecheckConfig
+	viper.MustUnmarshalKey("ethrpc.txPrecheck", &conf)
+
+	if !conf.Enabled {
+		return nil, false
+	}
+
+	return newEthTxPrechecker(&conf, chainId), true
+}
+
+func newEthTxPrechecker(conf *ethTxPrecheckConfig, chainId uint64) *ethTxPrechecker {
+	cid := new(big.Int).SetUint64(chainId)
+
+	return &ethTxPrechecker{
+		conf:    conf,
+		chainId: cid,
+		signer:  types.LatestSignerForChainID(cid),
+		states:  util.NewExpirableLruCache(conf.StateCacheSize, conf.StateCacheTTL),
+	}
+}
+
+// Check decodes and validates the raw transaction, and returns the JSON-RPC error if rejected.
+func (p *ethTxPrechecker) Check(w3c *node.Web3goClient, rawTx []byte) error {
+	tx, sender, err := p.checkStateless(rawTx)
+	if err != nil {
+		return err
+	}
+
+	state, cached, err := p.getAccountState(w3c, sender, false)
+	if err != nil {
+		// leave it to fullnode to validate
+		logrus.WithError(err).WithField("sender", sender).Debug("Failed to get account state for tx precheck")
+		return nil
+	}
+
+	err = p.checkState(tx, state)
+	if err != nil && cached {
+		// cached state might be outdated, e.g. balance deposited just now
+		if state, _, err = p.getAccountState(w3c, sender, true); err != nil {
+			return nil
+		}
+
+		err = p.checkState(tx, state)
+	}
+
+	if err != nil {
+		e := err.(*ethTxRejectedError)
+		return rejectTx(e.reason, e.err)
+	}
+
+	return nil
+}
+
+// checkStateless validates the raw transaction without account state, and returns the decoded
+// transaction along with the recovered sender.
+func (p *ethTxPrechecker) checkStateless(rawTx []byte) (*types.Transaction, common.Address, error) {
+	if len(rawTx) > p.conf.MaxTxSize {
+		return nil, common.Address{}, rejectTx(txRejectOversized, core.ErrOversizedData)
+	}
+
+	// decode both legacy RLP and typed envelope
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return nil, common.Address{}, rejectTx(txRejectDecode, err)
+	}
+
+	if !tx.Protected() {
+		if !p.conf.AllowUnprotected {
+			return nil, common.Address{}, rejectTx(txRejectUnprotected, errTxUnprotected)
+		}
+	} else if tx.ChainId().Cmp(p.chainId) != 0 {
+		return nil, common.Address{}, rejectTx(txRejectChainId, types.ErrInvalidChainId)
+	}
+
+	sender, err := types.Sender(p.signer, &tx)
+	if err != nil {
+		return nil, common.Address{}, rejectTx(txRejectSignature, err)
+	}
+
+	if tx.GasTipCapIntCmp(tx.GasFeeCap()) > 0 {
+		return nil, common.Address{}, rejectTx(txRejectFeeCap, core.ErrTipAboveFeeCap)
+	}
+
+	intrGas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, true)
+	if err != nil {
+		return nil, common.Address{}, rejectTx(txRejectIntrinsicGas, err)
+	}
+
+	if tx.Gas() < intrGas {
+		return nil, common.Address{}, rejectTx(txRejectIntrinsicGas, core.ErrIntrinsicGas)
+	}
+
+	if p.conf.MaxGas > 0 && tx.Gas() > p.conf.MaxGas {
+		return nil, common.Address{}, rejectTx(txRejectGasLimit, core.ErrGasLimit)
+	}
+
+	return &tx, sender, nil
+}
+
+// checkState validates the nonce and balance of sender, and returns the rejected error without
+// metrics, since the state might be outdated.
+func (p *ethTxPrechecker) checkState(tx *types.Transaction, state *ethAccountState) error {
+	if tx.Nonce() < state.nonce {
+		return &ethTxRejectedError{txRejectNonceTooLow, core.ErrNonceTooLow}
+	}
+
+	if tx.Nonce() > state.nonce+p.conf.MaxNonceGap {
+		return &ethTxRejectedError{txRejectNonceTooHigh, errTxNonceTooHigh}
+	}
+
+	if state.balance.Cmp(tx.Cost()) < 0 {
+		return &ethTxRejectedError{txRejectInsufficientFunds, core.ErrInsufficientFunds}
+	}
+
+	return nil
+}
+
+// getAccountState returns the account state from cache if not refresh, otherwise from fullnode.
+func (p *ethTxPrechecker) getAccountState(
+	w3c *node.Web3goClient, addr common.Address, refresh bool,
+) (state *ethAccountState, cached bool, err error) {
+	if !refresh {
+		if val, ok := p.states.Get(addr); ok {
+			return val.(*ethAccountState), true, nil
+		}
+	}
+
+	latest := web3Types.BlockNumberOrHashWithNumber(web3Types.LatestBlockNumber)
+
+	nonce, err := w3c.Eth.TransactionCount(addr, &latest)
+	if err != nil {
+		return nil, false, errors.WithMessage(err, "failed to get nonce")
+	}
+
+	balance, err := w3c.Eth.Balance(addr, &latest)
+	if err != nil {
+		return nil, false, errors.WithMessage(err, "failed to get balance")
+	}
+
+	state = &ethAccountState{nonce: nonce.Uint64(), balance: balance}
+	p.states.Add(addr, state)
+
+	return state, false, nil
+}
@@ -0,0 +1,99 @@
+package rpc
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthTxPrecheck(t *testing.T) {
+	prechecker := newEthTxPrechecker(&ethTxPrecheckConfig{
+		MaxTxSize:      131072,
+		MaxGas:         10_000_000,
+		MaxNonceGap:    64,
+		StateCacheSize: 10,
+		StateCacheTTL:  time.Second,
+	}, 534352)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	signTx := func(chainId int64, nonce, gas uint64, protected bool) []byte {
+		var signer types.Signer = types.HomesteadSigner{}
+		var txData types.TxData = &types.LegacyTx{
+			Nonce: nonce, GasPrice: big.NewInt(10), Gas: gas, To: &to, Value: big.NewInt(100),
+		}
+
+		if protected {
+			signer = types.LatestSignerForChainID(big.NewInt(chainId))
+			txData = &types.DynamicFeeTx{
+				ChainID:   big.NewInt(chainId),
+				Nonce:     nonce,
+				GasTipCap: big.NewInt(1),
+				GasFeeCap: big.NewInt(10),
+				Gas:       gas,
+				To:        &to,
+				Value:     big.NewInt(100),
+			}
+		}
+
+		tx, err := types.SignNewTx(key, signer, txData)
+		require.NoError(t, err)
+
+		data, err := tx.MarshalBinary()
+		require.NoError(t, err)
+
+		return data
+	}
+
+	rejected := func(err error) string {
+		if err == nil {
+			return ""
+		}
+
+		return err.(*ethTxRejectedError).reason
+	}
+
+	testCases := []struct {
+		rawTx  []byte
+		reason string
+	}{
+		{signTx(534352, 0, 21000, true), ""},
+		{[]byte{0x02, 0xc0}, txRejectDecode},
+		{signTx(1, 0, 21000, true), txRejectChainId},
+		{signTx(534352, 0, 21000, false), txRejectUnprotected},
+		{signTx(534352, 0, 20000, true), txRejectIntrinsicGas},
+		{signTx(534352, 0, 20_000_000, true), txRejectGasLimit},
+	}
+
+	for _, tc := range testCases {
+		_, sender, err := prechecker.checkStateless(tc.rawTx)
+		assert.Equal(t, tc.reason, rejected(err))
+
+		if err == nil {
+			assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), sender)
+		}
+	}
+
+	tx, _, err := prechecker.checkStateless(signTx(534352, 5, 21000, true))
+	require.NoError(t, err)
+
+	// gas * fee cap + value = 21000 * 10 + 100
+	funds := big.NewInt(210100)
+
+	assert.Equal(t, "", rejected(prechecker.checkState(tx, &ethAccountState{5, funds})))
+	assert.Equal(t, txRejectNonceTooLow, rejected(prechecker.checkState(tx, &ethAccountState{6, funds})))
+	assert.Equal(t, txRejectInsufficientFunds, rejected(prechecker.checkState(tx, &ethAccountState{5, big.NewInt(210099)})))
+
+	tx, _, err = prechecker.checkStateless(signTx(534352, 70, 21000, true))
+	require.NoError(t, err)
+	assert.Equal(t, txRejectNonceTooHigh, rejected(prechecker.checkState(tx, &ethAccountState{5, funds})))
+}
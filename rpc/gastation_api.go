@@ -19,3 +19,16 @@ func newGasStationAPI(handler *handler.GasStationHandler) *gasStationAPI {
 func (api *gasStationAPI) Price(ctx context.Context) (*types.GasStationPrice, error) {
 	return api.handler.GetPrice()
 }
+
+// ethGasStationAPI provides EVM space gasstation API.
+type ethGasStationAPI struct {
+	handler *handler.EthGasStationHandler
+}
+
+func newEthGasStationAPI(handler *handler.EthGasStationHandler) *ethGasStationAPI {
+	return &ethGasStationAPI{handler: handler}
+}
+
+func (api *ethGasStationAPI) Price(ctx context.Context) (*types.GasStationPrice, error) {
+	return api.handler.GetPrice()
+}
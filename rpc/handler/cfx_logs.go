@@ -11,7 +11,6 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/store"
-	"github.com/scroll-tech/rpc-gateway/store/mysql"
 	"github.com/scroll-tech/rpc-gateway/util/metrics"
 )
 
@@ -21,12 +20,12 @@ var (
 
 // CfxLogsApiHandler RPC handler to get core space event logs from store or fullnode.
 type CfxLogsApiHandler struct {
-	ms *mysql.MysqlStore
+	ms store.DBStore
 
 	prunedHandler *CfxPrunedLogsHandler // optional
 }
 
-func NewCfxLogsApiHandler(ms *mysql.MysqlStore, prunedHandler *CfxPrunedLogsHandler) *CfxLogsApiHandler {
+func NewCfxLogsApiHandler(ms store.DBStore, prunedHandler *CfxPrunedLogsHandler) *CfxLogsApiHandler {
 	return &CfxLogsApiHandler{ms, prunedHandler}
 }
 
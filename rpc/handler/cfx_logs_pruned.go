@@ -12,7 +12,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/node"
 	"github.com/scroll-tech/rpc-gateway/rpc/throttle"
-	"github.com/scroll-tech/rpc-gateway/store/mysql"
+	"github.com/scroll-tech/rpc-gateway/store"
 	"github.com/scroll-tech/rpc-gateway/util/rpc"
 	"github.com/sirupsen/logrus"
 )
@@ -27,12 +27,12 @@ var (
 // in rate limited way or vip mode with dedicated fullnode.
 type CfxPrunedLogsHandler struct {
 	pool       *node.CfxClientProvider
-	store      *mysql.UserStore
+	store      store.UserStore
 	throttling *throttle.RefCounter
 }
 
 func NewCfxPrunedLogsHandler(
-	pool *node.CfxClientProvider, store *mysql.UserStore, client *redis.Client) *CfxPrunedLogsHandler {
+	pool *node.CfxClientProvider, store store.UserStore, client *redis.Client) *CfxPrunedLogsHandler {
 	return &CfxPrunedLogsHandler{
 		pool:       pool,
 		store:      store,
@@ -0,0 +1,500 @@
+package handler
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	web3Types "github.com/openweb3/web3go/types"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/store"
+	itypes "github.com/scroll-tech/rpc-gateway/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// max number of blocks allowed to query fee history, which is the same as geth
+	maxEthFeeHistoryBlocks = 1024
+	// percentile of sampled priority fees to suggest max priority fee per gas
+	ethPriorityFeePercentile = 60
+
+	// EIP-1559 parameters to calculate base fee of the next block
+	ethBaseFeeChangeDenominator = 8
+	ethElasticityMultiplier     = 2
+)
+
+var (
+	errEthFeeHistoryNotCovered = errors.New("fee history not covered by gas station")
+	errEthGasPriceNoSamples    = errors.New("no gas price samples in gas station")
+	errInvalidRewardPercentile = errors.New("invalid reward percentile")
+)
+
+// EthGasStationConfig gas station configurations for EVM space.
+type EthGasStationConfig struct {
+	// whether to estimate gas price from the blocks and receipts persisted in store
+	Enabled bool
+	// interval to poll newly synchronized blocks from store
+	Interval time.Duration `default:"1s"`
+	// number of the latest blocks kept in memory to serve fee history
+	HistoryBlocks uint64 `default:"1024"`
+	// number of the latest blocks sampled to suggest gas price and priority fee
+	SampleBlocks uint64 `default:"20"`
+}
+
+// ethFeeTx fee data of an executed transaction.
+type ethFeeTx struct {
+	gasUsed  uint64
+	gasPrice *big.Int // effective gas price
+	reward   *big.Int // effective priority fee per gas
+}
+
+// ethFeeBlock fee data of a block.
+type ethFeeBlock struct {
+	number   uint64
+	hash     common.Hash
+	baseFee  *big.Int // nil if EIP-1559 not activated
+	gasUsed  uint64
+	gasLimit uint64
+	txs      []ethFeeTx // sorted by reward in ascending order
+}
+
+// gasUsedRatio returns the ratio of gas used to gas limit of the block.
+func (fb *ethFeeBlock) gasUsedRatio() float64 {
+	if fb.gasLimit == 0 {
+		return 0
+	}
+
+	return float64(fb.gasUsed) / float64(fb.gasLimit)
+}
+
+// rewards returns the effective priority fees at the specified percentiles weighted by gas
+// used, which is the same as geth does.
+func (fb *ethFeeBlock) rewards(percentiles []float64) []*hexutil.Big {
+	result := make([]*hexutil.Big, len(percentiles))
+	if len(fb.txs) == 0 { // empty block
+		for i := range result {
+			result[i] = (*hexutil.Big)(big.NewInt(0))
+		}
+
+		return result
+	}
+
+	txIndex, sumGasUsed := 0, fb.txs[0].gasUsed
+	for i, p := range percentiles {
+		thresholdGasUsed := uint64(float64(fb.gasUsed) * p / 100)
+		for sumGasUsed < thresholdGasUsed && txIndex < len(fb.txs)-1 {
+			txIndex++
+			sumGasUsed += fb.txs[txIndex].gasUsed
+		}
+
+		result[i] = (*hexutil.Big)(fb.txs[txIndex].reward)
+	}
+
+	return result
+}
+
+// nextBaseFee calculates the base fee of the next block according to EIP-1559.
+func (fb *ethFeeBlock) nextBaseFee() *big.Int {
+	if fb.baseFee == nil {
+		return nil
+	}
+
+	gasTarget := fb.gasLimit / ethElasticityMultiplier
+	if gasTarget == 0 || fb.gasUsed == gasTarget {
+		return new(big.Int).Set(fb.baseFee)
+	}
+
+	var delta uint64
+	if fb.gasUsed > gasTarget {
+		delta = fb.gasUsed - gasTarget
+	} else {
+		delta = gasTarget - fb.gasUsed
+	}
+
+	// baseFee * delta / gasTarget / denominator
+	change := new(big.Int).Mul(fb.baseFee, new(big.Int).SetUint64(delta))
+	change.Div(change, new(big.Int).SetUint64(gasTarget))
+	change.Div(change, big.NewInt(ethBaseFeeChangeDenominator))
+
+	if fb.gasUsed > gasTarget {
+		if change.Sign() == 0 {
+			change.SetInt64(1)
+		}
+
+		return change.Add(fb.baseFee, change)
+	}
+
+	change.Sub(fb.baseFee, change)
+	if change.Sign() < 0 {
+		change.SetInt64(0)
+	}
+
+	return change
+}
+
+// EthGasStationHandler RPC handler to estimate gas price and serve fee history from the latest
+// blocks and receipts persisted in store, which are kept in memory as a sliding window.
+type EthGasStationHandler struct {
+	config EthGasStationConfig
+
+	db           store.DBStore
+	storeHandler *EthStoreHandler
+
+	mu     sync.RWMutex
+	blocks []*ethFeeBlock // in ascending order of block number
+}
+
+// MustNewEthGasStationHandlerFromViper creates an EVM space gas station handler from viper
+// settings, or returns false if not enabled.
+func MustNewEthGasStationHandlerFromViper(db store.DBStore) (*EthGasStationHandler, bool) {
+	var config EthGasStationConfig
+	viper.MustUnmarshalKey("ethrpc.gasStation", &config)
+
+	if !config.Enabled {
+		return nil, false
+	}
+
+	if conf := store.EthStoreConfig(); conf.IsChainBlockDisabled() || conf.IsChainReceiptDisabled() {
+		logrus.Fatal("EVM space gas station requires both blocks and receipts persisted in store")
+	}
+
+	if config.HistoryBlocks > maxEthFeeHistoryBlocks {
+		config.HistoryBlocks = maxEthFeeHistoryBlocks
+	}
+
+	if config.SampleBlocks == 0 || config.SampleBlocks > config.HistoryBlocks {
+		logrus.WithField("config", config).Fatal("Invalid EVM space gas station sample blocks")
+	}
+
+	return NewEthGasStationHandler(db, config), true
+}
+
+func NewEthGasStationHandler(db store.DBStore, config EthGasStationConfig) *EthGasStationHandler {
+	return &EthGasStationHandler{
+		config:       config,
+		db:           db,
+		storeHandler: NewEthStoreHandler(db, nil),
+	}
+}
+
+// Run polls the newly synchronized blocks from store until the context is done.
+func (h *EthGasStationHandler) Run(ctx context.Context) {
+	logrus.WithField("config", h.config).Info("EVM space gas station started")
+
+	ticker := time.NewTicker(h.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.sync(ctx); err != nil {
+				logrus.WithError(err).Info("EVM space gas station failed to sync fee data from store")
+			}
+		}
+	}
+}
+
+func (h *EthGasStationHandler) sync(ctx context.Context) error {
+	if err := h.revertReorged(); err != nil {
+		return errors.WithMessage(err, "failed to revert reorged blocks")
+	}
+
+	maxBlock, ok, err := h.db.MaxEpoch()
+	if err != nil || !ok {
+		return err
+	}
+
+	h.mu.RLock()
+	numBlocks := len(h.blocks)
+	var nextBlock uint64
+	if numBlocks > 0 {
+		nextBlock = h.blocks[numBlocks-1].number + 1
+	} else if maxBlock >= h.config.HistoryBlocks {
+		nextBlock = maxBlock - h.config.HistoryBlocks + 1
+	}
+	h.mu.RUnlock()
+
+	for bn := nextBlock; bn <= maxBlock; bn++ {
+		fb, err := h.loadBlock(ctx, bn)
+		if err != nil && numBlocks == 0 && h.db.IsRecordNotFound(err) {
+			// block might be pruned already, skip to the next one
+			continue
+		}
+
+		if err != nil {
+			return errors.WithMessagef(err, "failed to load fee data of block %v", bn)
+		}
+
+		h.append(fb)
+		numBlocks++
+	}
+
+	return nil
+}
+
+// revertReorged removes the tail blocks that have been popped from store due to chain reorg.
+func (h *EthGasStationHandler) revertReorged() error {
+	for {
+		h.mu.RLock()
+		if len(h.blocks) == 0 {
+			h.mu.RUnlock()
+			return nil
+		}
+		tail := h.blocks[len(h.blocks)-1]
+		h.mu.RUnlock()
+
+		pivotHash, ok, err := h.db.PivotHash(tail.number)
+		if err != nil {
+			return err
+		}
+
+		if ok && pivotHash == tail.hash.Hex() {
+			return nil
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"blockNumber": tail.number, "blockHash": tail.hash,
+		}).Info("EVM space gas station reverted reorged block")
+
+		h.mu.Lock()
+		h.blocks = h.blocks[:len(h.blocks)-1]
+		h.mu.Unlock()
+	}
+}
+
+func (h *EthGasStationHandler) append(fb *ethFeeBlock) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.blocks = append(h.blocks, fb)
+	if overflow := len(h.blocks) - int(h.config.HistoryBlocks); overflow > 0 {
+		h.blocks = append(h.blocks[:0:0], h.blocks[overflow:]...)
+	}
+}
+
+// loadBlock loads fee data of the specified block from store.
+func (h *EthGasStationHandler) loadBlock(ctx context.Context, bn uint64) (*ethFeeBlock, error) {
+	blockNum := web3Types.BlockNumber(bn)
+
+	block, err := h.storeHandler.GetBlockByNumber(ctx, &blockNum, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if block == nil {
+		return nil, store.ErrNotFound
+	}
+
+	receipts, err := h.storeHandler.GetBlockReceipts(
+		ctx, &web3Types.BlockNumberOrHash{BlockNumber: &blockNum},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fb := &ethFeeBlock{
+		number:   bn,
+		hash:     block.Hash,
+		baseFee:  block.BaseFeePerGas,
+		gasUsed:  block.GasUsed,
+		gasLimit: block.GasLimit,
+		txs:      make([]ethFeeTx, 0, len(receipts)),
+	}
+
+	for i := range receipts {
+		gasPrice := new(big.Int).SetUint64(receipts[i].EffectiveGasPrice)
+
+		reward := new(big.Int).Set(gasPrice)
+		if fb.baseFee != nil {
+			reward.Sub(reward, fb.baseFee)
+		}
+
+		if reward.Sign() < 0 {
+			reward.SetInt64(0)
+		}
+
+		fb.txs = append(fb.txs, ethFeeTx{
+			gasUsed: receipts[i].GasUsed, gasPrice: gasPrice, reward: reward,
+		})
+	}
+
+	sort.Slice(fb.txs, func(i, j int) bool {
+		return fb.txs[i].reward.Cmp(fb.txs[j].reward) < 0
+	})
+
+	return fb, nil
+}
+
+// FeeHistory returns the fee market history from memory, or `errEthFeeHistoryNotCovered`
+// if the requested block range is not fully covered.
+func (h *EthGasStationHandler) FeeHistory(
+	blockCount uint64, lastBlock web3Types.BlockNumber, rewardPercentiles []float64,
+) (*web3Types.FeeHistoryResult, error) {
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 || (i > 0 && p < rewardPercentiles[i-1]) {
+			return nil, errInvalidRewardPercentile
+		}
+	}
+
+	if blockCount == 0 {
+		return nil, errEthFeeHistoryNotCovered
+	}
+
+	if blockCount > maxEthFeeHistoryBlocks {
+		blockCount = maxEthFeeHistoryBlocks
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.blocks) == 0 {
+		return nil, errEthFeeHistoryNotCovered
+	}
+
+	head, tail := h.blocks[0].number, h.blocks[len(h.blocks)-1].number
+
+	var last uint64
+	switch {
+	case lastBlock == web3Types.LatestBlockNumber || lastBlock == web3Types.PendingBlockNumber:
+		last = tail
+	case lastBlock >= 0:
+		last = uint64(lastBlock)
+	default: // e.g., `earliest`
+		return nil, errEthFeeHistoryNotCovered
+	}
+
+	if last > tail {
+		return nil, errEthFeeHistoryNotCovered
+	}
+
+	if blockCount > last+1 {
+		blockCount = last + 1
+	}
+
+	oldest := last + 1 - blockCount
+	if oldest < head {
+		return nil, errEthFeeHistoryNotCovered
+	}
+
+	result := &web3Types.FeeHistoryResult{
+		OldestBlock:  (*hexutil.Big)(new(big.Int).SetUint64(oldest)),
+		BaseFee:      make([]*hexutil.Big, 0, blockCount+1),
+		GasUsedRatio: make([]float64, 0, blockCount),
+	}
+
+	if len(rewardPercentiles) > 0 {
+		result.Reward = make([][]*hexutil.Big, 0, blockCount)
+	}
+
+	for bn := oldest; bn <= last; bn++ {
+		fb := h.blocks[bn-head]
+
+		result.BaseFee = append(result.BaseFee, ethBaseFeeOrZero(fb.baseFee))
+		result.GasUsedRatio = append(result.GasUsedRatio, fb.gasUsedRatio())
+
+		if len(rewardPercentiles) > 0 {
+			result.Reward = append(result.Reward, fb.rewards(rewardPercentiles))
+		}
+	}
+
+	// base fee of the next block
+	if last < tail {
+		result.BaseFee = append(result.BaseFee, ethBaseFeeOrZero(h.blocks[last+1-head].baseFee))
+	} else {
+		result.BaseFee = append(result.BaseFee, ethBaseFeeOrZero(h.blocks[last-head].nextBaseFee()))
+	}
+
+	return result, nil
+}
+
+// MaxPriorityFeePerGas suggests the priority fee per gas from the latest sampled blocks.
+func (h *EthGasStationHandler) MaxPriorityFeePerGas() (*hexutil.Big, error) {
+	var rewards []*big.Int
+	h.iterateSampledTxs(func(tx *ethFeeTx) {
+		rewards = append(rewards, tx.reward)
+	})
+
+	if len(rewards) == 0 {
+		return nil, errEthGasPriceNoSamples
+	}
+
+	return (*hexutil.Big)(ethPercentileOf(rewards, ethPriorityFeePercentile)), nil
+}
+
+// GetPrice suggests gas prices of different levels from the latest sampled blocks, or base fee
+// of the next block if no transaction sampled.
+func (h *EthGasStationHandler) GetPrice() (*itypes.GasStationPrice, error) {
+	var gasPrices []*big.Int
+	h.iterateSampledTxs(func(tx *ethFeeTx) {
+		gasPrices = append(gasPrices, tx.gasPrice)
+	})
+
+	if len(gasPrices) > 0 {
+		return &itypes.GasStationPrice{
+			SafeLow: (*hexutil.Big)(ethPercentileOf(gasPrices, 25)),
+			Average: (*hexutil.Big)(ethPercentileOf(gasPrices, 50)),
+			Fast:    (*hexutil.Big)(ethPercentileOf(gasPrices, 75)),
+			Fastest: (*hexutil.Big)(ethPercentileOf(gasPrices, 95)),
+		}, nil
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.blocks) == 0 {
+		return nil, errEthGasPriceNoSamples
+	}
+
+	baseFee := h.blocks[len(h.blocks)-1].nextBaseFee()
+	if baseFee == nil {
+		return nil, errEthGasPriceNoSamples
+	}
+
+	return &itypes.GasStationPrice{
+		SafeLow: (*hexutil.Big)(baseFee),
+		Average: (*hexutil.Big)(baseFee),
+		Fast:    (*hexutil.Big)(baseFee),
+		Fastest: (*hexutil.Big)(baseFee),
+	}, nil
+}
+
+// iterateSampledTxs iterates transactions within the latest sampled blocks.
+func (h *EthGasStationHandler) iterateSampledTxs(consumer func(tx *ethFeeTx)) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	start := 0
+	if len(h.blocks) > int(h.config.SampleBlocks) {
+		start = len(h.blocks) - int(h.config.SampleBlocks)
+	}
+
+	for _, fb := range h.blocks[start:] {
+		for i := range fb.txs {
+			consumer(&fb.txs[i])
+		}
+	}
+}
+
+// ethPercentileOf returns the value at the specified percentile of the unsorted values.
+func ethPercentileOf(values []*big.Int, percentile int) *big.Int {
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Cmp(values[j]) < 0
+	})
+
+	return values[(len(values)-1)*percentile/100]
+}
+
+func ethBaseFeeOrZero(baseFee *big.Int) *hexutil.Big {
+	if baseFee == nil {
+		return (*hexutil.Big)(big.NewInt(0))
+	}
+
+	return (*hexutil.Big)(baseFee)
+}
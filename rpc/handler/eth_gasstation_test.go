@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"math/big"
+	"testing"
+
+	web3Types "github.com/openweb3/web3go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEthGasStationHandler() *EthGasStationHandler {
+	h := &EthGasStationHandler{
+		config: EthGasStationConfig{HistoryBlocks: 3, SampleBlocks: 2},
+	}
+
+	for bn := uint64(10); bn <= 13; bn++ {
+		h.append(&ethFeeBlock{
+			number:   bn,
+			baseFee:  big.NewInt(100),
+			gasUsed:  30,
+			gasLimit: 40,
+			txs: []ethFeeTx{ // sorted by reward
+				{gasUsed: 10, gasPrice: big.NewInt(101), reward: big.NewInt(1)},
+				{gasUsed: 20, gasPrice: big.NewInt(100 + int64(bn)), reward: big.NewInt(int64(bn))},
+			},
+		})
+	}
+
+	return h
+}
+
+func TestEthGasStationFeeHistory(t *testing.T) {
+	h := newTestEthGasStationHandler()
+
+	// only the latest 3 blocks kept in memory
+	_, err := h.FeeHistory(4, web3Types.LatestBlockNumber, nil)
+	assert.Equal(t, errEthFeeHistoryNotCovered, err)
+
+	_, err = h.FeeHistory(1, web3Types.BlockNumber(14), nil)
+	assert.Equal(t, errEthFeeHistoryNotCovered, err)
+
+	_, err = h.FeeHistory(1, web3Types.LatestBlockNumber, []float64{50, 10})
+	assert.Equal(t, errInvalidRewardPercentile, err)
+
+	result, err := h.FeeHistory(2, web3Types.BlockNumber(12), []float64{0, 50})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(11), result.OldestBlock.ToInt().Uint64())
+	assert.Equal(t, []float64{0.75, 0.75}, result.GasUsedRatio)
+	if assert.Equal(t, 2, len(result.Reward)) {
+		assert.Equal(t, int64(1), result.Reward[0][0].ToInt().Int64())
+		assert.Equal(t, int64(11), result.Reward[0][1].ToInt().Int64())
+	}
+
+	// next base fee: 100 + 100 * (30 - 20) / 20 / 8
+	result, err = h.FeeHistory(1, web3Types.LatestBlockNumber, nil)
+	require.NoError(t, err)
+	if assert.Equal(t, 2, len(result.BaseFee)) {
+		assert.Equal(t, int64(106), result.BaseFee[1].ToInt().Int64())
+	}
+}
+
+func TestEthGasStationSuggestion(t *testing.T) {
+	h := newTestEthGasStationHandler()
+
+	// rewards sampled from the latest 2 blocks: [1, 1, 12, 13]
+	priorityFee, err := h.MaxPriorityFeePerGas()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), priorityFee.ToInt().Int64())
+
+	price, err := h.GetPrice()
+	require.NoError(t, err)
+	assert.Equal(t, int64(101), price.SafeLow.ToInt().Int64())
+	assert.Equal(t, int64(112), price.Fastest.ToInt().Int64())
+}
@@ -9,18 +9,19 @@ import (
 	"github.com/openweb3/web3go/types"
 	"github.com/scroll-tech/rpc-gateway/rpc/ethbridge"
 	"github.com/scroll-tech/rpc-gateway/store"
-	"github.com/scroll-tech/rpc-gateway/store/mysql"
 	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // EthLogsApiHandler RPC handler to get evm space event logs from store or fullnode.
 type EthLogsApiHandler struct {
-	ms *mysql.MysqlStore
+	ms store.DBStore
 
 	networkId atomic.Value
 }
 
-func NewEthLogsApiHandler(ms *mysql.MysqlStore) *EthLogsApiHandler {
+func NewEthLogsApiHandler(ms store.DBStore) *EthLogsApiHandler {
 	return &EthLogsApiHandler{ms: ms}
 }
 
@@ -68,9 +69,15 @@ func (handler *EthLogsApiHandler) getLogsReorgGuard(
 	ctx context.Context,
 	eth *client.RpcEthClient,
 	filter *types.FilterQuery,
-) ([]types.Log, bool, error) {
+) (logs []types.Log, hitStore bool, err error) {
+	ctx, span := tracing.Start(ctx, "getLogsReorgGuard")
+	defer func() {
+		span.SetAttributes(tracing.AttrStoreHit.Bool(hitStore))
+		tracing.End(span, err)
+	}()
+
 	// Try to query event logs from database and fullnode.
-	dbFilter, fnFilter, err := handler.splitLogFilter(eth, filter)
+	dbFilter, fnFilter, err := handler.splitLogFilter(ctx, eth, filter)
 	if err != nil {
 		return nil, false, err
 	}
@@ -79,8 +86,6 @@ func (handler *EthLogsApiHandler) getLogsReorgGuard(
 	metrics.Registry.RPC.Percentage("eth_getLogs", "filter/split/allfullnode").Mark(dbFilter == nil)
 	metrics.Registry.RPC.Percentage("eth_getLogs", "filter/split/partial").Mark(dbFilter != nil && fnFilter != nil)
 
-	var logs []types.Log
-
 	// query data from database
 	if dbFilter != nil {
 		dbLogs, err := handler.ms.GetLogs(ctx, *dbFilter)
@@ -107,8 +112,9 @@ func (handler *EthLogsApiHandler) getLogsReorgGuard(
 			return nil, false, err
 		}
 
-		fnLogs, err := eth.Logs(*fnFilter)
-		if err != nil {
+		// delegate with context so that the fullnode call is traced as a child span
+		var fnLogs []types.Log
+		if err := eth.CallContext(ctx, &fnLogs, "eth_getLogs", *fnFilter); err != nil {
 			return nil, false, err
 		}
 
@@ -123,6 +129,7 @@ func (handler *EthLogsApiHandler) getLogsReorgGuard(
 }
 
 func (handler *EthLogsApiHandler) splitLogFilter(
+	ctx context.Context,
 	eth *client.RpcEthClient,
 	filter *types.FilterQuery,
 ) (*store.LogFilter, *types.FilterQuery, error) {
@@ -139,7 +146,7 @@ func (handler *EthLogsApiHandler) splitLogFilter(
 		return handler.splitLogFilterByBlockHash(eth, filter, maxBlock)
 	}
 
-	return handler.splitLogFilterByBlockRange(eth, filter, maxBlock)
+	return handler.splitLogFilterByBlockRange(ctx, eth, filter, maxBlock)
 }
 
 func (handler *EthLogsApiHandler) splitLogFilterByBlockHash(
@@ -172,10 +179,14 @@ func (handler *EthLogsApiHandler) splitLogFilterByBlockHash(
 }
 
 func (handler *EthLogsApiHandler) splitLogFilterByBlockRange(
+	ctx context.Context,
 	eth *client.RpcEthClient,
 	filter *types.FilterQuery,
 	maxBlock uint64,
 ) (*store.LogFilter, *types.FilterQuery, error) {
+	_, span := tracing.Start(ctx, "splitLogFilterByBlockRange", attribute.Int64("maxBlock", int64(maxBlock)))
+	defer span.End()
+
 	if filter.FromBlock == nil || *filter.FromBlock < 0 {
 		return nil, filter, nil
 	}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"context"
+	"math"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	web3Types "github.com/openweb3/web3go/types"
 	"github.com/scroll-tech/rpc-gateway/rpc/cfxbridge"
 	"github.com/scroll-tech/rpc-gateway/rpc/ethbridge"
@@ -150,3 +153,125 @@ func (h *EthStoreHandler) GetTransactionReceipt(ctx context.Context, txHash comm
 
 	return nil, err
 }
+
+func (h *EthStoreHandler) GetBlockTransactionCountByHash(ctx context.Context, blockHash common.Hash) (*hexutil.Big, error) {
+	cfxBlockHash := cfxbridge.ConvertHash(blockHash)
+
+	count, err := h.store.GetBlockTransactionCountByHash(ctx, cfxBlockHash)
+	if err == nil {
+		return (*hexutil.Big)(new(big.Int).SetUint64(count)), nil
+	}
+
+	if !util.IsInterfaceValNil(h.next) {
+		return h.next.GetBlockTransactionCountByHash(ctx, blockHash)
+	}
+
+	return nil, err
+}
+
+func (h *EthStoreHandler) GetBlockTransactionCountByNumber(
+	ctx context.Context, blockNum *web3Types.BlockNumber,
+) (*hexutil.Big, error) {
+	if *blockNum <= 0 {
+		return nil, store.ErrUnsupported
+	}
+
+	count, err := h.store.GetBlockTransactionCountByNumber(ctx, uint64(*blockNum))
+	if err == nil {
+		return (*hexutil.Big)(new(big.Int).SetUint64(count)), nil
+	}
+
+	if !util.IsInterfaceValNil(h.next) {
+		return h.next.GetBlockTransactionCountByNumber(ctx, blockNum)
+	}
+
+	return nil, err
+}
+
+func (h *EthStoreHandler) GetTransactionByBlockHashAndIndex(
+	ctx context.Context, blockHash common.Hash, index hexutil.Uint,
+) (*web3Types.TransactionDetail, error) {
+	if uint64(index) > math.MaxUint32 { // out of range
+		return nil, nil
+	}
+
+	cfxBlockHash := cfxbridge.ConvertHash(blockHash)
+
+	stx, err := h.store.GetTransactionByBlockHashAndIndex(ctx, cfxBlockHash, uint32(index))
+	if err == nil {
+		return convertStoreTx(stx), nil
+	}
+
+	if !util.IsInterfaceValNil(h.next) {
+		return h.next.GetTransactionByBlockHashAndIndex(ctx, blockHash, index)
+	}
+
+	return nil, err
+}
+
+func (h *EthStoreHandler) GetTransactionByBlockNumberAndIndex(
+	ctx context.Context, blockNum *web3Types.BlockNumber, index hexutil.Uint,
+) (*web3Types.TransactionDetail, error) {
+	if *blockNum <= 0 {
+		return nil, store.ErrUnsupported
+	}
+
+	if uint64(index) > math.MaxUint32 { // out of range
+		return nil, nil
+	}
+
+	stx, err := h.store.GetTransactionByBlockNumberAndIndex(ctx, uint64(*blockNum), uint32(index))
+	if err == nil {
+		return convertStoreTx(stx), nil
+	}
+
+	if !util.IsInterfaceValNil(h.next) {
+		return h.next.GetTransactionByBlockNumberAndIndex(ctx, blockNum, index)
+	}
+
+	return nil, err
+}
+
+// convertStoreTx converts store transaction to eth transaction, which returns nil if transaction
+// not found, e.g. index out of range.
+func convertStoreTx(stx *store.Transaction) *web3Types.TransactionDetail {
+	if stx == nil {
+		return nil
+	}
+
+	return ethbridge.ConvertTx(stx.CfxTransaction, stx.Extra)
+}
+
+func (h *EthStoreHandler) GetBlockReceipts(
+	ctx context.Context, blockNumOrHash *web3Types.BlockNumberOrHash,
+) ([]web3Types.Receipt, error) {
+	var srcpts []*store.TransactionReceipt
+	var err error
+
+	switch {
+	case blockNumOrHash == nil: // defaults to `latest`
+		return nil, store.ErrUnsupported
+	case blockNumOrHash.BlockHash != nil:
+		cfxBlockHash := cfxbridge.ConvertHash(*blockNumOrHash.BlockHash)
+		srcpts, err = h.store.GetReceiptsByBlockHash(ctx, cfxBlockHash)
+	case blockNumOrHash.BlockNumber != nil && *blockNumOrHash.BlockNumber > 0:
+		srcpts, err = h.store.GetReceiptsByBlockNumber(ctx, uint64(*blockNumOrHash.BlockNumber))
+	default:
+		return nil, store.ErrUnsupported
+	}
+
+	if err == nil {
+		receipts := make([]web3Types.Receipt, 0, len(srcpts))
+		for _, v := range srcpts {
+			receipts = append(receipts, *ethbridge.ConvertReceipt(v.CfxReceipt, v.Extra))
+		}
+
+		return receipts, nil
+	}
+
+	if !util.IsInterfaceValNil(h.next) {
+		return h.next.GetBlockReceipts(ctx, blockNumOrHash)
+	}
+
+	return nil, err
+}
@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	web3Types "github.com/openweb3/web3go/types"
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/rpc/cfxbridge"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	rpcutil "github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+// EthTxTrackerConfig represents the configurations to track evm space transactions submitted
+// via gateway.
+type EthTxTrackerConfig struct {
+	// whether to track transactions submitted via gateway
+	Enabled bool
+	// whether to check the status of tracked transactions and rebroadcast in this instance, which
+	// should be enabled for only one of the RPC service instances sharing the same database
+	Checker bool
+	// interval to check the status of tracked transactions
+	Interval time.Duration `default:"5s"`
+	// max number of tracked transactions to check each time
+	BatchSize int `default:"100"`
+	// interval to rebroadcast transaction disappeared from the pool of fullnode
+	RebroadcastInterval time.Duration `default:"1m"`
+	// max number of times to rebroadcast, after which transaction is regarded as dropped
+	MaxRebroadcasts int `default:"5"`
+	// max duration to track transaction, after which transaction is regarded as dropped
+	// once disappeared from the pool of fullnode
+	Expiry time.Duration `default:"1h"`
+}
+
+// EthTxTracker tracks the status of evm space transactions submitted via gateway until mined,
+// dropped or replaced, and rebroadcasts the pending ones disappeared from the pool of fullnode.
+type EthTxTracker struct {
+	conf     *EthTxTrackerConfig
+	db       store.DBStore
+	provider *node.EthClientProvider
+}
+
+func MustNewEthTxTrackerFromViper(db store.DBStore, provider *node.EthClientProvider) (*EthTxTracker, bool) {
+	var conf EthTxTrackerConfig
+	viper.MustUnmarshalKey("ethrpc.txTracker", &conf)
+
+	if !conf.Enabled {
+		return nil, false
+	}
+
+	return NewEthTxTracker(db, provider, &conf), true
+}
+
+func NewEthTxTracker(db store.DBStore, provider *node.EthClientProvider, conf *EthTxTrackerConfig) *EthTxTracker {
+	return &EthTxTracker{conf: conf, db: db, provider: provider}
+}
+
+// Track records the raw transaction accepted by the specified fullnode to track its status.
+func (t *EthTxTracker) Track(signedTx hexutil.Bytes, txHash common.Hash, nodeName string) error {
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(signedTx); err != nil {
+		return errors.WithMessage(err, "failed to decode raw transaction")
+	}
+
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), &tx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to recover transaction sender")
+	}
+
+	now := time.Now()
+
+	return t.db.AddTxSubmission(&store.TxSubmission{
+		Hash:        txHash.Hex(),
+		Sender:      sender.Hex(),
+		Nonce:       tx.Nonce(),
+		RawTx:       signedTx,
+		Nodes:       nodeName,
+		Status:      store.TxStatusSubmitted,
+		SubmittedAt: now,
+		BroadcastAt: now,
+		CheckedAt:   now,
+	})
+}
+
+// GetStatus returns the tracked transaction by hash, or false if not submitted via gateway.
+func (t *EthTxTracker) GetStatus(txHash common.Hash) (*store.TxSubmission, bool, error) {
+	return t.db.GetTxSubmission(txHash.Hex())
+}
+
+// IsChecker returns whether to check the status of tracked transactions in this instance.
+func (t *EthTxTracker) IsChecker() bool {
+	return t.conf.Checker
+}
+
+// Run checks the status of tracked transactions periodically until the context done.
+func (t *EthTxTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.conf.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.checkOnce(ctx); err != nil {
+				logrus.WithError(err).Info("Tx tracker failed to check submitted transactions")
+			}
+		}
+	}
+}
+
+func (t *EthTxTracker) checkOnce(ctx context.Context) error {
+	subs, err := t.db.GetTrackingTxSubmissions(t.conf.BatchSize)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get tracking transactions")
+	}
+
+	for _, sub := range subs {
+		if err := t.check(ctx, sub); err != nil {
+			logrus.WithField("txHash", sub.Hash).WithError(err).Debug("Tx tracker failed to check transaction")
+		}
+	}
+
+	return nil
+}
+
+// check updates the status of the tracked transaction, and rebroadcasts it if disappeared from
+// the pool of fullnode.
+func (t *EthTxTracker) check(ctx context.Context, sub *store.TxSubmission) error {
+	now := time.Now()
+	sub.CheckedAt = now
+
+	// watch the mined transaction through the synced receipts
+	txHash := common.HexToHash(sub.Hash)
+	if rcpt, err := t.db.GetReceipt(ctx, cfxbridge.ConvertHash(txHash)); err == nil {
+		if rcpt.CfxReceipt != nil && rcpt.CfxReceipt.EpochNumber != nil {
+			return t.conclude(sub, store.TxStatusMined, uint64(*rcpt.CfxReceipt.EpochNumber), now)
+		}
+	}
+
+	tx, w3c, err := t.findTransaction(sub, txHash)
+	if err != nil {
+		return err
+	}
+
+	if tx != nil {
+		// receipts might be not synced yet or disabled to store
+		if tx.BlockNumber != nil {
+			return t.conclude(sub, store.TxStatusMined, tx.BlockNumber.Uint64(), now)
+		}
+
+		if sub.PendingAt == nil {
+			sub.Status, sub.PendingAt = store.TxStatusPending, &now
+		}
+
+		return t.db.UpdateTxSubmission(sub)
+	}
+
+	// disappeared from the pool of fullnode
+	latest := web3Types.BlockNumberOrHashWithNumber(web3Types.LatestBlockNumber)
+
+	nonce, err := w3c.Eth.TransactionCount(common.HexToAddress(sub.Sender), &latest)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get sender nonce")
+	}
+
+	if nonce.Uint64() > sub.Nonce {
+		// transaction might be mined just now, which is not in the pool of routed fullnode
+		rcpt, err := w3c.Eth.TransactionReceipt(txHash)
+		if err != nil {
+			return errors.WithMessage(err, "failed to get transaction receipt")
+		}
+
+		if rcpt != nil {
+			return t.conclude(sub, store.TxStatusMined, rcpt.BlockNumber, now)
+		}
+
+		return t.conclude(sub, store.TxStatusReplaced, 0, now)
+	}
+
+	if now.Sub(sub.BroadcastAt) < t.conf.RebroadcastInterval {
+		return t.db.UpdateTxSubmission(sub)
+	}
+
+	if sub.Rebroadcasts >= t.conf.MaxRebroadcasts || now.Sub(sub.SubmittedAt) > t.conf.Expiry {
+		return t.conclude(sub, store.TxStatusDropped, 0, now)
+	}
+
+	// rebroadcast to a random fullnode, so that transaction propagates via more fullnodes
+	if w3c, err = t.provider.GetClientRandom(); err != nil {
+		return errors.WithMessage(err, "failed to get fullnode client")
+	}
+
+	t.rebroadcast(w3c, sub, now)
+
+	return t.db.UpdateTxSubmission(sub)
+}
+
+// findTransaction gets the transaction from the fullnodes that it was sent to, since transaction
+// may not be propagated to the pool of other fullnodes. If none of them available, a random
+// fullnode is requested instead. Returns the last requested fullnode client along with the
+// transaction, which is nil if not found.
+func (t *EthTxTracker) findTransaction(
+	sub *store.TxSubmission, txHash common.Hash,
+) (*web3Types.TransactionDetail, *node.Web3goClient, error) {
+	var clients []*node.Web3goClient
+
+	for _, nodeName := range strings.Split(sub.Nodes, ",") {
+		if w3c, err := t.provider.GetClientByNodeName(node.GroupEthHttp, nodeName); err == nil {
+			clients = append(clients, w3c)
+		}
+	}
+
+	if len(clients) == 0 {
+		w3c, err := t.provider.GetClientRandom()
+		if err != nil {
+			return nil, nil, errors.WithMessage(err, "failed to get fullnode client")
+		}
+
+		clients = append(clients, w3c)
+	}
+
+	var lastErr error
+
+	for _, w3c := range clients {
+		tx, err := w3c.Eth.TransactionByHash(txHash)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if tx != nil {
+			return tx, w3c, nil
+		}
+	}
+
+	// never regarded as disappeared unless all fullnodes sent to responded
+	if lastErr != nil {
+		return nil, nil, errors.WithMessage(lastErr, "failed to get transaction")
+	}
+
+	return nil, clients[len(clients)-1], nil
+}
+
+// rebroadcast sends the raw transaction to fullnode again, regardless of the result, e.g. already
+// known by fullnode.
+func (t *EthTxTracker) rebroadcast(w3c *node.Web3goClient, sub *store.TxSubmission, now time.Time) {
+	_, err := w3c.Eth.SendRawTransaction(sub.RawTx)
+
+	metrics.Registry.RPC.TxRelay("eth", "rebroadcast").Mark(1)
+	logrus.WithFields(logrus.Fields{
+		"txHash": sub.Hash,
+		"node":   w3c.URL,
+		"times":  sub.Rebroadcasts + 1,
+	}).WithError(err).Debug("Tx tracker rebroadcasted transaction disappeared from pool")
+
+	sub.Rebroadcasts++
+	sub.BroadcastAt = now
+
+	nodeName := rpcutil.Url2NodeName(w3c.URL)
+	if !strings.Contains(","+sub.Nodes+",", ","+nodeName+",") {
+		sub.Nodes = strings.Join([]string{sub.Nodes, nodeName}, ",")
+	}
+}
+
+// conclude finalizes the status of tracked transaction. Once mined, other tracked transactions of
+// the same sender and nonce are concluded as replaced by it.
+func (t *EthTxTracker) conclude(sub *store.TxSubmission, status store.TxStatus, blockNum uint64, now time.Time) error {
+	sub.Status, sub.BlockNumber, sub.ConcludedAt = status, blockNum, &now
+
+	siblings, err := t.db.GetTxSubmissionsByNonce(sub.Sender, sub.Nonce)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get transactions of the same nonce")
+	}
+
+	var replaced []*store.TxSubmission
+
+	for _, s := range siblings {
+		switch {
+		case s.Hash == sub.Hash:
+		case status == store.TxStatusReplaced && s.Status == store.TxStatusMined:
+			sub.ReplacedBy = s.Hash
+		case status == store.TxStatusMined && !s.Status.Concluded():
+			s.Status, s.ReplacedBy, s.ConcludedAt, s.CheckedAt = store.TxStatusReplaced, sub.Hash, &now, now
+			replaced = append(replaced, s)
+		case status == store.TxStatusMined && s.Status == store.TxStatusReplaced && len(s.ReplacedBy) == 0:
+			// concluded as replaced before the replacing transaction observed
+			s.ReplacedBy = sub.Hash
+			replaced = append(replaced, s)
+		}
+	}
+
+	for _, s := range append(replaced, sub) {
+		if err := t.db.UpdateTxSubmission(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/sqlite"
+	rpcutil "github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTxPool mocks the pool of fullnode, which serves the transactions in pool or mined.
+type testTxPool struct {
+	mu          sync.Mutex
+	nonce       uint64                 // account nonce at the latest block
+	txs         map[common.Hash]uint64 // tx hash => block number, 0 if pending
+	broadcasted int
+	nodeName    string
+}
+
+func (p *testTxPool) serve(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage
+		Method string
+		Params []json.RawMessage
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := "null"
+
+	switch req.Method {
+	case "eth_getTransactionByHash", "eth_getTransactionReceipt":
+		var txHash common.Hash
+		json.Unmarshal(req.Params[0], &txHash)
+
+		if bn, ok := p.txs[txHash]; ok && (bn > 0 || req.Method == "eth_getTransactionByHash") {
+			blockNum := "null"
+			if bn > 0 {
+				blockNum = fmt.Sprintf(`"0x%x"`, bn)
+			}
+
+			result = fmt.Sprintf(
+				`{"hash":"%v","blockNumber":%v,"gas":"0x0","input":"0x","nonce":"0x0",`+
+					`"r":"0x0","s":"0x0","v":"0x0","value":"0x0"}`,
+				txHash.Hex(), blockNum,
+			)
+		}
+	case "eth_getTransactionCount":
+		result = fmt.Sprintf(`"0x%x"`, p.nonce)
+	case "eth_sendRawTransaction":
+		p.broadcasted++
+		result = fmt.Sprintf(`"%v"`, common.Hash{}.Hex())
+	}
+
+	fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%v}`, req.ID, result)
+}
+
+func newTestEthTxTracker(t *testing.T, pools ...*testTxPool) *EthTxTracker {
+	var urls []string
+
+	for _, pool := range pools {
+		server := httptest.NewServer(http.HandlerFunc(pool.serve))
+		t.Cleanup(server.Close)
+
+		pool.nodeName = rpcutil.Url2NodeName(server.URL)
+		urls = append(urls, server.URL)
+	}
+
+	config := sqlite.Config{Path: filepath.Join(t.TempDir(), "confura.db"), BusyTimeout: time.Second}
+	db := config.MustOpenOrCreate(sqlite.StoreOption{})
+	t.Cleanup(func() { db.Close() })
+
+	provider := node.NewEthClientProvider(node.NewLocalRouter(map[node.Group][]string{
+		node.GroupEthHttp: urls,
+	}))
+
+	return NewEthTxTracker(db, provider, &EthTxTrackerConfig{
+		BatchSize: 10, MaxRebroadcasts: 1, Expiry: time.Hour,
+	})
+}
+
+func newTestSignedTx(t *testing.T, nonce uint64, gasPrice int64) (hexutil.Bytes, common.Hash) {
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	require.NoError(t, err)
+
+	tx, err := types.SignTx(
+		types.NewTransaction(nonce, common.Address{}, big.NewInt(1), 21000, big.NewInt(gasPrice), nil),
+		types.LatestSignerForChainID(big.NewInt(1)), key,
+	)
+	require.NoError(t, err)
+
+	raw, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	return raw, tx.Hash()
+}
+
+func TestEthTxTrackerMinedAndReplaced(t *testing.T) {
+	pool := &testTxPool{txs: make(map[common.Hash]uint64)}
+	tracker := newTestEthTxTracker(t, pool)
+
+	raw1, hash1 := newTestSignedTx(t, 0, 100)
+	raw2, hash2 := newTestSignedTx(t, 0, 200) // speed up
+	require.NoError(t, tracker.Track(raw1, hash1, "node1"))
+	require.NoError(t, tracker.Track(raw2, hash2, "node2"))
+
+	// the speed up transaction in pool, while the other one disappeared and rebroadcasted
+	pool.txs[hash2] = 0
+	require.NoError(t, tracker.checkOnce(context.Background()))
+
+	sub1, ok, err := tracker.GetStatus(hash1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, store.TxStatusSubmitted, sub1.Status)
+	assert.Equal(t, 1, sub1.Rebroadcasts)
+	assert.Equal(t, 1, pool.broadcasted)
+
+	sub2, _, _ := tracker.GetStatus(hash2)
+	assert.Equal(t, store.TxStatusPending, sub2.Status)
+	assert.NotNil(t, sub2.PendingAt)
+
+	// the speed up transaction mined
+	pool.txs[hash2], pool.nonce = 16, 1
+	require.NoError(t, tracker.checkOnce(context.Background()))
+
+	sub2, _, _ = tracker.GetStatus(hash2)
+	assert.Equal(t, store.TxStatusMined, sub2.Status)
+	assert.Equal(t, uint64(16), sub2.BlockNumber)
+	assert.NotNil(t, sub2.ConcludedAt)
+
+	sub1, _, _ = tracker.GetStatus(hash1)
+	assert.Equal(t, store.TxStatusReplaced, sub1.Status)
+	assert.Equal(t, hash2.Hex(), sub1.ReplacedBy)
+
+	// concluded transactions no longer tracked
+	subs, err := tracker.db.GetTrackingTxSubmissions(10)
+	require.NoError(t, err)
+	assert.Empty(t, subs)
+}
+
+func TestEthTxTrackerDropped(t *testing.T) {
+	pool := &testTxPool{txs: make(map[common.Hash]uint64)}
+	tracker := newTestEthTxTracker(t, pool)
+
+	raw, hash := newTestSignedTx(t, 0, 100)
+	require.NoError(t, tracker.Track(raw, hash, "node1"))
+
+	// rebroadcasted once, and then dropped
+	for i := 0; i < 2; i++ {
+		require.NoError(t, tracker.checkOnce(context.Background()))
+	}
+
+	sub, _, err := tracker.GetStatus(hash)
+	require.NoError(t, err)
+	assert.Equal(t, store.TxStatusDropped, sub.Status)
+	assert.Equal(t, 1, sub.Rebroadcasts)
+	assert.Equal(t, 1, pool.broadcasted)
+}
+
+func TestEthTxTrackerPendingOnRelayedNode(t *testing.T) {
+	relayed := &testTxPool{txs: make(map[common.Hash]uint64)}
+	other := &testTxPool{txs: make(map[common.Hash]uint64)}
+	tracker := newTestEthTxTracker(t, relayed, other)
+
+	raw, hash := newTestSignedTx(t, 0, 100)
+	require.NoError(t, tracker.Track(raw, hash, relayed.nodeName))
+
+	// not propagated to the pool of other fullnode yet
+	relayed.txs[hash] = 0
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, tracker.checkOnce(context.Background()))
+	}
+
+	sub, _, err := tracker.GetStatus(hash)
+	require.NoError(t, err)
+	assert.Equal(t, store.TxStatusPending, sub.Status)
+	assert.Zero(t, sub.Rebroadcasts)
+	assert.Zero(t, relayed.broadcasted+other.broadcasted)
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 
 	"github.com/openweb3/web3go/types"
+	"github.com/scroll-tech/rpc-gateway/rpc/handler"
 )
 
 // parityAPI provides evm space parity RPC proxy API.
-type parityAPI struct{}
+type parityAPI struct {
+	storeHandler *handler.EthStoreHandler
+}
 
 func (api *parityAPI) GetBlockReceipts(ctx context.Context, blockNumOrHash *types.BlockNumberOrHash) ([]types.Receipt, error) {
-	return GetEthClientFromContext(ctx).Parity.BlockReceipts(blockNumOrHash)
+	return getEthBlockReceipts(ctx, api.storeHandler, "parity_getBlockReceipts", blockNumOrHash)
 }
@@ -0,0 +1,92 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/openweb3/go-rpc-provider"
+	"github.com/openweb3/go-rpc-provider/utils"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/sirupsen/logrus"
+)
+
+// errCodeMethodNotFound is the JSON-RPC error code if method not found on RPC server.
+const errCodeMethodNotFound = -32601
+
+// routing tables to determine the passthrough namespaces of core space and evm space
+var (
+	cfxPassthroughTable = node.CfxRoutingTable()
+	ethPassthroughTable = node.EthRoutingTable()
+)
+
+// passthroughMiddleware forwards the RPC methods that are not served by RPC server verbatim to
+// the routed fullnode, if the method namespace is configured as passthrough.
+func passthroughMiddleware(next rpc.HandleCallMsgFunc) rpc.HandleCallMsgFunc {
+	return func(ctx context.Context, msg *rpc.JsonRpcMessage) *rpc.JsonRpcMessage {
+		resp := next(ctx, msg)
+		if resp == nil || resp.Error == nil || resp.Error.ErrorCode() != errCodeMethodNotFound {
+			return resp
+		}
+
+		var table *node.RoutingTable
+		switch ctx.Value(ctxKeyClientProvider).(type) {
+		case *node.CfxClientProvider:
+			table = cfxPassthroughTable
+		case *node.EthClientProvider:
+			table = ethPassthroughTable
+		default:
+			return resp
+		}
+
+		if !table.IsPassthrough(msg.Method) {
+			return resp
+		}
+
+		var params []json.RawMessage
+		if len(msg.Params) > 0 {
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				return resp
+			}
+		}
+
+		// raw params are marshaled verbatim when forwarded
+		args := make([]interface{}, len(params))
+		for i := range params {
+			args[i] = params[i]
+		}
+
+		var result json.RawMessage
+		var err error
+
+		switch client := ctx.Value(ctxKeyClient).(type) {
+		case *node.Web3goClient:
+			err = client.Provider().CallContext(ctx, &result, msg.Method, args...)
+		case sdk.ClientOperator:
+			err = client.CallRPC(&result, msg.Method, args...)
+		default:
+			return resp
+		}
+
+		logger := logrus.WithFields(logrus.Fields{
+			"method": msg.Method,
+			"node":   clientNodeName(ctx.Value(ctxKeyClient)),
+		})
+
+		if err != nil && !utils.IsRPCJSONError(err) { // generally io error
+			logger.WithError(err).Info("Failed to passthrough RPC method to fullnode")
+		} else {
+			logger.WithError(err).Debug("Passthrough RPC method to fullnode")
+		}
+
+		if err != nil {
+			return msg.ErrorResponse(err)
+		}
+
+		if len(result) == 0 {
+			result = json.RawMessage("null")
+		}
+
+		return &rpc.JsonRpcMessage{Version: msg.Version, ID: msg.ID, Result: result}
+	}
+}
@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openweb3/go-rpc-provider"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// methodNotFoundError is the error responded by RPC server if method not served.
+type methodNotFoundError struct{ method string }
+
+func (e *methodNotFoundError) ErrorCode() int { return errCodeMethodNotFound }
+
+func (e *methodNotFoundError) Error() string {
+	return "the method " + e.method + " does not exist/is not available"
+}
+
+func TestPassthroughMiddleware(t *testing.T) {
+	oldTable := ethPassthroughTable
+	defer func() { ethPassthroughTable = oldTable }()
+
+	table, err := node.NewRoutingTable(
+		node.RoutingConfig{Passthrough: []string{"txpool"}},
+		nil, node.GroupEthHttp, map[node.Group]node.UrlConfig{node.GroupEthHttp: {}},
+	)
+	require.NoError(t, err)
+	ethPassthroughTable = table
+
+	client := newTestEthNode(t, 16)
+	provider := node.NewEthClientProvider(node.NewLocalRouter(map[node.Group][]string{
+		node.GroupEthHttp: {client.URL},
+	}))
+
+	// none of the methods served by RPC server
+	handler := passthroughMiddleware(func(ctx context.Context, msg *rpc.JsonRpcMessage) *rpc.JsonRpcMessage {
+		return msg.ErrorResponse(&methodNotFoundError{msg.Method})
+	})
+
+	call := func(method string) *rpc.JsonRpcMessage {
+		ctx := context.WithValue(context.Background(), ctxKeyClientProvider, provider)
+		ctx = context.WithValue(ctx, ctxKeyClient, client)
+
+		return handler(ctx, &rpc.JsonRpcMessage{Version: "2.0", ID: []byte("1"), Method: method})
+	}
+
+	// forwarded to fullnode for configured passthrough namespace
+	resp := call("txpool_status")
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, `"0x10"`, string(resp.Result))
+
+	// method not found for unconfigured namespace
+	resp = call("debug_traceTransaction")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, errCodeMethodNotFound, resp.Error.ErrorCode())
+}
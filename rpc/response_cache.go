@@ -0,0 +1,232 @@
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/openweb3/go-rpc-provider"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/rpc/cache"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/util/pubsub"
+	"github.com/sirupsen/logrus"
+)
+
+// ethResponseCache caches responses of immutable historical queries for evm space, nil if disabled.
+var ethResponseCache *cache.ResponseCache
+
+// ethCacheBlock is the block on which a RPC response depends.
+type ethCacheBlock struct {
+	number uint64
+	byHash bool // immutable if queried by block hash
+}
+
+// ethCacheRule resolves the block on which the RPC response depends, or returns false if
+// the response is not cacheable.
+type ethCacheRule func(params []json.RawMessage, result json.RawMessage) (ethCacheBlock, bool)
+
+// ethCacheableMethods are evm space RPC methods whose responses could be cached once the
+// queried block is immutable.
+var ethCacheableMethods = map[string]ethCacheRule{
+	"eth_call":                ethCacheRuleBlockParam(1),
+	"eth_getBalance":          ethCacheRuleBlockParam(1),
+	"eth_getCode":             ethCacheRuleBlockParam(1),
+	"eth_getStorageAt":        ethCacheRuleBlockParam(2),
+	"eth_getTransactionCount": ethCacheRuleBlockParam(1),
+	"trace_transaction":       ethCacheRuleTraceResult,
+}
+
+// ethCacheRuleBlockParam resolves the block from the block number or hash parameter at the
+// specified index, and block tags (e.g. `latest`) are not cacheable.
+func ethCacheRuleBlockParam(index int) ethCacheRule {
+	return func(params []json.RawMessage, result json.RawMessage) (ethCacheBlock, bool) {
+		if index >= len(params) {
+			return ethCacheBlock{}, false
+		}
+
+		var bnh rpc.BlockNumberOrHash
+		if err := json.Unmarshal(params[index], &bnh); err != nil {
+			return ethCacheBlock{}, false
+		}
+
+		if _, ok := bnh.Hash(); ok {
+			return ethCacheBlock{byHash: true}, true
+		}
+
+		if bn, ok := bnh.Number(); ok && bn >= 0 {
+			return ethCacheBlock{number: uint64(bn)}, true
+		}
+
+		return ethCacheBlock{}, false
+	}
+}
+
+// ethCacheRuleTraceResult resolves the block from the traces of transaction.
+func ethCacheRuleTraceResult(params []json.RawMessage, result json.RawMessage) (ethCacheBlock, bool) {
+	var traces []struct {
+		BlockNumber uint64 `json:"blockNumber"`
+	}
+
+	if err := json.Unmarshal(result, &traces); err != nil || len(traces) == 0 {
+		return ethCacheBlock{}, false
+	}
+
+	return ethCacheBlock{number: traces[0].BlockNumber}, true
+}
+
+// ethResponseCacheMiddleware serves evm space RPC responses from cache if hit, otherwise caches
+// the responses of immutable historical queries.
+func ethResponseCacheMiddleware(next rpc.HandleCallMsgFunc) rpc.HandleCallMsgFunc {
+	return func(ctx context.Context, msg *rpc.JsonRpcMessage) *rpc.JsonRpcMessage {
+		rule, ok := ethCacheableMethods[msg.Method]
+		if !ok {
+			return next(ctx, msg)
+		}
+
+		// core space also serves some methods in the same namespace, e.g. `trace_transaction`
+		if _, ok := ctx.Value(ctxKeyClientProvider).(*node.EthClientProvider); !ok {
+			return next(ctx, msg)
+		}
+
+		var params []json.RawMessage
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return next(ctx, msg)
+		}
+
+		key, err := ethResponseCacheKey(msg.Method, params)
+		if err != nil {
+			return next(ctx, msg)
+		}
+
+		if result, ok := ethResponseCache.Get(ctx, msg.Method, key); ok {
+			return &rpc.JsonRpcMessage{Version: msg.Version, ID: msg.ID, Result: result}
+		}
+
+		resp := next(ctx, msg)
+		if resp == nil || resp.Error != nil || len(resp.Result) == 0 || string(resp.Result) == "null" {
+			return resp
+		}
+
+		block, ok := rule(params, resp.Result)
+		if !ok {
+			return resp
+		}
+
+		if !block.byHash && !isEthBlockImmutable(ctx, block.number) {
+			return resp
+		}
+
+		ethResponseCache.Set(ctx, msg.Method, key, resp.Result, block.number)
+
+		return resp
+	}
+}
+
+// isEthBlockImmutable checks if the block is deep enough below the latest block of the
+// requested fullnode.
+func isEthBlockImmutable(ctx context.Context, blockNum uint64) bool {
+	w3c, ok := ctx.Value(ctxKeyClient).(*node.Web3goClient)
+	if !ok {
+		return false
+	}
+
+	latestBlock, err := cache.EthDefault.GetBlockNumber(w3c)
+	if err != nil {
+		return false
+	}
+
+	return blockNum+ethResponseCache.Confirmations() <= latestBlock.ToInt().Uint64()
+}
+
+// ethResponseCacheKey canonicalises the RPC params, so that semantically equal requests
+// (e.g. in different letter case or whitespace) share the same cache key.
+func ethResponseCacheKey(method string, params []json.RawMessage) (string, error) {
+	canonical := make([]interface{}, len(params))
+
+	for i := range params {
+		var val interface{}
+		if err := json.Unmarshal(params[i], &val); err != nil {
+			return "", err
+		}
+
+		canonical[i] = canonicaliseJsonValue(val)
+	}
+
+	// keys of map are sorted when marshaled
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+
+	return method + ":" + hex.EncodeToString(hash[:]), nil
+}
+
+// canonicaliseJsonValue lowercases all the string values, e.g. hex addresses and block tags.
+func canonicaliseJsonValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case string:
+		return strings.ToLower(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = canonicaliseJsonValue(v[i])
+		}
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = canonicaliseJsonValue(v[k])
+		}
+	}
+
+	return val
+}
+
+// MustInitEthResponseCache initializes the evm space response cache if enabled, which loads the
+// reorg version from db store and subscribes chain reorg from broker to invalidate cache.
+func MustInitEthResponseCache(ctx context.Context, db store.DBStore, broker pubsub.Broker) {
+	if ethResponseCache == nil {
+		return
+	}
+
+	if db != nil {
+		reorgVersion, err := db.GetReorgVersion()
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to get reorg version to initialize response cache")
+		}
+
+		ethResponseCache.SetGeneration(int64(reorgVersion))
+	}
+
+	if broker == nil {
+		logrus.Warn("Response cache won't be invalidated on chain reorg without pub/sub broker")
+		return
+	}
+
+	sub := broker.Subscribe(pubsub.TopicEthReorg)
+
+	go func() {
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-sub.Chan():
+				if !ok {
+					return
+				}
+
+				var msg pubsub.ReorgMessage
+				if err := json.Unmarshal(data, &msg); err != nil {
+					logrus.WithError(err).Error("Failed to unmarshal chain reorg message")
+					continue
+				}
+
+				ethResponseCache.Invalidate(msg.RevertTo, int64(msg.ReorgVersion))
+			}
+		}
+	}()
+}
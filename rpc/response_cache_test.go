@@ -0,0 +1,54 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthResponseCacheKey(t *testing.T) {
+	var params1, params2 []json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(`["0xABcd", {"blockHash": "0xEF"}]`), &params1))
+	require.NoError(t, json.Unmarshal([]byte(`[ "0xabcd",{ "blockHash":"0xef" } ]`), &params2))
+
+	key1, err := ethResponseCacheKey("eth_getBalance", params1)
+	require.NoError(t, err)
+
+	key2, err := ethResponseCacheKey("eth_getBalance", params2)
+	require.NoError(t, err)
+	assert.Equal(t, key1, key2)
+
+	key3, err := ethResponseCacheKey("eth_getCode", params2)
+	require.NoError(t, err)
+	assert.NotEqual(t, key1, key3)
+}
+
+func TestEthCacheRuleBlockParam(t *testing.T) {
+	rule := ethCacheRuleBlockParam(1)
+
+	testCases := []struct {
+		params   string
+		ok       bool
+		expected ethCacheBlock
+	}{
+		{`["0xabcd"]`, false, ethCacheBlock{}},
+		{`["0xabcd", "latest"]`, false, ethCacheBlock{}},
+		{`["0xabcd", "0x10"]`, true, ethCacheBlock{number: 16}},
+		{`["0xabcd", {"blockNumber": "0x10"}]`, true, ethCacheBlock{number: 16}},
+		{
+			`["0xabcd", {"blockHash": "0x0000000000000000000000000000000000000000000000000000000000000001"}]`,
+			true, ethCacheBlock{byHash: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		var params []json.RawMessage
+		require.NoError(t, json.Unmarshal([]byte(tc.params), &params))
+
+		block, ok := rule(params, nil)
+		assert.Equal(t, tc.ok, ok, tc.params)
+		assert.Equal(t, tc.expected, block, tc.params)
+	}
+}
@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"context"
+
 	infuraNode "github.com/scroll-tech/rpc-gateway/node"
 	"github.com/scroll-tech/rpc-gateway/rpc/handler"
 	"github.com/scroll-tech/rpc-gateway/util/rate"
@@ -39,15 +41,16 @@ func MustNewNativeSpaceServer(
 	return rpc.MustNewServer(nativeSpaceRpcServerName, exposedApis, middleware)
 }
 
-// MustNewEvmSpaceServer new evm space RPC server by specifying router, and exposed modules.
-// `exposedModules` is a list of API modules to expose via the RPC interface. If the module
-// list is empty, all RPC API endpoints designated public will be exposed.
+// MustNewEvmSpaceServer new evm space RPC server by specifying client provider, and exposed
+// modules. `exposedModules` is a list of API modules to expose via the RPC interface. If the
+// module list is empty, all RPC API endpoints designated public will be exposed. Background
+// tasks of the server, e.g. polling txpool, stop once the context is done.
 func MustNewEvmSpaceServer(
-	router infuraNode.Router, exposedModules []string, option ...EthAPIOption,
+	ctx context.Context, clientProvider *infuraNode.EthClientProvider,
+	exposedModules []string, option ...EthAPIOption,
 ) *rpc.Server {
 	// retrieve all available evm space rpc apis
-	clientProvider := infuraNode.NewEthClientProvider(router)
-	allApis, err := evmSpaceApis(clientProvider, option...)
+	allApis, err := evmSpaceApis(ctx, clientProvider, option...)
 	if err != nil {
 		logrus.WithError(err).Fatal("Failed to new EVM space RPC server")
 	}
@@ -6,12 +6,18 @@ import (
 
 	sdk "github.com/Conflux-Chain/go-conflux-sdk"
 	"github.com/openweb3/go-rpc-provider"
+	"github.com/openweb3/go-rpc-provider/interfaces"
+	"github.com/openweb3/web3go"
 	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/rpc/cache"
 	"github.com/scroll-tech/rpc-gateway/util/rate"
+	rpcutil "github.com/scroll-tech/rpc-gateway/util/rpc"
 	"github.com/scroll-tech/rpc-gateway/util/rpc/handlers"
 	"github.com/scroll-tech/rpc-gateway/util/rpc/middlewares"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -27,6 +33,9 @@ func init() {
 	// panic recovery
 	rpc.HookHandleCallMsg(middlewares.Recover)
 
+	// tracing
+	rpc.HookHandleCallMsg(middlewares.Tracing)
+
 	// web3pay billing
 	if web3payClient, ok := middlewares.MustNewWeb3PayClient(); ok {
 		logrus.Info("Web3Pay billing RPC middleware enabled")
@@ -48,15 +57,44 @@ func init() {
 	// cfx/eth client
 	rpc.HookHandleCallMsg(clientMiddleware)
 
-	// invalid json rpc request without `ID``
+	// invalid json rpc request without `ID``, which must be rejected before answered from cache
+	// or forwarded to any fullnode by the following middlewares
 	rpc.HookHandleCallMsg(rpc.PreventMessagesWithouID)
+
+	// evm space `safe` and `finalized` block tags
+	rpc.HookHandleCallMsg(ethBlockTagMiddleware)
+
+	// evm space monotonic chain head per client session
+	if sessions, ok := mustNewEthSessionsFromViper(); ok {
+		logrus.Info("EVM space session consistency RPC middleware enabled")
+		ethSessionStore = sessions
+		rpc.HookHandleCallMsg(ethSessionMiddleware)
+	}
+
+	// evm space archive routing for historical state queries
+	if config, ok := mustNewEthArchiveConfigFromViper(); ok {
+		logrus.Info("EVM space archive routing RPC middleware enabled")
+		ethArchiveConf = config
+		rpc.HookHandleCallMsg(ethArchiveMiddleware)
+	}
+
+	// forward unknown methods of passthrough namespaces to fullnode
+	rpc.HookHandleCallMsg(passthroughMiddleware)
+
+	// evm space response cache
+	if rc, ok := cache.MustNewEthResponseCacheFromViper(); ok {
+		logrus.Info("EVM space response cache RPC middleware enabled")
+		ethResponseCache = rc
+		rpc.HookHandleCallMsg(ethResponseCacheMiddleware)
+	}
 }
 
 // Inject values into context for static RPC call middlewares, e.g. rate limit
 func httpMiddleware(registry *rate.Registry, clientProvider interface{}) handlers.Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ctx := r.Context()
+			// continue the trace of remote caller if any
+			ctx := tracing.ExtractHTTPHeader(r.Context(), r.Header)
 
 			if token := handlers.GetAccessToken(r); len(token) > 0 { // optional
 				ctx = context.WithValue(ctx, handlers.CtxAccessToken, token)
@@ -78,27 +116,17 @@ func clientMiddleware(next rpc.HandleCallMsgFunc) rpc.HandleCallMsgFunc {
 		var err error
 
 		if cfxProvider, ok := ctx.Value(ctxKeyClientProvider).(*node.CfxClientProvider); ok {
-			switch msg.Method {
-			case "cfx_getLogs":
-				client, err = cfxProvider.GetClientByIPGroup(ctx, node.GroupCfxLogs)
-			default:
-				client, err = cfxProvider.GetClientByIP(ctx)
-			}
+			group := node.CfxRoutingTable().Route(msg.Method)
+			client, err = cfxProvider.GetClientByIPGroup(ctx, group)
 		} else if ethProvider, ok := ctx.Value(ctxKeyClientProvider).(*node.EthClientProvider); ok {
-			if (loadBalancerMode == "consistentHashing") {
-				switch msg.Method {
-				case "eth_getLogs":
-					client, err = ethProvider.GetClientByIPGroup(ctx, node.GroupEthLogs)
-				default:
-					client, err = ethProvider.GetClientByIP(ctx)
-				}
-			} else {
-				switch msg.Method{
-				case "eth_getLogs":
-					client, err = ethProvider.GetClientRandomByGroup(node.GroupEthLogs)
-				default:
-					client, err = ethProvider.GetClientRandom()
-				}
+			group := node.EthRoutingTable().Route(msg.Method)
+			switch loadBalancerMode {
+			case "consistentHashing":
+				client, err = ethProvider.GetClientByIPGroup(ctx, group)
+			case "leastLoad":
+				client, err = ethProvider.GetClientByLoad(group)
+			default:
+				client, err = ethProvider.GetClientRandomByGroup(group)
 			}
 		} else {
 			return next(ctx, msg)
@@ -109,9 +137,67 @@ func clientMiddleware(next rpc.HandleCallMsgFunc) rpc.HandleCallMsgFunc {
 			return msg.ErrorResponse(err)
 		}
 
-		ctx = context.WithValue(ctx, ctxKeyClient, client)
+		return next(withClient(ctx, client), msg)
+	}
+}
+
+// withClient injects the cfx/eth client into context for the following RPC call middlewares
+// and handlers, which replaces any client injected before, e.g. to reroute to archive fullnode.
+func withClient(ctx context.Context, client interface{}) context.Context {
+	// trace fullnode calls of the typed client under request span
+	if w3c, ok := client.(*node.Web3goClient); ok {
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			client = ethClientWithSpan(w3c, span)
+		}
+	}
+
+	ctx = context.WithValue(ctx, ctxKeyClient, client)
+	tracing.SetAttributes(ctx, tracing.AttrNodeName.String(clientNodeName(client)))
+
+	return ctx
+}
+
+// ethClientWithSpan wraps the evm space client to attach the specified span to upstream calls,
+// since typed client methods always request with background context. Note, the request context
+// is not used, so that upstream calls, e.g. sending transaction, won't be canceled by caller.
+func ethClientWithSpan(client *node.Web3goClient, span trace.Span) *node.Web3goClient {
+	provider := &spanProvider{Provider: client.Provider(), span: span}
+	return &node.Web3goClient{Client: web3go.NewClientWithProvider(provider), URL: client.URL}
+}
+
+// spanProvider attaches span to the upstream calls without span.
+type spanProvider struct {
+	interfaces.Provider
+	span trace.Span
+}
+
+func (p *spanProvider) withSpan(ctx context.Context) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	return trace.ContextWithSpan(ctx, p.span)
+}
+
+func (p *spanProvider) CallContext(
+	ctx context.Context, result interface{}, method string, args ...interface{},
+) error {
+	return p.Provider.CallContext(p.withSpan(ctx), result, method, args...)
+}
+
+func (p *spanProvider) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return p.Provider.BatchCallContext(p.withSpan(ctx), b)
+}
 
-		return next(ctx, msg)
+// clientNodeName returns the name of fullnode that the cfx/eth client connects to.
+func clientNodeName(client interface{}) string {
+	switch c := client.(type) {
+	case sdk.ClientOperator:
+		return rpcutil.Url2NodeName(c.GetNodeURL())
+	case *node.Web3goClient:
+		return rpcutil.Url2NodeName(c.URL)
+	default:
+		return ""
 	}
 }
 
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	providers "github.com/openweb3/go-rpc-provider/provider_wrapper"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEthClientWithSpan(t *testing.T) {
+	client := newTestEthNode(t, 16)
+
+	var traced trace.SpanContext
+	client.Provider().HookCallContext(func(handler providers.CallContextFunc) providers.CallContextFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			traced = trace.SpanContextFromContext(ctx)
+			assert.NoError(t, ctx.Err())
+
+			return handler(ctx, result, method, args...)
+		}
+	})
+
+	tracer := sdktrace.NewTracerProvider().Tracer("test")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, span := tracer.Start(ctx, "eth_blockNumber")
+	defer span.End()
+
+	// upstream calls not canceled along with request, e.g. client disconnected
+	cancel()
+
+	blockNum, err := ethClientWithSpan(client, span).Eth.BlockNumber()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(16), blockNum.Uint64())
+
+	// typed client calls traced under request span
+	assert.Equal(t, span.SpanContext(), traced)
+}
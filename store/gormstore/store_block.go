@@ -1,4 +1,4 @@
-package mysql
+package gormstore
 
 import (
 	"context"
@@ -16,14 +16,14 @@ const (
 
 type block struct {
 	ID          uint64
-	Epoch       uint64 `gorm:"not null;index"`
-	BlockNumber uint64 `gorm:"not null;index"`
-	HashId      uint64 `gorm:"not null;index"`
-	Hash        string `gorm:"size:66;not null"`
-	Pivot       bool   `gorm:"not null"`
-	RawData     []byte `gorm:"type:MEDIUMBLOB;not null"`
-	RawDataLen  uint64 `gorm:"not null"`
-	Extra       []byte `gorm:"type:text"` // extention json field
+	Epoch       uint64     `gorm:"not null;index"`
+	BlockNumber uint64     `gorm:"not null;index"`
+	HashId      hashId     `gorm:"not null;index"`
+	Hash        string     `gorm:"size:66;not null"`
+	Pivot       bool       `gorm:"not null"`
+	RawData     mediumBlob `gorm:"not null"`
+	RawDataLen  uint64     `gorm:"not null"`
+	Extra       jsonText   // extention json field
 }
 
 func newBlock(data *types.Block, pivot bool, extra *store.BlockExtra) *block {
@@ -35,7 +35,7 @@ func newBlock(data *types.Block, pivot bool, extra *store.BlockExtra) *block {
 		RawData:     util.MustMarshalRLP(util.GetSummaryOfBlock(data)),
 	}
 
-	block.HashId = util.GetShortIdOfHash(block.Hash)
+	block.HashId = newHashId(block.Hash)
 	block.RawDataLen = uint64(len(block.RawData))
 
 	if extra != nil {
@@ -67,17 +67,17 @@ func (block *block) parseBlockExtra() *store.BlockExtra {
 	return nil
 }
 
-type blockStore struct {
+type BlockStore struct {
 	db *gorm.DB
 }
 
-func newBlockStore(db *gorm.DB) *blockStore {
-	return &blockStore{
+func NewBlockStore(db *gorm.DB) *BlockStore {
+	return &BlockStore{
 		db: db,
 	}
 }
 
-func (bs *blockStore) loadBlockSummary(whereClause string, args ...interface{}) (*store.BlockSummary, error) {
+func (bs *BlockStore) loadBlockSummary(whereClause string, args ...interface{}) (*store.BlockSummary, error) {
 	var blk block
 	if err := bs.db.Where(whereClause, args...).First(&blk).Error; err != nil {
 		return nil, err
@@ -91,7 +91,7 @@ func (bs *blockStore) loadBlockSummary(whereClause string, args ...interface{})
 	}, nil
 }
 
-func (bs *blockStore) GetBlocksByEpoch(ctx context.Context, epochNumber uint64) ([]types.Hash, error) {
+func (bs *BlockStore) GetBlocksByEpoch(ctx context.Context, epochNumber uint64) ([]types.Hash, error) {
 	rows, err := bs.db.Raw("SELECT hash FROM blocks WHERE epoch = ?", epochNumber).Rows()
 	if err != nil {
 		return nil, err
@@ -117,34 +117,34 @@ func (bs *blockStore) GetBlocksByEpoch(ctx context.Context, epochNumber uint64)
 	return result, nil
 }
 
-func (bs *blockStore) GetBlockByEpoch(ctx context.Context, epochNumber uint64) (*store.Block, error) {
+func (bs *BlockStore) GetBlockByEpoch(ctx context.Context, epochNumber uint64) (*store.Block, error) {
 	// TODO Cannot get txn from db in advance, since only executed transactions saved in db
 	return nil, store.ErrUnsupported
 }
 
-func (bs *blockStore) GetBlockSummaryByEpoch(ctx context.Context, epochNumber uint64) (*store.BlockSummary, error) {
+func (bs *BlockStore) GetBlockSummaryByEpoch(ctx context.Context, epochNumber uint64) (*store.BlockSummary, error) {
 	return bs.loadBlockSummary("epoch = ? AND pivot = true", epochNumber)
 }
 
-func (bs *blockStore) GetBlockByHash(ctx context.Context, blockHash types.Hash) (*store.Block, error) {
+func (bs *BlockStore) GetBlockByHash(ctx context.Context, blockHash types.Hash) (*store.Block, error) {
 	return nil, store.ErrUnsupported
 }
 
-func (bs *blockStore) GetBlockSummaryByHash(ctx context.Context, blockHash types.Hash) (*store.BlockSummary, error) {
+func (bs *BlockStore) GetBlockSummaryByHash(ctx context.Context, blockHash types.Hash) (*store.BlockSummary, error) {
 	hash := blockHash.String()
-	return bs.loadBlockSummary("hash_id = ? AND hash = ?", util.GetShortIdOfHash(hash), hash)
+	return bs.loadBlockSummary("hash_id = ? AND hash = ?", newHashId(hash), hash)
 }
 
-func (bs *blockStore) GetBlockByBlockNumber(ctx context.Context, blockNumber uint64) (*store.Block, error) {
+func (bs *BlockStore) GetBlockByBlockNumber(ctx context.Context, blockNumber uint64) (*store.Block, error) {
 	return nil, store.ErrUnsupported
 }
 
-func (bs *blockStore) GetBlockSummaryByBlockNumber(ctx context.Context, blockNumber uint64) (*store.BlockSummary, error) {
+func (bs *BlockStore) GetBlockSummaryByBlockNumber(ctx context.Context, blockNumber uint64) (*store.BlockSummary, error) {
 	return bs.loadBlockSummary("block_number = ?", blockNumber)
 }
 
 // Add batch save epoch blocks into db store.
-func (bs *blockStore) Add(dbTx *gorm.DB, dataSlice []*store.EpochData) error {
+func (bs *BlockStore) Add(dbTx *gorm.DB, dataSlice []*store.EpochData) error {
 	var blocks []*block
 
 	for _, data := range dataSlice {
@@ -168,6 +168,6 @@ func (bs *blockStore) Add(dbTx *gorm.DB, dataSlice []*store.EpochData) error {
 }
 
 // Remove remove blocks of specific epoch range from db store.
-func (bs *blockStore) Remove(dbTx *gorm.DB, epochFrom, epochTo uint64) error {
+func (bs *BlockStore) Remove(dbTx *gorm.DB, epochFrom, epochTo uint64) error {
 	return dbTx.Where("epoch >= ? AND epoch <= ?", epochFrom, epochTo).Delete(&block{}).Error
 }
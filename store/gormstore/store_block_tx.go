@@ -0,0 +1,104 @@
+package gormstore
+
+import (
+	"context"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"gorm.io/gorm"
+)
+
+// BlockTxStore serves transactions and receipts of block, which are loaded in order of the
+// transaction hashes within the block summary, since the transaction index is not persisted.
+type BlockTxStore struct {
+	*BlockStore
+	*TxStore
+}
+
+func NewBlockTxStore(db *gorm.DB) *BlockTxStore {
+	return &BlockTxStore{
+		BlockStore: NewBlockStore(db),
+		TxStore:    NewTxStore(db),
+	}
+}
+
+// GetBlockTransactionCountByHash returns the number of transactions of the specified block.
+func (bts *BlockTxStore) GetBlockTransactionCountByHash(ctx context.Context, blockHash types.Hash) (uint64, error) {
+	summary, err := bts.GetBlockSummaryByHash(ctx, blockHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(summary.CfxBlockSummary.Transactions)), nil
+}
+
+// GetBlockTransactionCountByNumber returns the number of transactions of the specified block.
+func (bts *BlockTxStore) GetBlockTransactionCountByNumber(ctx context.Context, blockNumber uint64) (uint64, error) {
+	summary, err := bts.GetBlockSummaryByBlockNumber(ctx, blockNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(summary.CfxBlockSummary.Transactions)), nil
+}
+
+// GetTransactionByBlockHashAndIndex returns the transaction at the specified index of block,
+// or nil if index out of range.
+func (bts *BlockTxStore) GetTransactionByBlockHashAndIndex(
+	ctx context.Context, blockHash types.Hash, index uint32,
+) (*store.Transaction, error) {
+	summary, err := bts.GetBlockSummaryByHash(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return bts.getTransactionByIndex(ctx, summary, index)
+}
+
+// GetTransactionByBlockNumberAndIndex returns the transaction at the specified index of block,
+// or nil if index out of range.
+func (bts *BlockTxStore) GetTransactionByBlockNumberAndIndex(
+	ctx context.Context, blockNumber uint64, index uint32,
+) (*store.Transaction, error) {
+	summary, err := bts.GetBlockSummaryByBlockNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return bts.getTransactionByIndex(ctx, summary, index)
+}
+
+func (bts *BlockTxStore) getTransactionByIndex(
+	ctx context.Context, summary *store.BlockSummary, index uint32,
+) (*store.Transaction, error) {
+	txHashes := summary.CfxBlockSummary.Transactions
+	if int(index) >= len(txHashes) {
+		return nil, nil
+	}
+
+	return bts.GetTransaction(ctx, txHashes[index])
+}
+
+// GetReceiptsByBlockHash returns all the transaction receipts of the specified block.
+func (bts *BlockTxStore) GetReceiptsByBlockHash(
+	ctx context.Context, blockHash types.Hash,
+) ([]*store.TransactionReceipt, error) {
+	summary, err := bts.GetBlockSummaryByHash(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return bts.GetReceipts(summary.CfxBlockSummary.Transactions)
+}
+
+// GetReceiptsByBlockNumber returns all the transaction receipts of the specified block.
+func (bts *BlockTxStore) GetReceiptsByBlockNumber(
+	ctx context.Context, blockNumber uint64,
+) ([]*store.TransactionReceipt, error) {
+	summary, err := bts.GetBlockSummaryByBlockNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return bts.GetReceipts(summary.CfxBlockSummary.Transactions)
+}
@@ -0,0 +1,155 @@
+// Package gormstore provides the dialect neutral stores shared by the relational database backends,
+// e.g. MySQL, PostgreSQL and SQLite, while the partitioned tables and dialect specific queries are
+// implemented by each backend.
+package gormstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Models returns the table models of the shared stores, which are created by backends along with
+// their own tables.
+func Models() []interface{} {
+	return []interface{}{
+		&transaction{},
+		&block{},
+		&conf{},
+		&RateLimit{},
+		&User{},
+		&TxSubmission{},
+		&Contract{},
+	}
+}
+
+// BaseStore provides basic store common operatition.
+type BaseStore struct {
+	db *gorm.DB
+}
+
+func NewBaseStore(db *gorm.DB) *BaseStore {
+	return &BaseStore{db}
+}
+
+func (BaseStore) IsRecordNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, store.ErrNotFound)
+}
+
+func (bs *BaseStore) Close() error {
+	if sqlDb, err := bs.db.DB(); err != nil {
+		return err
+	} else {
+		return sqlDb.Close()
+	}
+}
+
+// Exists returns whether any record matches the specified query.
+func (bs *BaseStore) Exists(modelPtr interface{}, whereQuery string, args ...interface{}) (bool, error) {
+	err := bs.db.Where(whereQuery, args...).First(modelPtr).Error
+	if err == nil {
+		return true, nil
+	}
+
+	if bs.IsRecordNotFound(err) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// isMysql returns whether the dialect of db is MySQL.
+func isMysql(db *gorm.DB) bool {
+	return db.Dialector.Name() == "mysql"
+}
+
+// hashId is the short id of hash, since number is better than long string as an index. It is
+// persisted as unsigned integer in MySQL, but reinterpreted as signed integer in databases that
+// do not support unsigned 64 bits integer, e.g. PostgreSQL and SQLite.
+type hashId uint64
+
+func newHashId(hash string) hashId {
+	return hashId(util.GetShortIdOfHash(hash))
+}
+
+// GormValue implements the `gorm.Valuer` interface.
+func (id hashId) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if isMysql(db) {
+		return clause.Expr{SQL: "?", Vars: []interface{}{uint64(id)}}
+	}
+
+	return clause.Expr{SQL: "?", Vars: []interface{}{int64(id)}}
+}
+
+// Scan implements the `sql.Scanner` interface.
+func (id *hashId) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*id = hashId(v)
+	case uint64:
+		*id = hashId(v)
+	case []byte:
+		return id.parse(string(v))
+	case string:
+		return id.parse(v)
+	default:
+		return errors.New("unsupported hash id type")
+	}
+
+	return nil
+}
+
+func (id *hashId) parse(s string) error {
+	if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+		*id = hashId(v)
+		return nil
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	*id = hashId(v)
+
+	return err
+}
+
+// mediumBlob is binary data persisted as MEDIUMBLOB in MySQL, or the default binary type of other
+// databases, e.g. BYTEA in PostgreSQL.
+type mediumBlob []byte
+
+// GormDBDataType implements the `migrator.GormDataTypeInterface` interface.
+func (mediumBlob) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if isMysql(db) {
+		return "MEDIUMBLOB"
+	}
+
+	return ""
+}
+
+// Value implements the `driver.Valuer` interface.
+func (b mediumBlob) Value() (driver.Value, error) {
+	return []byte(b), nil
+}
+
+// jsonText is the json extension field persisted as TEXT in MySQL, or the default binary type of
+// other databases, e.g. BYTEA in PostgreSQL.
+type jsonText []byte
+
+// GormDBDataType implements the `migrator.GormDataTypeInterface` interface.
+func (jsonText) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if isMysql(db) {
+		return "TEXT"
+	}
+
+	return ""
+}
+
+// Value implements the `driver.Valuer` interface.
+func (t jsonText) Value() (driver.Value, error) {
+	return []byte(t), nil
+}
@@ -1,4 +1,4 @@
-package mysql
+package gormstore
 
 import (
 	"crypto/md5"
@@ -14,7 +14,7 @@ import (
 )
 
 const (
-	MysqlConfKeyReorgVersion = "reorg.version"
+	ConfKeyReorgVersion = "reorg.version"
 
 	rateLimitConfigStrategyPrefix    = "ratelimit.strategy."
 	rateLimitStrategySqlMatchPattern = rateLimitConfigStrategyPrefix + "%"
@@ -33,17 +33,17 @@ func (conf) TableName() string {
 	return "configs"
 }
 
-type confStore struct {
-	*baseStore
+type ConfStore struct {
+	*BaseStore
 }
 
-func newConfStore(db *gorm.DB) *confStore {
-	return &confStore{
-		baseStore: newBaseStore(db),
+func NewConfStore(db *gorm.DB) *ConfStore {
+	return &ConfStore{
+		BaseStore: NewBaseStore(db),
 	}
 }
 
-func (cs *confStore) LoadConfig(confNames ...string) (map[string]interface{}, error) {
+func (cs *ConfStore) LoadConfig(confNames ...string) (map[string]interface{}, error) {
 	var confs []conf
 
 	if err := cs.db.Where("name IN ?", confNames).Find(&confs).Error; err != nil {
@@ -58,7 +58,7 @@ func (cs *confStore) LoadConfig(confNames ...string) (map[string]interface{}, er
 	return res, nil
 }
 
-func (cs *confStore) StoreConfig(confName string, confVal interface{}) error {
+func (cs *ConfStore) StoreConfig(confName string, confVal interface{}) error {
 	return cs.db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "name"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{"value": confVal}),
@@ -68,9 +68,9 @@ func (cs *confStore) StoreConfig(confName string, confVal interface{}) error {
 	}).Error
 }
 
-func (cs *confStore) GetReorgVersion() (int, error) {
+func (cs *ConfStore) GetReorgVersion() (int, error) {
 	var result conf
-	exists, err := cs.exists(&result, "name = ?", MysqlConfKeyReorgVersion)
+	exists, err := cs.Exists(&result, "name = ?", ConfKeyReorgVersion)
 	if err != nil {
 		return 0, err
 	}
@@ -82,21 +82,24 @@ func (cs *confStore) GetReorgVersion() (int, error) {
 	return strconv.Atoi(result.Value)
 }
 
-// thread unsafe
-func (cs *confStore) createOrUpdateReorgVersion(dbTx *gorm.DB) error {
-	version, err := cs.GetReorgVersion()
+// CreateOrUpdateReorgVersion increases the reorg version within the specified db transaction.
+// Note, this method is thread unsafe.
+func (cs *ConfStore) CreateOrUpdateReorgVersion(dbTx *gorm.DB) error {
+	txStore := NewConfStore(dbTx)
+
+	version, err := txStore.GetReorgVersion()
 	if err != nil {
 		return err
 	}
 
 	newVersion := strconv.Itoa(version + 1)
 
-	return cs.StoreConfig(MysqlConfKeyReorgVersion, newVersion)
+	return txStore.StoreConfig(ConfKeyReorgVersion, newVersion)
 }
 
 // ratelimit config
 
-func (cs *confStore) LoadRateLimitConfigs() *rate.Config {
+func (cs *ConfStore) LoadRateLimitConfigs() *rate.Config {
 	var cfgs []conf
 	if err := cs.db.Where("name LIKE ?", rateLimitStrategySqlMatchPattern).Find(&cfgs).Error; err != nil {
 		logrus.WithError(err).Error("Failed to load rate limit config from db")
@@ -125,7 +128,7 @@ func (cs *confStore) LoadRateLimitConfigs() *rate.Config {
 	return &rate.Config{Strategies: strategies}
 }
 
-func (cs *confStore) loadRateLimitStrategy(cfg conf) (*rate.Strategy, error) {
+func (cs *ConfStore) loadRateLimitStrategy(cfg conf) (*rate.Strategy, error) {
 	// eg., ratelimit.strategy.whitelist
 	name := cfg.Name[len(rateLimitConfigStrategyPrefix):]
 	if len(name) == 0 {
@@ -1,4 +1,4 @@
-package mysql
+package gormstore
 
 import (
 	lru "github.com/hashicorp/golang-lru"
@@ -25,7 +25,7 @@ func (Contract) TableName() string {
 }
 
 type ContractStore struct {
-	*baseStore
+	*BaseStore
 
 	// generally, active contracts are limited
 	cacheById      *lru.Cache // id => contract
@@ -42,7 +42,7 @@ func NewContractStore(db *gorm.DB, cacheSize ...int) *ContractStore {
 	cacheByAddress, _ := lru.New(size)
 
 	return &ContractStore{
-		baseStore:      newBaseStore(db),
+		BaseStore:      NewBaseStore(db),
 		cacheById:      cacheById,
 		cacheByAddress: cacheByAddress,
 	}
@@ -158,7 +158,7 @@ func (cs *ContractStore) AddContract(contracts map[string]bool) (int, error) {
 
 // AddContract adds contract for the specified epoch data slice and returns the number of new added contracts.
 func (cs *ContractStore) AddContractByEpochData(slice ...*store.EpochData) (int, error) {
-	contracts := extractUniqueContractAddresses(slice...)
+	contracts := ExtractUniqueContractAddresses(slice...)
 	return cs.AddContract(contracts)
 }
 
@@ -184,7 +184,10 @@ func (cs *ContractStore) UpdateContractStats(
 		if countDelta > 0 { // increment
 			updates["log_count"] = gorm.Expr("log_count + ?", countDelta)
 		} else { // decrement
-			updates["log_count"] = gorm.Expr("GREATEST(0, CAST(log_count AS SIGNED) - ?)", -countDelta)
+			// dialect neutral expression of `GREATEST(0, log_count - ?)`
+			updates["log_count"] = gorm.Expr(
+				"CASE WHEN log_count > ? THEN log_count - ? ELSE 0 END", -countDelta, -countDelta,
+			)
 		}
 	}
 
@@ -197,7 +200,7 @@ func (cs *ContractStore) enforceCache(whereQuery string, args ...interface{}) (*
 	// 1. Use mutex lock to allow only 1 thread to read from db.
 	// 2. Cache non-existent address.
 	var contract Contract
-	exists, err := cs.exists(&contract, whereQuery, args...)
+	exists, err := cs.Exists(&contract, whereQuery, args...)
 
 	if err == nil && exists {
 		cs.updateCache(&contract)
@@ -205,3 +208,19 @@ func (cs *ContractStore) enforceCache(whereQuery string, args ...interface{}) (*
 
 	return &contract, exists, err
 }
+
+// ExtractUniqueContractAddresses extracts unique contract addresses of event logs within epoch data slice.
+func ExtractUniqueContractAddresses(slice ...*store.EpochData) map[string]bool {
+	contracts := make(map[string]bool)
+
+	for _, data := range slice {
+		for _, receipt := range data.Receipts {
+			for i := range receipt.Logs {
+				addr := receipt.Logs[i].Address.String()
+				contracts[addr] = true
+			}
+		}
+	}
+
+	return contracts
+}
@@ -1,4 +1,4 @@
-package mysql
+package gormstore
 
 import (
 	"errors"
@@ -27,12 +27,12 @@ func (RateLimit) TableName() string {
 }
 
 type RateLimitStore struct {
-	*baseStore
+	*BaseStore
 }
 
 func NewRateLimitStore(db *gorm.DB) *RateLimitStore {
 	return &RateLimitStore{
-		baseStore: newBaseStore(db),
+		BaseStore: NewBaseStore(db),
 	}
 }
 
@@ -0,0 +1,72 @@
+package gormstore
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func mustNewTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "confura.db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(Models()...))
+
+	t.Cleanup(func() { NewBaseStore(db).Close() })
+
+	return db
+}
+
+func TestBlockStoreHashIdWithHighBitSet(t *testing.T) {
+	db := mustNewTestDB(t)
+	bs := NewBlockStore(db)
+
+	blockHash := types.Hash("0xff00000000000000000000000000000000000000000000000000000000000001")
+	require.Equal(t, hashId(0xff00000000000000), newHashId(blockHash.String()))
+
+	cfxBlock := types.Block{
+		BlockHeader: types.BlockHeader{
+			Hash:        blockHash,
+			BlockNumber: (*hexutil.Big)(big.NewInt(1)),
+			EpochNumber: (*hexutil.Big)(big.NewInt(1)),
+			Miner:       cfxaddress.MustNewFromHex("0x8b8689c7f3014a4d86e4d1d0daaf74a47f5e0f27", 1),
+		},
+	}
+
+	require.NoError(t, bs.Add(db, []*store.EpochData{{Number: 1, Blocks: []*types.Block{&cfxBlock}}}))
+
+	summary, err := bs.GetBlockSummaryByHash(context.Background(), blockHash)
+	require.NoError(t, err)
+	assert.Equal(t, blockHash, summary.CfxBlockSummary.Hash)
+
+	var blk block
+	require.NoError(t, db.First(&blk).Error)
+	assert.Equal(t, newHashId(blockHash.String()), blk.HashId)
+}
+
+func TestContractStoreUpdateContractStats(t *testing.T) {
+	db := mustNewTestDB(t)
+	cs := NewContractStore(db)
+
+	cid, ok, err := cs.AddContractIfAbsent("cfx:contract")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, cs.UpdateContractStats(db, cid, 3, 10))
+	require.NoError(t, cs.UpdateContractStats(db, cid, -5, 12)) // never decreased below 0
+
+	contract, ok, err := cs.GetContractById(cid)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, 0, contract.LogCount)
+	assert.Equal(t, uint64(12), contract.LatestUpdatedEpoch)
+}
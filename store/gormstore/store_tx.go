@@ -1,4 +1,4 @@
-package mysql
+package gormstore
 
 import (
 	"context"
@@ -16,15 +16,15 @@ const defaultBatchSizeTxnInsert = 500
 type transaction struct {
 	ID                uint64
 	Epoch             uint64 `gorm:"not null;index"`
-	HashId            uint64 `gorm:"not null;index"` // as an index, number is better than long string
+	HashId            hashId `gorm:"not null;index"` // as an index, number is better than long string
 	Hash              string `gorm:"size:66;not null"`
-	TxRawData         []byte `gorm:"type:MEDIUMBLOB"`
+	TxRawData         mediumBlob
 	TxRawDataLen      uint64 `gorm:"not null"`
-	ReceiptRawData    []byte `gorm:"type:MEDIUMBLOB"`
-	ReceiptRawDataLen uint64 `gorm:"not null"`
-	NumReceiptLogs    int    `gorm:"not null"`
-	Extra             []byte `gorm:"type:text"` // txn extention json field
-	ReceiptExtra      []byte `gorm:"type:text"` // receipt extention json field
+	ReceiptRawData    mediumBlob
+	ReceiptRawDataLen uint64   `gorm:"not null"`
+	NumReceiptLogs    int      `gorm:"not null"`
+	Extra             jsonText // txn extention json field
+	ReceiptExtra      jsonText // receipt extention json field
 }
 
 func (transaction) TableName() string {
@@ -48,7 +48,7 @@ func newTx(
 		result.ReceiptRawData = util.MustMarshalRLP(receipt)
 	}
 
-	result.HashId = util.GetShortIdOfHash(result.Hash)
+	result.HashId = newHashId(result.Hash)
 	result.TxRawDataLen = uint64(len(result.TxRawData))
 	result.ReceiptRawDataLen = uint64(len(result.ReceiptRawData))
 	result.NumReceiptLogs = len(receipt.Logs)
@@ -66,6 +66,24 @@ func newTx(
 	return result
 }
 
+func (tx *transaction) parseTx() *store.Transaction {
+	var rpcTx types.Transaction
+	util.MustUnmarshalRLP(tx.TxRawData, &rpcTx)
+
+	return &store.Transaction{
+		CfxTransaction: &rpcTx, Extra: tx.parseTxExtra(),
+	}
+}
+
+func (tx *transaction) parseReceipt() *store.TransactionReceipt {
+	var receipt types.TransactionReceipt
+	util.MustUnmarshalRLP(tx.ReceiptRawData, &receipt)
+
+	return &store.TransactionReceipt{
+		CfxReceipt: &receipt, Extra: tx.parseTxReceiptExtra(),
+	}
+}
+
 func (tx *transaction) parseTxExtra() *store.TransactionExtra {
 	if len(tx.Extra) == 0 {
 		return nil
@@ -91,59 +109,97 @@ func (tx *transaction) parseTxReceiptExtra() *store.ReceiptExtra {
 	return &extra
 }
 
-type txStore struct {
+type TxStore struct {
 	db *gorm.DB
 }
 
-func newTxStore(db *gorm.DB) *txStore {
-	return &txStore{
+func NewTxStore(db *gorm.DB) *TxStore {
+	return &TxStore{
 		db: db,
 	}
 }
 
-func (ts *txStore) loadTx(txHash types.Hash) (*transaction, error) {
-	hashId := util.GetShortIdOfHash(txHash.String())
+func (ts *TxStore) loadTx(txHash types.Hash) (*transaction, error) {
+	id := newHashId(txHash.String())
 
 	var tx transaction
-	if err := ts.db.Where("hash_id = ? AND hash = ?", hashId, txHash).First(&tx).Error; err != nil {
+	if err := ts.db.Where("hash_id = ? AND hash = ?", id, txHash).First(&tx).Error; err != nil {
 		return nil, err
 	}
 
 	return &tx, nil
 }
 
-func (ts *txStore) GetTransaction(ctx context.Context, txHash types.Hash) (*store.Transaction, error) {
+func (ts *TxStore) GetTransaction(ctx context.Context, txHash types.Hash) (*store.Transaction, error) {
 	tx, err := ts.loadTx(txHash)
 	if err != nil {
 		return nil, err
 	}
 
-	var rpcTx types.Transaction
-	util.MustUnmarshalRLP(tx.TxRawData, &rpcTx)
-
-	return &store.Transaction{
-		CfxTransaction: &rpcTx, Extra: tx.parseTxExtra(),
-	}, nil
+	return tx.parseTx(), nil
 }
 
-func (ts *txStore) GetReceipt(ctx context.Context, txHash types.Hash) (*store.TransactionReceipt, error) {
+func (ts *TxStore) GetReceipt(ctx context.Context, txHash types.Hash) (*store.TransactionReceipt, error) {
 	tx, err := ts.loadTx(txHash)
 	if err != nil {
 		return nil, err
 	}
 
-	var receipt types.TransactionReceipt
-	util.MustUnmarshalRLP(tx.ReceiptRawData, &receipt)
+	return tx.parseReceipt(), nil
+}
 
-	ptrRcptExtra := tx.parseTxReceiptExtra()
+// loadTxs loads transactions of the specified hashes in the same order, and returns
+// `gorm.ErrRecordNotFound` if any transaction not found.
+func (ts *TxStore) loadTxs(txHashes []types.Hash) ([]*transaction, error) {
+	if len(txHashes) == 0 {
+		return nil, nil
+	}
 
-	return &store.TransactionReceipt{
-		CfxReceipt: &receipt, Extra: ptrRcptExtra,
-	}, nil
+	hashIds := make([]hashId, 0, len(txHashes))
+	for _, txHash := range txHashes {
+		hashIds = append(hashIds, newHashId(txHash.String()))
+	}
+
+	var txs []*transaction
+	if err := ts.db.Where("hash_id IN ?", hashIds).Find(&txs).Error; err != nil {
+		return nil, err
+	}
+
+	hash2Txs := make(map[string]*transaction, len(txs))
+	for _, tx := range txs {
+		hash2Txs[tx.Hash] = tx
+	}
+
+	result := make([]*transaction, 0, len(txHashes))
+	for _, txHash := range txHashes {
+		tx, ok := hash2Txs[txHash.String()]
+		if !ok { // transaction not executed or not persisted
+			return nil, gorm.ErrRecordNotFound
+		}
+
+		result = append(result, tx)
+	}
+
+	return result, nil
+}
+
+// GetReceipts returns receipts of the specified transactions in the same order.
+func (ts *TxStore) GetReceipts(txHashes []types.Hash) ([]*store.TransactionReceipt, error) {
+	txs, err := ts.loadTxs(txHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := make([]*store.TransactionReceipt, 0, len(txs))
+	for _, tx := range txs {
+		receipts = append(receipts, tx.parseReceipt())
+	}
+
+	return receipts, nil
 }
 
 // Add batch save epoch transactions into db store.
-func (ts *txStore) Add(dbTx *gorm.DB, dataSlice []*store.EpochData, skipTx, skipRcpt bool) error {
+func (ts *TxStore) Add(dbTx *gorm.DB, dataSlice []*store.EpochData, skipTx, skipRcpt bool) error {
 	if skipTx && skipRcpt {
 		return nil
 	}
@@ -194,6 +250,6 @@ func (ts *txStore) Add(dbTx *gorm.DB, dataSlice []*store.EpochData, skipTx, skip
 }
 
 // Remove remove transactions of specific epoch range from db store.
-func (ts *txStore) Remove(dbTx *gorm.DB, epochFrom, epochTo uint64) error {
+func (ts *TxStore) Remove(dbTx *gorm.DB, epochFrom, epochTo uint64) error {
 	return dbTx.Where("epoch >= ? AND epoch <= ?", epochFrom, epochTo).Delete(&transaction{}).Error
 }
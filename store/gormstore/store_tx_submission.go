@@ -0,0 +1,54 @@
+package gormstore
+
+import (
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TxSubmission represents a raw transaction submitted via gateway, which is tracked until mined,
+// dropped or replaced.
+type TxSubmission = store.TxSubmission
+
+type TxSubmissionStore struct {
+	*BaseStore
+}
+
+func NewTxSubmissionStore(db *gorm.DB) *TxSubmissionStore {
+	return &TxSubmissionStore{
+		BaseStore: NewBaseStore(db),
+	}
+}
+
+func (tss *TxSubmissionStore) AddTxSubmission(sub *TxSubmission) error {
+	err := tss.db.Clauses(clause.OnConflict{DoNothing: true}).Create(sub).Error
+	return errors.WithMessage(err, "failed to add tx submission")
+}
+
+func (tss *TxSubmissionStore) UpdateTxSubmission(sub *TxSubmission) error {
+	err := tss.db.Save(sub).Error
+	return errors.WithMessage(err, "failed to update tx submission")
+}
+
+func (tss *TxSubmissionStore) GetTxSubmission(hash string) (*TxSubmission, bool, error) {
+	var sub TxSubmission
+	exists, err := tss.Exists(&sub, "hash = ?", hash)
+	return &sub, exists, err
+}
+
+func (tss *TxSubmissionStore) GetTxSubmissionsByNonce(sender string, nonce uint64) ([]*TxSubmission, error) {
+	var subs []*TxSubmission
+	err := tss.db.Where("sender = ? AND nonce = ?", sender, nonce).Find(&subs).Error
+	return subs, err
+}
+
+func (tss *TxSubmissionStore) GetTrackingTxSubmissions(limit int) ([]*TxSubmission, error) {
+	var subs []*TxSubmission
+	err := tss.db.
+		Where("status IN ?", []store.TxStatus{store.TxStatusSubmitted, store.TxStatusPending}).
+		Order("checked_at ASC").
+		Limit(limit).
+		Find(&subs).Error
+	return subs, err
+}
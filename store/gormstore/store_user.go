@@ -0,0 +1,26 @@
+package gormstore
+
+import (
+	"github.com/scroll-tech/rpc-gateway/store"
+	"gorm.io/gorm"
+)
+
+// User represents a VIP user that provide specific archive node to query historical
+// event logs.
+type User = store.User
+
+type UserStore struct {
+	*BaseStore
+}
+
+func NewUserStore(db *gorm.DB) *UserStore {
+	return &UserStore{
+		BaseStore: NewBaseStore(db),
+	}
+}
+
+func (us *UserStore) GetUserByKey(key string) (*User, bool, error) {
+	var user User
+	exists, err := us.Exists(&user, "api_key = ?", key)
+	return &user, exists, err
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Conflux-Chain/go-conflux-util/viper"
 	gosql "github.com/go-sql-driver/mysql"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -15,16 +16,12 @@ import (
 )
 
 // auto migrating table models
-var allModels = []interface{}{
-	&transaction{},
-	&block{},
-	&conf{},
-	&RateLimit{},
-	&User{},
-	&Contract{},
+var allModels = append(
+	gormstore.Models(),
 	&epochBlockMap{},
 	&bnPartition{},
-}
+	&nodeRecord{},
+)
 
 // Config represents the mysql configurations to open a database instance.
 type Config struct {
@@ -103,7 +100,7 @@ func (config *Config) MustOpenOrCreate(option StoreOption) *MysqlStore {
 			logrus.WithError(err).Fatal("Failed to create tables")
 		}
 
-		ls := NewAddressIndexedLogStore(db, NewContractStore(db), config.AddressIndexedLogPartitions)
+		ls := NewAddressIndexedLogStore(db, gormstore.NewContractStore(db), config.AddressIndexedLogPartitions)
 		if _, err := ls.CreatePartitionedTables(); err != nil {
 			logrus.WithError(err).
 				WithField("partitions", config.AddressIndexedLogPartitions).
@@ -111,6 +108,20 @@ func (config *Config) MustOpenOrCreate(option StoreOption) *MysqlStore {
 		}
 	}
 
+	// node registry table is absent in database created by legacy versions
+	if !newCreated && !db.Migrator().HasTable(&nodeRecord{}) {
+		if err := db.Migrator().CreateTable(&nodeRecord{}); err != nil {
+			logrus.WithError(err).Fatal("Failed to create node registry table")
+		}
+	}
+
+	// tx submission table is absent in database created by legacy versions
+	if !newCreated && !db.Migrator().HasTable(&gormstore.TxSubmission{}) {
+		if err := db.Migrator().CreateTable(&gormstore.TxSubmission{}); err != nil {
+			logrus.WithError(err).Fatal("Failed to create tx submission table")
+		}
+	}
+
 	if sqlDb, err := db.DB(); err != nil {
 		logrus.WithError(err).Fatal("Failed to init mysql db")
 	} else {
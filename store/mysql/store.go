@@ -7,8 +7,11 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
 	citypes "github.com/scroll-tech/rpc-gateway/types"
 	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
@@ -16,6 +19,7 @@ var (
 	_ store.Readable      = (*MysqlStore)(nil)
 	_ store.StackOperable = (*MysqlStore)(nil)
 	_ store.Configurable  = (*MysqlStore)(nil)
+	_ store.DBStore       = (*MysqlStore)(nil)
 	_ io.Closer           = (*MysqlStore)(nil)
 )
 
@@ -27,15 +31,16 @@ type StoreOption struct {
 type MysqlStore struct {
 	*baseStore
 	*epochBlockMapStore
-	*txStore
-	*blockStore
-	*confStore
-	*UserStore
-	*RateLimitStore
+	*gormstore.BlockTxStore
+	*gormstore.ConfStore
+	*gormstore.UserStore
+	*gormstore.TxSubmissionStore
+	*gormstore.RateLimitStore
 	ls   *logStore
 	ails *AddressIndexedLogStore
 	bcls *bigContractLogStore
-	cs   *ContractStore
+	cs   *gormstore.ContractStore
+	nrs  *NodeRegistryStore
 
 	// config
 	config *Config
@@ -47,22 +52,23 @@ type MysqlStore struct {
 
 func mustNewStore(db *gorm.DB, config *Config, option StoreOption) *MysqlStore {
 	pruner := newStorePruner(db)
-	cs := NewContractStore(db)
+	cs := gormstore.NewContractStore(db)
 	ebms := newEpochBlockMapStore(db, config)
 	ails := NewAddressIndexedLogStore(db, cs, config.AddressIndexedLogPartitions)
 
 	return &MysqlStore{
 		baseStore:          newBaseStore(db),
 		epochBlockMapStore: ebms,
-		txStore:            newTxStore(db),
-		blockStore:         newBlockStore(db),
-		confStore:          newConfStore(db),
-		UserStore:          newUserStore(db),
-		RateLimitStore:     NewRateLimitStore(db),
+		BlockTxStore:       gormstore.NewBlockTxStore(db),
+		ConfStore:          gormstore.NewConfStore(db),
+		UserStore:          gormstore.NewUserStore(db),
+		TxSubmissionStore:  gormstore.NewTxSubmissionStore(db),
+		RateLimitStore:     gormstore.NewRateLimitStore(db),
 		ls:                 newLogStore(db, cs, ebms, pruner.newBnPartitionObsChan),
 		bcls:               newBigContractLogStore(db, cs, ebms, ails, pruner.newBnPartitionObsChan),
 		ails:               ails,
 		cs:                 cs,
+		nrs:                NewNodeRegistryStore(db),
 		config:             config,
 		disabler:           option.Disabler,
 		pruner:             pruner,
@@ -129,7 +135,7 @@ func (ms *MysqlStore) Pushn(dataSlice []*store.EpochData) error {
 	return ms.baseStore.db.Transaction(func(dbTx *gorm.DB) error {
 		if !ms.disabler.IsChainBlockDisabled() {
 			// save blocks
-			if err := ms.blockStore.Add(dbTx, dataSlice); err != nil {
+			if err := ms.BlockStore.Add(dbTx, dataSlice); err != nil {
 				return errors.WithMessagef(err, "failed to save blocks")
 			}
 		}
@@ -138,7 +144,7 @@ func (ms *MysqlStore) Pushn(dataSlice []*store.EpochData) error {
 		skipRcpt := ms.disabler.IsChainReceiptDisabled()
 		if !skipRcpt || !skipTxn {
 			// save transactions or receipts
-			if err := ms.txStore.Add(dbTx, dataSlice, skipTxn, skipRcpt); err != nil {
+			if err := ms.TxStore.Add(dbTx, dataSlice, skipTxn, skipRcpt); err != nil {
 				return errors.WithMessage(err, "failed to save transactions")
 			}
 		}
@@ -191,7 +197,7 @@ func (ms *MysqlStore) Popn(epochUntil uint64) error {
 	return ms.baseStore.db.Transaction(func(dbTx *gorm.DB) error {
 		if !ms.disabler.IsChainBlockDisabled() {
 			// remove blocks
-			if err := ms.blockStore.Remove(dbTx, epochUntil, maxEpoch); err != nil {
+			if err := ms.BlockStore.Remove(dbTx, epochUntil, maxEpoch); err != nil {
 				return errors.WithMessage(err, "failed to remove blocks")
 			}
 		}
@@ -200,7 +206,7 @@ func (ms *MysqlStore) Popn(epochUntil uint64) error {
 		skipRcpt := ms.disabler.IsChainReceiptDisabled()
 		if !skipRcpt || !skipTxn {
 			// remove transactions or receipts
-			if err := ms.txStore.Remove(dbTx, epochUntil, maxEpoch); err != nil {
+			if err := ms.TxStore.Remove(dbTx, epochUntil, maxEpoch); err != nil {
 				return errors.WithMessage(err, "failed to remove transactions")
 			}
 		}
@@ -229,14 +235,24 @@ func (ms *MysqlStore) Popn(epochUntil uint64) error {
 		}
 
 		// pop is always due to pivot chain switch, update reorg version too
-		return ms.confStore.createOrUpdateReorgVersion(dbTx)
+		return ms.ConfStore.CreateOrUpdateReorgVersion(dbTx)
 	})
 }
 
-func (ms *MysqlStore) GetLogs(ctx context.Context, storeFilter store.LogFilter) ([]*store.Log, error) {
+func (ms *MysqlStore) GetLogs(ctx context.Context, storeFilter store.LogFilter) (logs []*store.Log, err error) {
 	updater := metrics.Registry.Store.GetLogs()
 	defer updater.Update()
 
+	ctx, span := tracing.Start(ctx, "mysql.GetLogs",
+		attribute.Int64("blockFrom", int64(storeFilter.BlockFrom)),
+		attribute.Int64("blockTo", int64(storeFilter.BlockTo)),
+		attribute.Int("contracts", storeFilter.Contracts.Count()),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int("logs", len(logs)))
+		tracing.End(span, err)
+	}()
+
 	contracts := storeFilter.Contracts.ToSlice()
 
 	// if address not specified, query from universal event log table partition
@@ -325,3 +341,8 @@ func (ms *MysqlStore) GetLogs(ctx context.Context, storeFilter store.LogFilter)
 func (ms *MysqlStore) Prune() {
 	go ms.pruner.schedulePrune(ms.config)
 }
+
+// NodeRegistry returns the node registry to persist fullnodes managed by node manager.
+func (ms *MysqlStore) NodeRegistry() *NodeRegistryStore {
+	return ms.nrs
+}
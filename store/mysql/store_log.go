@@ -5,8 +5,10 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
 	"github.com/scroll-tech/rpc-gateway/types"
 	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
 	"gorm.io/gorm"
 )
 
@@ -36,14 +38,16 @@ func (log) TableName() string {
 
 type logStore struct {
 	*bnPartitionedStore
-	cs    *ContractStore
+	cs    *gormstore.ContractStore
 	ebms  *epochBlockMapStore
 	model log
 	// notify channel for new bn partition created
 	bnPartitionNotifyChan chan<- *bnPartition
 }
 
-func newLogStore(db *gorm.DB, cs *ContractStore, ebms *epochBlockMapStore, notifyChan chan<- *bnPartition) *logStore {
+func newLogStore(
+	db *gorm.DB, cs *gormstore.ContractStore, ebms *epochBlockMapStore, notifyChan chan<- *bnPartition,
+) *logStore {
 	return &logStore{
 		bnPartitionedStore:    newBnPartitionedStore(db),
 		bnPartitionNotifyChan: notifyChan, cs: cs, ebms: ebms,
@@ -195,7 +199,13 @@ func (ls *logStore) GetLogs(ctx context.Context, storeFilter store.LogFilter) ([
 		default:
 		}
 
+		_, span := tracing.Start(ctx, "mysql.GetBnPartitionedLogs",
+			tracing.AttrDbTable.String(ls.getPartitionedTableName(&log{}, partition.Index)),
+		)
+
 		logs, err := ls.GetBnPartitionedLogs(filter, *partition)
+		tracing.End(span, err)
+
 		if err != nil {
 			return nil, err
 		}
@@ -223,19 +233,3 @@ func (ls *logStore) GetBnPartitionedLogs(filter LogFilter, partition bnPartition
 
 	return res, err
 }
-
-// extractUniqueContractAddresses extracts unique contract addresses of event logs within epoch data slice.
-func extractUniqueContractAddresses(slice ...*store.EpochData) map[string]bool {
-	contracts := make(map[string]bool)
-
-	for _, data := range slice {
-		for _, receipt := range data.Receipts {
-			for i := range receipt.Logs {
-				addr := receipt.Logs[i].Address.String()
-				contracts[addr] = true
-			}
-		}
-	}
-
-	return contracts
-}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
 	"github.com/scroll-tech/rpc-gateway/util"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -38,12 +39,12 @@ func (AddressIndexedLog) TableName() string {
 type AddressIndexedLogStore struct {
 	partitionedStore
 	db         *gorm.DB
-	cs         *ContractStore
+	cs         *gormstore.ContractStore
 	model      AddressIndexedLog
 	partitions uint32
 }
 
-func NewAddressIndexedLogStore(db *gorm.DB, cs *ContractStore, partitions uint32) *AddressIndexedLogStore {
+func NewAddressIndexedLogStore(db *gorm.DB, cs *gormstore.ContractStore, partitions uint32) *AddressIndexedLogStore {
 	return &AddressIndexedLogStore{
 		db:         db,
 		cs:         cs,
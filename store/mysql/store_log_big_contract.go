@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
 	"github.com/scroll-tech/rpc-gateway/types"
 	"github.com/scroll-tech/rpc-gateway/util"
 	"github.com/sirupsen/logrus"
@@ -39,7 +40,7 @@ func (cl contractLog) TableName() string {
 // event logs.
 type bigContractLogStore struct {
 	*bnPartitionedStore
-	cs   *ContractStore
+	cs   *gormstore.ContractStore
 	ebms *epochBlockMapStore
 	ails *AddressIndexedLogStore
 	// notify channel for new bn partition created
@@ -48,7 +49,7 @@ type bigContractLogStore struct {
 
 func newBigContractLogStore(
 	db *gorm.DB,
-	cs *ContractStore,
+	cs *gormstore.ContractStore,
 	ebms *epochBlockMapStore,
 	ails *AddressIndexedLogStore,
 	notifyChan chan<- *bnPartition,
@@ -63,7 +64,7 @@ func newBigContractLogStore(
 // Also migrates event logs from address indexed table to separate contract specified log table
 // for the initial partitioning.
 func (bcls *bigContractLogStore) preparePartitions(dataSlice []*store.EpochData) (map[uint64]bnPartition, error) {
-	contractAddrs := extractUniqueContractAddresses(dataSlice...)
+	contractAddrs := gormstore.ExtractUniqueContractAddresses(dataSlice...)
 	contract2BnPartitions := make(map[uint64]bnPartition)
 
 	for caddr := range contractAddrs {
@@ -119,7 +120,7 @@ func (bcls *bigContractLogStore) preparePartitions(dataSlice []*store.EpochData)
 }
 
 // migrate migrates address indexed event logs for the big contract to seperate log table partition.
-func (bcls *bigContractLogStore) migrate(contract *Contract, partition bnPartition) error {
+func (bcls *bigContractLogStore) migrate(contract *gormstore.Contract, partition bnPartition) error {
 	aiTableName := bcls.ails.GetPartitionedTableName(contract.Address)
 
 	clEntity, clTabler := bcls.contractEntity(contract.ID), bcls.contractTabler(contract.ID)
@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ node.Registry = (*NodeRegistryStore)(nil)
+
+// nodeRecord fullnode registered by node manager.
+type nodeRecord struct {
+	ID        uint32
+	Group     string  `gorm:"column:grp;size:64;not null;uniqueIndex:uidx_group_name,priority:1"`
+	Name      string  `gorm:"size:128;not null;uniqueIndex:uidx_group_name,priority:2"` // node name
+	Url       string  `gorm:"size:256;not null"`
+	Labels    []byte  `gorm:"type:text"` // json encoded labels
+	Weight    float64 `gorm:"not null;default:0"`
+	Disabled  bool    `gorm:"not null;default:false"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (nodeRecord) TableName() string {
+	return "node_registries"
+}
+
+// NodeRegistryStore implements node.Registry to persist fullnodes managed by node manager.
+type NodeRegistryStore struct {
+	*baseStore
+}
+
+func NewNodeRegistryStore(db *gorm.DB) *NodeRegistryStore {
+	return &NodeRegistryStore{
+		baseStore: newBaseStore(db),
+	}
+}
+
+func (nrs *NodeRegistryStore) Load() ([]node.NodeRecord, error) {
+	var records []nodeRecord
+	if err := nrs.db.Find(&records).Error; err != nil {
+		return nil, errors.WithMessage(err, "failed to load node registry")
+	}
+
+	result := make([]node.NodeRecord, 0, len(records))
+
+	for i := range records {
+		nr := node.NodeRecord{
+			Group:    node.Group(records[i].Group),
+			Url:      records[i].Url,
+			Weight:   records[i].Weight,
+			Disabled: records[i].Disabled,
+		}
+
+		if len(records[i].Labels) > 0 {
+			if err := json.Unmarshal(records[i].Labels, &nr.Labels); err != nil {
+				return nil, errors.WithMessagef(err, "failed to decode labels of node %v", records[i].Name)
+			}
+		}
+
+		result = append(result, nr)
+	}
+
+	return result, nil
+}
+
+func (nrs *NodeRegistryStore) Put(record node.NodeRecord) error {
+	var labels []byte
+	if len(record.Labels) > 0 {
+		var err error
+		if labels, err = json.Marshal(record.Labels); err != nil {
+			return errors.WithMessage(err, "failed to encode node labels")
+		}
+	}
+
+	nr := nodeRecord{
+		Group:    string(record.Group),
+		Name:     record.NodeName(),
+		Url:      record.Url,
+		Labels:   labels,
+		Weight:   record.Weight,
+		Disabled: record.Disabled,
+	}
+
+	return nrs.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"url", "labels", "weight", "disabled", "updated_at"}),
+	}).Create(&nr).Error
+}
+
+func (nrs *NodeRegistryStore) Delete(group node.Group, url string) error {
+	return nrs.db.
+		Where("grp = ? AND name = ?", string(group), rpc.Url2NodeName(url)).
+		Delete(&nodeRecord{}).Error
+}
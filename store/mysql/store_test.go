@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"os"
+	"testing"
+
+	gosql "github.com/go-sql-driver/mysql"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/storetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Behavioural tests run against a local MySQL database, e.g.
+// `TEST_MYSQL_DSN=root:root@tcp(127.0.0.1:3306)/confura_test?parseTime=true`.
+//
+// Note the database will be dropped after test, so never use any production database.
+const envTestMysqlDsn = "TEST_MYSQL_DSN"
+
+// testDisabler persists event logs only.
+type testDisabler struct{}
+
+func (testDisabler) IsChainBlockDisabled() bool                     { return true }
+func (testDisabler) IsChainTxnDisabled() bool                       { return true }
+func (testDisabler) IsChainReceiptDisabled() bool                   { return true }
+func (testDisabler) IsChainLogDisabled() bool                       { return false }
+func (testDisabler) IsDisabledForType(edt store.EpochDataType) bool { return edt != store.EpochLog }
+
+func mustNewTestStore(t *testing.T) *MysqlStore {
+	dsn := os.Getenv(envTestMysqlDsn)
+	if len(dsn) == 0 {
+		t.Skipf("Environment variable %v not specified to run against local database", envTestMysqlDsn)
+	}
+
+	dsnCfg, err := gosql.ParseDSN(dsn)
+	require.NoError(t, err)
+
+	config := Config{
+		Enabled:                         true,
+		Host:                            dsnCfg.Addr,
+		Username:                        dsnCfg.User,
+		Password:                        dsnCfg.Passwd,
+		Database:                        dsnCfg.DBName,
+		Dsn:                             dsn,
+		AddressIndexedLogEnabled:        true,
+		AddressIndexedLogPartitions:     4,
+		MaxBnRangedArchiveLogPartitions: 5,
+	}
+
+	ms := config.MustOpenOrCreate(StoreOption{Disabler: testDisabler{}})
+
+	t.Cleanup(func() {
+		// drop the whole database along with partitioned tables
+		assert.NoError(t, ms.db.Exec("DROP DATABASE IF EXISTS "+config.Database).Error)
+
+		ms.Close()
+	})
+
+	return ms
+}
+
+func TestMysqlStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.DBStore {
+		return mustNewTestStore(t)
+	})
+}
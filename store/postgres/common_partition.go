@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// pgPartition represents a child partition of PostgreSQL declaratively partitioned table.
+type pgPartition struct {
+	Name  string
+	Index int
+}
+
+// pgRangePartitioner helper struct for PostgreSQL range partitioned tables, of which each
+// partition holds a fixed size range of the partition key, e.g. [0, size), [size, 2*size) etc.
+type pgRangePartitioner struct {
+	tableName string // partitioned table name
+	rangeSize uint64 // range size of partition key per partition
+
+	// cached latest partition index, -1 means no partition created yet
+	latestIndex int
+	// whether the latest partition index is loaded from db
+	loaded bool
+}
+
+func newPgRangePartitioner(tableName string, rangeSize uint64) *pgRangePartitioner {
+	return &pgRangePartitioner{
+		tableName: tableName, rangeSize: rangeSize, latestIndex: -1,
+	}
+}
+
+// latestPartition returns latest created partition of the table.
+func (prp *pgRangePartitioner) latestPartition(db *gorm.DB) (*pgPartition, error) {
+	partitions, err := prp.loadPartitions(db)
+	if err == nil && len(partitions) > 0 {
+		return partitions[len(partitions)-1], nil
+	}
+
+	return nil, err
+}
+
+// oldestPartition returns oldest partition of the table.
+func (prp *pgRangePartitioner) oldestPartition(db *gorm.DB) (*pgPartition, error) {
+	partitions, err := prp.loadPartitions(db)
+	if err == nil && len(partitions) > 0 {
+		return partitions[0], nil
+	}
+
+	return nil, err
+}
+
+// loadPartitions loads partitions of the table in ascending order of partition index.
+func (prp *pgRangePartitioner) loadPartitions(db *gorm.DB) ([]*pgPartition, error) {
+	names, err := loadPartitionNames(db, prp.tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var partitions []*pgPartition
+	for _, name := range names {
+		if index := prp.indexOfPartition(name); index >= 0 {
+			partitions = append(partitions, &pgPartition{Name: name, Index: index})
+		}
+	}
+
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].Index < partitions[j].Index
+	})
+
+	return partitions, nil
+}
+
+// preparePartitions creates new partitions if necessary to hold the specified range of partition key.
+func (prp *pgRangePartitioner) preparePartitions(db *gorm.DB, from, to uint64) error {
+	if !prp.loaded {
+		partition, err := prp.latestPartition(db)
+		if err != nil {
+			return err
+		}
+
+		if partition != nil {
+			prp.latestIndex = partition.Index
+		}
+
+		prp.loaded = true
+	}
+
+	for index := int(from / prp.rangeSize); index <= int(to/prp.rangeSize); index++ {
+		if index <= prp.latestIndex { // partition already exists
+			continue
+		}
+
+		if err := prp.addPartition(db, index); err != nil {
+			return err
+		}
+
+		prp.latestIndex = index
+	}
+
+	return nil
+}
+
+func (prp *pgRangePartitioner) addPartition(db *gorm.DB, index int) error {
+	sql := fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %v PARTITION OF %v FOR VALUES FROM (%v) TO (%v);",
+		prp.partitionName(index),
+		prp.tableName,
+		prp.lowerBound(index),
+		prp.lowerBound(index+1),
+	)
+	return db.Exec(sql).Error
+}
+
+func (prp *pgRangePartitioner) removePartition(db *gorm.DB, partition *pgPartition) error {
+	// dropping a partition will detach it from the partitioned table automatically
+	sql := fmt.Sprintf("DROP TABLE IF EXISTS %v;", partition.Name)
+	return db.Exec(sql).Error
+}
+
+// lowerBound returns the inclusive lower bound of partition key for the specified partition.
+func (prp *pgRangePartitioner) lowerBound(index int) uint64 {
+	return uint64(index) * prp.rangeSize
+}
+
+func (prp *pgRangePartitioner) indexOfPartition(name string) int {
+	v, err := strconv.Atoi(strings.TrimPrefix(name, prp.tableName+"_p"))
+	if err == nil {
+		return v
+	}
+
+	return -1
+}
+
+func (prp *pgRangePartitioner) partitionName(index int) string {
+	return fmt.Sprintf("%v_p%d", prp.tableName, index)
+}
+
+// createHashPartitions creates the specified number of hash partitions for the hash partitioned table.
+func createHashPartitions(db *gorm.DB, tableName string, partitions uint32) error {
+	for i := uint32(0); i < partitions; i++ {
+		sql := fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %v_p%d PARTITION OF %v FOR VALUES WITH (MODULUS %v, REMAINDER %v);",
+			tableName, i, tableName, partitions, i,
+		)
+
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadPartitionNames loads all child partition names of the partitioned table in current schema.
+func loadPartitionNames(db *gorm.DB, tableName string) ([]string, error) {
+	var names []string
+
+	err := db.Raw(`SELECT child.relname FROM pg_inherits
+		JOIN pg_class parent ON pg_inherits.inhparent = parent.oid
+		JOIN pg_class child ON pg_inherits.inhrelid = child.oid
+		JOIN pg_namespace ns ON parent.relnamespace = ns.oid
+		WHERE parent.relname = ? AND ns.nspname = current_schema()`, tableName,
+	).Scan(&names).Error
+
+	return names, err
+}
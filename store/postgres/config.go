@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"fmt"
+	stdLog "log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/jackc/pgconn"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// maintenance database to connect to when creating database
+const maintenanceDatabase = "postgres"
+
+// auto migrating table models, note that partitioned tables are created by DDL separately
+var allModels = gormstore.Models()
+
+// Config represents the postgres configurations to open a database instance.
+type Config struct {
+	Enabled bool
+
+	Host     string `default:"127.0.0.1:5432"`
+	Username string
+	Password string
+	Database string
+	SslMode  string `default:"disable"`
+
+	Dsn string // to support one line config
+
+	ConnMaxLifetime time.Duration `default:"3m"`
+	MaxOpenConns    int           `default:"10"`
+	MaxIdleConns    int           `default:"10"`
+
+	AddressIndexedLogEnabled    bool   `default:"true"`
+	AddressIndexedLogPartitions uint32 `default:"100"`
+
+	MaxBnRangedArchiveLogPartitions uint32 `default:"5"`
+}
+
+func mustNewConfigFromViper(key string) *Config {
+	var cfg Config
+	viper.MustUnmarshalKey(key, &cfg)
+
+	if !cfg.Enabled {
+		return &Config{}
+	}
+
+	if len(cfg.Dsn) == 0 {
+		return &cfg
+	}
+
+	if err := cfg.resolveDsn(); err != nil {
+		logrus.WithError(err).WithField("dsn", cfg.Dsn).Fatal("Failed to parse db DSN")
+	}
+
+	return &cfg
+}
+
+// resolveDsn fills the connection configurations with the one line DSN config.
+func (config *Config) resolveDsn() error {
+	dsnCfg, err := pgconn.ParseConfig(config.Dsn)
+	if err != nil {
+		return err
+	}
+
+	config.Host = net.JoinHostPort(dsnCfg.Host, fmt.Sprint(dsnCfg.Port))
+	config.Username = dsnCfg.User
+	config.Password = dsnCfg.Password
+	config.Database = dsnCfg.Database
+
+	return nil
+}
+
+// MustNewConfigFromViper creates an instance of Config from Viper or panic on error.
+func MustNewConfigFromViper() *Config {
+	return mustNewConfigFromViper("store.postgres")
+}
+
+func MustNewEthStoreConfigFromViper() *Config {
+	return mustNewConfigFromViper("ethstore.postgres")
+}
+
+// MustOpenOrCreate creates an instance of store or exits on any erorr.
+func (config *Config) MustOpenOrCreate(option StoreOption) *PostgresStore {
+	newCreated := config.mustCreateDatabaseIfAbsent()
+
+	db := config.mustNewDB(config.Database)
+
+	// Very naive check on database tables are ready or not.
+	// If no database tables are found, we regard the database as a new created one.
+	if !newCreated {
+		var tables []string
+		err := db.Raw("SELECT tablename FROM pg_tables WHERE schemaname = current_schema()").
+			Scan(&tables).Error
+		if err != nil {
+			logrus.WithField("database", config.Database).WithError(err).Fatal("Failed to query database tables")
+		}
+
+		newCreated = (len(tables) == 0)
+	}
+
+	if newCreated {
+		if err := db.Migrator().CreateTable(allModels...); err != nil {
+			logrus.WithError(err).Fatal("Failed to create tables")
+		}
+
+		if err := createPartitionedTables(db, config); err != nil {
+			logrus.WithError(err).Fatal("Failed to create partitioned tables")
+		}
+	}
+
+	// tx submission table is absent in database created by legacy versions
+	if !newCreated && !db.Migrator().HasTable(&gormstore.TxSubmission{}) {
+		if err := db.Migrator().CreateTable(&gormstore.TxSubmission{}); err != nil {
+			logrus.WithError(err).Fatal("Failed to create tx submission table")
+		}
+	}
+
+	if sqlDb, err := db.DB(); err != nil {
+		logrus.WithError(err).Fatal("Failed to init postgres db")
+	} else {
+		sqlDb.SetConnMaxLifetime(config.ConnMaxLifetime)
+		sqlDb.SetMaxOpenConns(config.MaxOpenConns)
+		sqlDb.SetMaxIdleConns(config.MaxIdleConns)
+	}
+
+	logrus.Info("PostgreSQL database initialized")
+
+	return mustNewStore(db, config, option)
+}
+
+func (config *Config) mustNewDB(database string) *gorm.DB {
+	logrusLogLevel := logrus.GetLevel()
+	gLogLevel := gormLogger.Warn
+
+	// map log level of logrus to that of gorm
+	switch {
+	case logrusLogLevel <= logrus.ErrorLevel:
+		gLogLevel = gormLogger.Error
+	case logrusLogLevel >= logrus.DebugLevel:
+		// gorm info log level is kind of too verbose
+		gLogLevel = gormLogger.Info
+	}
+
+	// create gorm logger by customizing the default logger
+	gLogger := gormLogger.New(
+		stdLog.New(os.Stdout, "\r\n", stdLog.LstdFlags), // io writer
+		gormLogger.Config{
+			SlowThreshold:             time.Millisecond * 200, // slow SQL threshold (200ms)
+			LogLevel:                  gLogLevel,              // log level
+			IgnoreRecordNotFoundError: true,                   // never logging on ErrRecordNotFound error
+			Colorful:                  true,                   // use colorful print
+		},
+	)
+
+	db, err := gorm.Open(postgres.Open(config.dsn(database)), &gorm.Config{
+		Logger: gLogger,
+	})
+
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to open postgres")
+	}
+
+	return db
+}
+
+// dsn returns the data source name to connect to the specified database.
+//
+// Refer to https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-CONNSTRING
+func (config *Config) dsn(database string) string {
+	if database == config.Database && len(config.Dsn) > 0 {
+		return config.Dsn
+	}
+
+	host, port, err := net.SplitHostPort(config.Host)
+	if err != nil {
+		logrus.WithError(err).WithField("host", config.Host).Fatal("Failed to parse db host")
+	}
+
+	return fmt.Sprintf(
+		"host=%v port=%v user=%v password=%v dbname=%v sslmode=%v",
+		host, port, config.Username, config.Password, database, config.SslMode,
+	)
+}
+
+func (config *Config) mustCreateDatabaseIfAbsent() bool {
+	db := config.mustNewDB(maintenanceDatabase)
+	if pgDb, err := db.DB(); err != nil {
+		return false
+	} else {
+		defer pgDb.Close()
+	}
+
+	var count int64
+	err := db.Raw("SELECT COUNT(*) FROM pg_database WHERE datname = ?", config.Database).
+		Scan(&count).Error
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to query databases")
+	}
+
+	if count > 0 {
+		return false
+	}
+
+	// postgres does not support `CREATE DATABASE IF NOT EXISTS`
+	if err = db.Exec(fmt.Sprintf("CREATE DATABASE %q", config.Database)).Error; err != nil {
+		logrus.WithError(err).Fatal("Failed to create database")
+	}
+
+	logrus.Info("Create database for the first time")
+
+	return true
+}
@@ -0,0 +1,331 @@
+package postgres
+
+import (
+	"context"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
+	citypes "github.com/scroll-tech/rpc-gateway/types"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+var (
+	_ store.Readable      = (*PostgresStore)(nil)
+	_ store.StackOperable = (*PostgresStore)(nil)
+	_ store.Configurable  = (*PostgresStore)(nil)
+	_ store.DBStore       = (*PostgresStore)(nil)
+	_ io.Closer           = (*PostgresStore)(nil)
+)
+
+// interval to prune archive log partitions
+const pruneInterval = 15 * time.Minute
+
+type StoreOption struct {
+	Disabler store.StoreDisabler
+}
+
+// PostgresStore aggregation store for chain data persistence operation.
+type PostgresStore struct {
+	*gormstore.BaseStore
+	*epochBlockMapStore
+	*gormstore.BlockTxStore
+	*gormstore.ConfStore
+	*gormstore.UserStore
+	*gormstore.TxSubmissionStore
+	*gormstore.RateLimitStore
+	db   *gorm.DB
+	ls   *logStore
+	ails *AddressIndexedLogStore
+	cs   *gormstore.ContractStore
+
+	// config
+	config *Config
+	// store chaindata disabler
+	disabler store.StoreDisabler
+}
+
+func mustNewStore(db *gorm.DB, config *Config, option StoreOption) *PostgresStore {
+	cs := gormstore.NewContractStore(db)
+	ebms := newEpochBlockMapStore(db)
+
+	return &PostgresStore{
+		BaseStore:          gormstore.NewBaseStore(db),
+		db:                 db,
+		epochBlockMapStore: ebms,
+		BlockTxStore:       gormstore.NewBlockTxStore(db),
+		ConfStore:          gormstore.NewConfStore(db),
+		UserStore:          gormstore.NewUserStore(db),
+		TxSubmissionStore:  gormstore.NewTxSubmissionStore(db),
+		RateLimitStore:     gormstore.NewRateLimitStore(db),
+		ls:                 newLogStore(db, cs, ebms),
+		ails:               NewAddressIndexedLogStore(db, cs, config.AddressIndexedLogPartitions),
+		cs:                 cs,
+		config:             config,
+		disabler:           option.Disabler,
+	}
+}
+
+// createPartitionedTables creates the declaratively partitioned tables, which are not supported
+// by gorm auto migration.
+func createPartitionedTables(db *gorm.DB, config *Config) error {
+	ddls := append([]string{ddlCreateEpochBlockMapTable}, ddlCreateLogTable...)
+	for _, ddl := range ddls {
+		if err := db.Exec(ddl).Error; err != nil {
+			return err
+		}
+	}
+
+	ails := NewAddressIndexedLogStore(db, gormstore.NewContractStore(db), config.AddressIndexedLogPartitions)
+	if err := ails.CreatePartitionedTables(); err != nil {
+		return errors.WithMessagef(
+			err, "failed to create address indexed log tables with %v partitions",
+			config.AddressIndexedLogPartitions,
+		)
+	}
+
+	return nil
+}
+
+func (ps *PostgresStore) Push(data *store.EpochData) error {
+	return ps.Pushn([]*store.EpochData{data})
+}
+
+func (ps *PostgresStore) Pushn(dataSlice []*store.EpochData) error {
+	if len(dataSlice) == 0 {
+		return nil
+	}
+
+	storeMaxEpoch, ok, err := ps.MaxEpoch()
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		storeMaxEpoch = citypes.EpochNumberNil
+	}
+
+	if err := store.RequireContinuous(dataSlice, storeMaxEpoch); err != nil {
+		return err
+	}
+
+	updater := metrics.Registry.Store.Push("postgres")
+	defer updater.Update()
+
+	if !ps.disabler.IsChainLogDisabled() {
+		// add log contract address
+		if ps.config.AddressIndexedLogEnabled {
+			// Note, even if failed to insert event logs afterward, no need to rollback the inserted contract records.
+			if _, err := ps.cs.AddContractByEpochData(dataSlice...); err != nil {
+				return errors.WithMessage(err, "failed to add contracts for specified epoch data slice")
+			}
+		}
+
+		// prepare for new log partitions if necessary before saving epoch data
+		if err := ps.ls.preparePartition(dataSlice); err != nil {
+			return errors.WithMessage(err, "failed to prepare log partition")
+		}
+	}
+
+	// prepare epoch to block mapping table partition if necessary
+	if err := ps.epochBlockMapStore.preparePartition(dataSlice); err != nil {
+		return errors.WithMessage(err, "failed to prepare epoch block map partition")
+	}
+
+	return ps.db.Transaction(func(dbTx *gorm.DB) error {
+		if !ps.disabler.IsChainBlockDisabled() {
+			// save blocks
+			if err := ps.BlockStore.Add(dbTx, dataSlice); err != nil {
+				return errors.WithMessagef(err, "failed to save blocks")
+			}
+		}
+
+		skipTxn := ps.disabler.IsChainTxnDisabled()
+		skipRcpt := ps.disabler.IsChainReceiptDisabled()
+		if !skipRcpt || !skipTxn {
+			// save transactions or receipts
+			if err := ps.TxStore.Add(dbTx, dataSlice, skipTxn, skipRcpt); err != nil {
+				return errors.WithMessage(err, "failed to save transactions")
+			}
+		}
+
+		if !ps.disabler.IsChainLogDisabled() {
+			if ps.config.AddressIndexedLogEnabled {
+				// save address indexed event logs
+				for _, data := range dataSlice {
+					if err := ps.ails.AddAddressIndexedLogs(dbTx, data); err != nil {
+						return errors.WithMessage(err, "failed to save address indexed event logs")
+					}
+				}
+			}
+
+			// save event logs
+			if err := ps.ls.Add(dbTx, dataSlice); err != nil {
+				return errors.WithMessage(err, "failed to save event logs")
+			}
+		}
+
+		// save epoch to block mapping data
+		return ps.epochBlockMapStore.Add(dbTx, dataSlice)
+	})
+}
+
+// Popn pops multiple epoch data from database.
+func (ps *PostgresStore) Popn(epochUntil uint64) error {
+	maxEpoch, ok, err := ps.MaxEpoch()
+	if err != nil {
+		return errors.WithMessage(err, "failed to get max epoch")
+	}
+
+	if !ok || epochUntil > maxEpoch { // no data in database or popped beyond the max epoch
+		return nil
+	}
+
+	updater := metrics.Registry.Store.Pop("postgres")
+	defer updater.Update()
+
+	return ps.db.Transaction(func(dbTx *gorm.DB) error {
+		if !ps.disabler.IsChainBlockDisabled() {
+			// remove blocks
+			if err := ps.BlockStore.Remove(dbTx, epochUntil, maxEpoch); err != nil {
+				return errors.WithMessage(err, "failed to remove blocks")
+			}
+		}
+
+		skipTxn := ps.disabler.IsChainTxnDisabled()
+		skipRcpt := ps.disabler.IsChainReceiptDisabled()
+		if !skipRcpt || !skipTxn {
+			// remove transactions or receipts
+			if err := ps.TxStore.Remove(dbTx, epochUntil, maxEpoch); err != nil {
+				return errors.WithMessage(err, "failed to remove transactions")
+			}
+		}
+
+		if !ps.disabler.IsChainLogDisabled() {
+			// remove address indexed event logs
+			if ps.config.AddressIndexedLogEnabled {
+				if err := ps.ails.DeleteAddressIndexedLogs(dbTx, epochUntil, maxEpoch); err != nil {
+					return errors.WithMessage(err, "failed to remove address indexed event logs")
+				}
+			}
+
+			// pop universal event logs
+			if err := ps.ls.Popn(dbTx, epochUntil); err != nil {
+				return errors.WithMessage(err, "failed to remove universal event logs")
+			}
+		}
+
+		// remove epoch to block mapping data
+		if err := ps.epochBlockMapStore.Remove(dbTx, epochUntil, maxEpoch); err != nil {
+			return errors.WithMessage(err, "failed to remove epoch to block mapping data")
+		}
+
+		// pop is always due to pivot chain switch, update reorg version too
+		return ps.ConfStore.CreateOrUpdateReorgVersion(dbTx)
+	})
+}
+
+func (ps *PostgresStore) GetLogs(ctx context.Context, storeFilter store.LogFilter) (logs []*store.Log, err error) {
+	updater := metrics.Registry.Store.GetLogs()
+	defer updater.Update()
+
+	ctx, span := tracing.Start(ctx, "postgres.GetLogs",
+		attribute.Int64("blockFrom", int64(storeFilter.BlockFrom)),
+		attribute.Int64("blockTo", int64(storeFilter.BlockTo)),
+		attribute.Int("contracts", storeFilter.Contracts.Count()),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int("logs", len(logs)))
+		tracing.End(span, err)
+	}()
+
+	contracts := storeFilter.Contracts.ToSlice()
+
+	// if address not specified, query from universal event log table partitioned
+	// by block number range.
+	if len(contracts) == 0 {
+		return ps.ls.GetLogs(ctx, storeFilter)
+	}
+
+	filter := LogFilter{
+		BlockFrom: storeFilter.BlockFrom,
+		BlockTo:   storeFilter.BlockTo,
+		Topics:    storeFilter.Topics,
+	}
+
+	var result []*store.Log
+	for _, addr := range contracts {
+		// convert contract address to id
+		cid, exists, err := ps.cs.GetContractIdByAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			continue
+		}
+
+		// check timeout before query
+		select {
+		case <-ctx.Done():
+			return nil, store.ErrGetLogsTimeout
+		default:
+		}
+
+		// query from address indexed logs
+		addrFilter := AddressIndexedLogFilter{
+			LogFilter:  filter,
+			ContractId: cid,
+		}
+
+		logs, err := ps.ails.GetAddressIndexedLogs(addrFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		// convert to common store log
+		for _, v := range logs {
+			result = append(result, (*store.Log)(v))
+		}
+
+		// check log count
+		if len(result) > int(store.MaxLogLimit) {
+			return nil, store.ErrGetLogsResultSetTooLarge
+		}
+	}
+
+	// merge && sort log result
+	sort.Sort(store.LogSlice(result))
+
+	return result, nil
+}
+
+// Prune prune data from db store.
+func (ps *PostgresStore) Prune() {
+	go ps.schedulePrune()
+}
+
+// schedulePrune periodically removes extra more than the max sepcified number of archive
+// log partitions. Be noted this function will block caller thread.
+func (ps *PostgresStore) schedulePrune() {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		pruned, err := ps.ls.pruneArchivePartitions(ps.config.MaxBnRangedArchiveLogPartitions)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to prune archive log partitions")
+		}
+
+		if len(pruned) > 0 {
+			logrus.WithField("prunedPartitions", pruned).Info("Archive partitions pruned")
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
+	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// block number range size per log partition
+	bnPartitionedLogRangeSize = 1_000_000
+)
+
+// DDL to create block number range partitioned event log table
+var ddlCreateLogTable = []string{
+	`CREATE TABLE IF NOT EXISTS logs (
+		id BIGSERIAL,
+		cid BIGINT NOT NULL,
+		bn BIGINT NOT NULL,
+		epoch BIGINT NOT NULL,
+		topic0 VARCHAR(66) NOT NULL,
+		topic1 VARCHAR(66),
+		topic2 VARCHAR(66),
+		topic3 VARCHAR(66),
+		log_index BIGINT NOT NULL,
+		extra BYTEA,
+		PRIMARY KEY (id, bn)
+	) PARTITION BY RANGE (bn);`,
+	`CREATE INDEX IF NOT EXISTS idx_logs_bn ON logs (bn);`,
+}
+
+type log struct {
+	ID          uint64
+	ContractID  uint64 `gorm:"column:cid"`
+	BlockNumber uint64 `gorm:"column:bn"`
+	Epoch       uint64
+	Topic0      string
+	Topic1      string
+	Topic2      string
+	Topic3      string
+	LogIndex    uint64
+	Extra       []byte // extension json field
+}
+
+func (log) TableName() string {
+	return "logs"
+}
+
+// logStore stores universal event logs in table which is declaratively partitioned by block
+// number range, so that the event logs could be queried by block number range efficiently
+// and the archive partitions could be pruned by dropping partition tables.
+type logStore struct {
+	*gormstore.BaseStore
+	db          *gorm.DB
+	cs          *gormstore.ContractStore
+	ebms        *epochBlockMapStore
+	partitioner *pgRangePartitioner
+}
+
+func newLogStore(db *gorm.DB, cs *gormstore.ContractStore, ebms *epochBlockMapStore) *logStore {
+	return &logStore{
+		BaseStore:   gormstore.NewBaseStore(db),
+		db:          db,
+		partitioner: newPgRangePartitioner(log{}.TableName(), bnPartitionedLogRangeSize),
+		cs:          cs, ebms: ebms,
+	}
+}
+
+// preparePartition create new log partitions if necessary.
+func (ls *logStore) preparePartition(dataSlice []*store.EpochData) error {
+	bnMin := dataSlice[0].Blocks[0].BlockNumber.ToInt().Uint64()
+	bnMax := dataSlice[len(dataSlice)-1].GetPivotBlock().BlockNumber.ToInt().Uint64()
+
+	return ls.partitioner.preparePartitions(ls.db, bnMin, bnMax)
+}
+
+func (ls *logStore) Add(dbTx *gorm.DB, dataSlice []*store.EpochData) error {
+	// containers to collect event logs for batch inserting
+	var logs []*log
+
+	for _, data := range dataSlice {
+		for _, block := range data.Blocks {
+			bn := block.BlockNumber.ToInt().Uint64()
+
+			for _, tx := range block.Transactions {
+				receipt := data.Receipts[tx.Hash]
+
+				// Skip transactions that unexecuted in block.
+				if receipt == nil || !util.IsTxExecutedInBlock(&tx) {
+					continue
+				}
+
+				var rcptExt *store.ReceiptExtra
+				if len(data.ReceiptExts) > 0 {
+					rcptExt = data.ReceiptExts[tx.Hash]
+				}
+
+				for k, rlog := range receipt.Logs {
+					cid, _, err := ls.cs.AddContractIfAbsent(rlog.Address.MustGetBase32Address())
+					if err != nil {
+						return errors.WithMessage(err, "failed to add contract")
+					}
+
+					var logExt *store.LogExtra
+					if rcptExt != nil && k < len(rcptExt.LogExts) {
+						logExt = rcptExt.LogExts[k]
+					}
+
+					clog := store.ParseCfxLog(&rlog, cid, bn, logExt)
+					logs = append(logs, (*log)(clog))
+				}
+			}
+		}
+	}
+
+	if len(logs) == 0 {
+		return nil
+	}
+
+	// event logs will be routed to the partition by block number automatically
+	return dbTx.CreateInBatches(logs, defaultBatchSizeLogInsert).Error
+}
+
+// Popn pops event logs until the specific epoch from db store.
+func (ls *logStore) Popn(dbTx *gorm.DB, epochUntil uint64) error {
+	bn, ok, err := ls.ebms.BlockRange(epochUntil)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to get block mapping for epoch %v", epochUntil)
+	}
+
+	if !ok { // no block mapping found for epoch
+		return errors.Errorf("no block mapping found for epoch %v", epochUntil)
+	}
+
+	return dbTx.Where("bn >= ?", bn.From).Delete(&log{}).Error
+}
+
+func (ls *logStore) GetLogs(ctx context.Context, storeFilter store.LogFilter) ([]*store.Log, error) {
+	bnStart, existed, err := ls.bnStart()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get start block number")
+	}
+
+	if !existed { // no event logs persisted yet
+		return nil, nil
+	}
+
+	if storeFilter.BlockFrom < bnStart {
+		// search range is before the first partition or event logs are not synced yet
+		return nil, store.ErrAlreadyPruned
+	}
+
+	// check timeout before query
+	select {
+	case <-ctx.Done():
+		return nil, store.ErrGetLogsTimeout
+	default:
+	}
+
+	// partitions out of the block number range will be pruned by the query planner
+	filter := LogFilter{
+		TableName: log{}.TableName(),
+		BlockFrom: storeFilter.BlockFrom,
+		BlockTo:   storeFilter.BlockTo,
+		Topics:    storeFilter.Topics,
+	}
+
+	_, span := tracing.Start(ctx, "postgres.FindLogs", tracing.AttrDbTable.String(filter.TableName))
+
+	var logs []*log
+	err = filter.find(ls.db, &logs)
+	tracing.End(span, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(logs) > int(store.MaxLogLimit) {
+		return nil, store.ErrGetLogsResultSetTooLarge
+	}
+
+	// convert to common store log
+	result := make([]*store.Log, 0, len(logs))
+	for _, v := range logs {
+		result = append(result, (*store.Log)(v))
+	}
+
+	return result, nil
+}
+
+// bnStart returns the start block number since which event logs are fully persisted, which
+// is the larger one of the first synchronized block and the lower bound of oldest partition.
+func (ls *logStore) bnStart() (uint64, bool, error) {
+	minEpoch, existed, err := ls.ebms.MinEpoch()
+	if err != nil || !existed {
+		return 0, false, err
+	}
+
+	bnr, existed, err := ls.ebms.BlockRange(minEpoch)
+	if err != nil || !existed {
+		return 0, false, err
+	}
+
+	partition, err := ls.partitioner.oldestPartition(ls.db)
+	if err != nil || partition == nil {
+		return 0, false, err
+	}
+
+	return util.MaxUint64(bnr.From, ls.partitioner.lowerBound(partition.Index)), true, nil
+}
+
+// pruneArchivePartitions drops the oldest partitions until the number of archive partitions
+// (all partitions except the latest one) is no more than the specified number.
+func (ls *logStore) pruneArchivePartitions(maxArchivePartitions uint32) ([]*pgPartition, error) {
+	partitions, err := ls.partitioner.loadPartitions(ls.db)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load partitions")
+	}
+
+	var prunedPartitions []*pgPartition
+	for i := 0; len(partitions)-1-i > int(maxArchivePartitions); i++ {
+		logrus.WithFields(logrus.Fields{
+			"partition":            partitions[i].Name,
+			"maxArchivePartitions": maxArchivePartitions,
+		}).Debug("Pruning archive log partition...")
+
+		if err := ls.partitioner.removePartition(ls.db, partitions[i]); err != nil {
+			return prunedPartitions, errors.WithMessagef(err, "failed to drop partition %v", partitions[i].Name)
+		}
+
+		prunedPartitions = append(prunedPartitions, partitions[i])
+	}
+
+	return prunedPartitions, nil
+}
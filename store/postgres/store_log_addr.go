@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
+	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const defaultBatchSizeLogInsert = 500
+
+// Address indexed logs are used to filter event logs by contract address and optional block number.
+// Different from MySQL, the event logs are stored in a table declaratively partitioned by hash of
+// contract id, so that the query planner could prune partitions by the specified contract.
+
+// DDL to create hash partitioned address indexed event log table
+var ddlCreateAddressIndexedLogTable = []string{
+	`CREATE TABLE IF NOT EXISTS addr_logs (
+		id BIGSERIAL,
+		cid BIGINT NOT NULL,
+		bn BIGINT NOT NULL,
+		epoch BIGINT NOT NULL,
+		topic0 VARCHAR(66) NOT NULL,
+		topic1 VARCHAR(66),
+		topic2 VARCHAR(66),
+		topic3 VARCHAR(66),
+		log_index BIGINT NOT NULL,
+		extra BYTEA,
+		PRIMARY KEY (id, cid)
+	) PARTITION BY HASH (cid);`,
+	`CREATE INDEX IF NOT EXISTS idx_addr_logs_cid_bn ON addr_logs (cid, bn);`,
+	`CREATE INDEX IF NOT EXISTS idx_addr_logs_epoch ON addr_logs (epoch);`,
+}
+
+type AddressIndexedLog struct {
+	ID          uint64
+	ContractID  uint64 `gorm:"column:cid"`
+	BlockNumber uint64 `gorm:"column:bn"`
+	Epoch       uint64 // to support pop logs when reorg
+	Topic0      string
+	Topic1      string
+	Topic2      string
+	Topic3      string
+	LogIndex    uint64
+	Extra       []byte // extention json field
+}
+
+func (AddressIndexedLog) TableName() string {
+	return "addr_logs"
+}
+
+// AddressIndexedLogStore is used to store address indexed event logs in N hash partitions, e.g.
+// addr_logs_p0, addr_logs_p1, ..., addr_logs_pn.
+type AddressIndexedLogStore struct {
+	db         *gorm.DB
+	cs         *gormstore.ContractStore
+	model      AddressIndexedLog
+	partitions uint32
+}
+
+func NewAddressIndexedLogStore(db *gorm.DB, cs *gormstore.ContractStore, partitions uint32) *AddressIndexedLogStore {
+	return &AddressIndexedLogStore{
+		db:         db,
+		cs:         cs,
+		partitions: partitions,
+	}
+}
+
+// CreatePartitionedTables initializes the partitioned table along with all the hash partitions.
+func (ls *AddressIndexedLogStore) CreatePartitionedTables() error {
+	for _, ddl := range ddlCreateAddressIndexedLogTable {
+		if err := ls.db.Exec(ddl).Error; err != nil {
+			return err
+		}
+	}
+
+	return createHashPartitions(ls.db, ls.model.TableName(), ls.partitions)
+}
+
+// convertToAddressIndexedLogs converts the specified epoch data into address indexed event logs.
+func (ls *AddressIndexedLogStore) convertToAddressIndexedLogs(
+	data *store.EpochData,
+) ([]*AddressIndexedLog, map[uint64]int, error) {
+	contract2LogCount := make(map[uint64]int)
+	var logs []*AddressIndexedLog
+
+	for _, block := range data.Blocks {
+		bn := block.BlockNumber.ToInt().Uint64()
+
+		for _, tx := range block.Transactions {
+			// ignore txs that not executed in current block
+			if !util.IsTxExecutedInBlock(&tx) {
+				continue
+			}
+
+			receipt, ok := data.Receipts[tx.Hash]
+			if !ok {
+				// should never occur, just to ensure code robust
+				logrus.WithFields(logrus.Fields{
+					"epoch": data.Number,
+					"tx":    tx.Hash,
+				}).Error("Cannot find transaction receipt in epoch data")
+				continue
+			}
+
+			receiptExt := data.ReceiptExts[tx.Hash]
+
+			for i, v := range receipt.Logs {
+				cid, _, err := ls.cs.AddContractIfAbsent(v.Address.MustGetBase32Address())
+				if err != nil {
+					return nil, nil, err
+				}
+
+				var logext *store.LogExtra
+				if receiptExt != nil && i < len(receiptExt.LogExts) {
+					logext = receiptExt.LogExts[i]
+				}
+
+				log := store.ParseCfxLog(&v, cid, bn, logext)
+				logs = append(logs, (*AddressIndexedLog)(log))
+
+				contract2LogCount[cid]++
+			}
+		}
+	}
+
+	return logs, contract2LogCount, nil
+}
+
+// AddAddressIndexedLogs adds event logs of specified epoch, which will be routed to hash partitions by contract.
+func (ls *AddressIndexedLogStore) AddAddressIndexedLogs(dbTx *gorm.DB, data *store.EpochData) error {
+	logs, contract2LogCount, err := ls.convertToAddressIndexedLogs(data)
+	if err != nil {
+		return err
+	}
+
+	if len(logs) > 0 {
+		if err := dbTx.CreateInBatches(&logs, defaultBatchSizeLogInsert).Error; err != nil {
+			return err
+		}
+	}
+
+	for cid, logCount := range contract2LogCount {
+		// Update contract statistics (log count and lastest updated epoch).
+		if err := ls.cs.UpdateContractStats(dbTx, cid, logCount, data.Number); err != nil {
+			return errors.WithMessage(err, "failed to update contract statistics")
+		}
+	}
+
+	return nil
+}
+
+// DeleteAddressIndexedLogs removes event logs of specified epoch number range.
+//
+// Generally, this is used when pivot chain switched for confirmed blocks.
+func (ls *AddressIndexedLogStore) DeleteAddressIndexedLogs(dbTx *gorm.DB, epochFrom, epochTo uint64) error {
+	contracts, err := ls.cs.GetUpdatedContractsSinceEpoch(epochFrom)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get updated contracts since start epoch")
+	}
+
+	// Delete logs for all possible contracts, so that only one partition is scanned at a time.
+	for _, contract := range contracts {
+		sql := fmt.Sprintf("DELETE FROM %v WHERE cid = ? AND epoch BETWEEN ? AND ?", ls.model.TableName())
+		res := dbTx.Exec(sql, contract.ID, epochFrom, epochTo)
+		if err := res.Error; err != nil {
+			return err
+		}
+
+		// Update contract statistics (log count and lastest updated epoch).
+		if err := ls.cs.UpdateContractStats(dbTx, contract.ID, int(-res.RowsAffected), epochFrom); err != nil {
+			return errors.WithMessage(err, "failed to update contract statistics")
+		}
+	}
+
+	return nil
+}
+
+// GetAddressIndexedLogs returns event logs for the specified filter.
+func (ls *AddressIndexedLogStore) GetAddressIndexedLogs(filter AddressIndexedLogFilter) ([]*AddressIndexedLog, error) {
+	filter.TableName = ls.model.TableName()
+	return filter.Find(ls.db)
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
+	citypes "github.com/scroll-tech/rpc-gateway/types"
+	"gorm.io/gorm"
+)
+
+const (
+	// batch insert size for epoch to block mapping
+	defaultBatchSizeMappingInsert = 1000
+
+	// epoch to block mapping partition size
+	epochToBlockMappingPartitionSize = 50_000_000
+)
+
+// DDL to create range partitioned epoch to block mapping table
+const ddlCreateEpochBlockMapTable = `CREATE TABLE IF NOT EXISTS epoch_block_map (
+	epoch BIGINT NOT NULL,
+	bn_min BIGINT NOT NULL,
+	bn_max BIGINT NOT NULL,
+	pivot_hash VARCHAR(66) NOT NULL,
+	PRIMARY KEY (epoch)
+) PARTITION BY RANGE (epoch);`
+
+// epochBlockMap mapping data from epoch to relative block info (such as block range and pivot block hash).
+// Use PostgreSQL declarative range partitions for scalability and performance.
+type epochBlockMap struct {
+	// epoch number
+	Epoch uint64 `gorm:"primaryKey;autoIncrement:false"`
+	// min block number
+	BnMin uint64 `gorm:"not null"`
+	// max block number
+	BnMax uint64 `gorm:"not null"`
+	// pivot block hash used for parent hash checking
+	PivotHash string `gorm:"size:66;not null"`
+}
+
+func (epochBlockMap) TableName() string {
+	return "epoch_block_map"
+}
+
+// epochBlockMapStore used to get epoch to block map data.
+type epochBlockMapStore struct {
+	*gormstore.BaseStore
+	db *gorm.DB
+
+	// help partitioner
+	partitioner *pgRangePartitioner
+}
+
+func newEpochBlockMapStore(db *gorm.DB) *epochBlockMapStore {
+	return &epochBlockMapStore{
+		BaseStore: gormstore.NewBaseStore(db),
+		db:        db,
+		partitioner: newPgRangePartitioner(
+			epochBlockMap{}.TableName(), epochToBlockMappingPartitionSize,
+		),
+	}
+}
+
+// preparePartition creates new table partitions if necessary.
+func (ebms *epochBlockMapStore) preparePartition(dataSlice []*store.EpochData) error {
+	epochFrom, epochTo := dataSlice[0].Number, dataSlice[len(dataSlice)-1].Number
+	return ebms.partitioner.preparePartitions(ebms.db, epochFrom, epochTo)
+}
+
+// MaxEpoch returns the max epoch within the map store.
+func (e2bms *epochBlockMapStore) MaxEpoch() (uint64, bool, error) {
+	return e2bms.epochBound("MAX(epoch)")
+}
+
+// MinEpoch returns the min epoch within the map store.
+func (e2bms *epochBlockMapStore) MinEpoch() (uint64, bool, error) {
+	return e2bms.epochBound("MIN(epoch)")
+}
+
+func (e2bms *epochBlockMapStore) epochBound(selector string) (uint64, bool, error) {
+	var epoch sql.NullInt64
+
+	db := e2bms.db.Model(&epochBlockMap{}).Select(selector)
+	if err := db.Find(&epoch).Error; err != nil {
+		return 0, false, err
+	}
+
+	if !epoch.Valid {
+		return 0, false, nil
+	}
+
+	return uint64(epoch.Int64), true, nil
+}
+
+// BlockRange returns the spanning block range for the give epoch.
+func (e2bms *epochBlockMapStore) BlockRange(epoch uint64) (citypes.RangeUint64, bool, error) {
+	var e2bmap epochBlockMap
+	var bnr citypes.RangeUint64
+
+	existed, err := e2bms.Exists(&e2bmap, "epoch = ?", epoch)
+	if err != nil {
+		return bnr, false, err
+	}
+
+	bnr.From, bnr.To = e2bmap.BnMin, e2bmap.BnMax
+	return bnr, existed, nil
+}
+
+// PivotHash returns the pivot hash of the given epoch.
+func (e2bms *epochBlockMapStore) PivotHash(epoch uint64) (string, bool, error) {
+	var e2bmap epochBlockMap
+
+	existed, err := e2bms.Exists(&e2bmap, "epoch = ?", epoch)
+	if err != nil {
+		return "", false, err
+	}
+
+	return e2bmap.PivotHash, existed, nil
+}
+
+// Add batch saves epoch to block mapping data to db store.
+func (e2bms *epochBlockMapStore) Add(dbTx *gorm.DB, dataSlice []*store.EpochData) error {
+	var mappings []*epochBlockMap
+
+	for _, data := range dataSlice {
+		pivotBlock := data.GetPivotBlock()
+		mappings = append(mappings, &epochBlockMap{
+			Epoch:     data.Number,
+			BnMin:     data.Blocks[0].BlockNumber.ToInt().Uint64(),
+			BnMax:     pivotBlock.BlockNumber.ToInt().Uint64(),
+			PivotHash: pivotBlock.Hash.String(),
+		})
+	}
+
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	return dbTx.CreateInBatches(mappings, defaultBatchSizeMappingInsert).Error
+}
+
+// Remove remove epoch to block mappings of specific epoch range from db store.
+func (e2bms *epochBlockMapStore) Remove(dbTx *gorm.DB, epochFrom, epochTo uint64) error {
+	return dbTx.Where("epoch >= ? AND epoch <= ?", epochFrom, epochTo).Delete(&epochBlockMap{}).Error
+}
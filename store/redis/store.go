@@ -193,6 +193,91 @@ func (rs *RedisStore) GetBlockSummaryByBlockNumber(ctx context.Context, blockNum
 	return &store.BlockSummary{CfxBlockSummary: blocksum}, nil
 }
 
+func (rs *RedisStore) GetBlockTransactionCountByHash(ctx context.Context, blockHash types.Hash) (uint64, error) {
+	blocksum, err := loadBlockSummaryByHash(rs.ctx, rs.rdb, blockHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(blocksum.Transactions)), nil
+}
+
+func (rs *RedisStore) GetBlockTransactionCountByNumber(ctx context.Context, blockNumber uint64) (uint64, error) {
+	blocksum, err := loadBlockSummaryByNumber(rs.ctx, rs.rdb, blockNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(blocksum.Transactions)), nil
+}
+
+func (rs *RedisStore) GetTransactionByBlockHashAndIndex(
+	ctx context.Context, blockHash types.Hash, index uint32,
+) (*store.Transaction, error) {
+	blocksum, err := loadBlockSummaryByHash(rs.ctx, rs.rdb, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if int(index) >= len(blocksum.Transactions) {
+		return nil, nil
+	}
+
+	return rs.GetTransaction(ctx, blocksum.Transactions[index])
+}
+
+func (rs *RedisStore) GetTransactionByBlockNumberAndIndex(
+	ctx context.Context, blockNumber uint64, index uint32,
+) (*store.Transaction, error) {
+	blocksum, err := loadBlockSummaryByNumber(rs.ctx, rs.rdb, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if int(index) >= len(blocksum.Transactions) {
+		return nil, nil
+	}
+
+	return rs.GetTransaction(ctx, blocksum.Transactions[index])
+}
+
+func (rs *RedisStore) GetReceiptsByBlockHash(
+	ctx context.Context, blockHash types.Hash,
+) ([]*store.TransactionReceipt, error) {
+	blocksum, err := loadBlockSummaryByHash(rs.ctx, rs.rdb, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.getReceipts(ctx, blocksum.Transactions)
+}
+
+func (rs *RedisStore) GetReceiptsByBlockNumber(
+	ctx context.Context, blockNumber uint64,
+) ([]*store.TransactionReceipt, error) {
+	blocksum, err := loadBlockSummaryByNumber(rs.ctx, rs.rdb, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.getReceipts(ctx, blocksum.Transactions)
+}
+
+func (rs *RedisStore) getReceipts(ctx context.Context, txHashes []types.Hash) ([]*store.TransactionReceipt, error) {
+	receipts := make([]*store.TransactionReceipt, 0, len(txHashes))
+
+	for _, txHash := range txHashes {
+		receipt, err := rs.GetReceipt(ctx, txHash)
+		if err != nil {
+			return nil, err
+		}
+
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, nil
+}
+
 func (rs *RedisStore) Push(data *store.EpochData) error {
 	return rs.Pushn([]*store.EpochData{data})
 }
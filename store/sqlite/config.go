@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"fmt"
+	stdLog "log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// auto migrating table models
+var allModels = append(
+	gormstore.Models(),
+	&epochBlockMap{},
+	&log{},
+)
+
+// Config represents the sqlite configurations to open an embedded database instance.
+type Config struct {
+	// database file path, which will be created if absent
+	Path string `default:"data/confura.db"`
+	// timeout to wait for the database lock, e.g. RPC reading while sync writing
+	BusyTimeout time.Duration `default:"5s"`
+}
+
+// MustNewEthStoreConfigFromViper creates an instance of Config from Viper or panic on error.
+func MustNewEthStoreConfigFromViper() *Config {
+	var cfg Config
+	viper.MustUnmarshalKey("ethstore.sqlite", &cfg)
+	return &cfg
+}
+
+// cacheConfig represents the sqlite configurations of core space cache store, which replaces
+// redis in embedded mode.
+type cacheConfig struct {
+	Enabled bool
+	// database file path, which should differ from that of evm space store
+	Path        string        `default:"data/confura-cache.db"`
+	BusyTimeout time.Duration `default:"5s"`
+}
+
+// MustNewCacheConfigFromViper creates an instance of Config for core space cache store from Viper
+// or panic on error, and returns false if not enabled.
+func MustNewCacheConfigFromViper() (*Config, bool) {
+	var cfg cacheConfig
+	viper.MustUnmarshalKey("store.sqlite", &cfg)
+
+	if !cfg.Enabled {
+		return nil, false
+	}
+
+	return &Config{Path: cfg.Path, BusyTimeout: cfg.BusyTimeout}, true
+}
+
+// MustOpenOrCreate creates an instance of store or exits on any erorr.
+func (config *Config) MustOpenOrCreate(option StoreOption) *SqliteStore {
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0755); err != nil {
+		logrus.WithError(err).WithField("path", config.Path).Fatal("Failed to create sqlite data directory")
+	}
+
+	db := config.mustNewDB()
+
+	// tables and indexes are created if absent
+	if err := db.AutoMigrate(allModels...); err != nil {
+		logrus.WithError(err).Fatal("Failed to create tables")
+	}
+
+	logrus.WithField("path", config.Path).Info("SQLite database initialized")
+
+	return mustNewStore(db, option)
+}
+
+func (config *Config) mustNewDB() *gorm.DB {
+	logrusLogLevel := logrus.GetLevel()
+	gLogLevel := gormLogger.Warn
+
+	// map log level of logrus to that of gorm
+	switch {
+	case logrusLogLevel <= logrus.ErrorLevel:
+		gLogLevel = gormLogger.Error
+	case logrusLogLevel >= logrus.DebugLevel:
+		// gorm info log level is kind of too verbose
+		gLogLevel = gormLogger.Info
+	}
+
+	// create gorm logger by customizing the default logger
+	gLogger := gormLogger.New(
+		stdLog.New(os.Stdout, "\r\n", stdLog.LstdFlags), // io writer
+		gormLogger.Config{
+			SlowThreshold:             time.Millisecond * 200, // slow SQL threshold (200ms)
+			LogLevel:                  gLogLevel,              // log level
+			IgnoreRecordNotFoundError: true,                   // never logging on ErrRecordNotFound error
+			Colorful:                  true,                   // use colorful print
+		},
+	)
+
+	db, err := gorm.Open(sqlite.Open(config.dsn()), &gorm.Config{
+		Logger: gLogger,
+	})
+
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to open sqlite")
+	}
+
+	return db
+}
+
+// dsn returns the data source name with WAL journal mode enabled, so that RPC could read
+// concurrently while sync writing.
+//
+// Refer to https://github.com/mattn/go-sqlite3#connection-string
+func (config *Config) dsn() string {
+	return fmt.Sprintf(
+		"file:%v?_journal_mode=WAL&_busy_timeout=%d",
+		config.Path, config.BusyTimeout.Milliseconds(),
+	)
+}
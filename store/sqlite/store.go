@@ -0,0 +1,213 @@
+package sqlite
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
+	citypes "github.com/scroll-tech/rpc-gateway/types"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+var (
+	_ store.DBStore    = (*SqliteStore)(nil)
+	_ store.Store      = (*SqliteStore)(nil)
+	_ store.CacheStore = (*SqliteStore)(nil)
+	_ io.Closer        = (*SqliteStore)(nil)
+)
+
+type StoreOption struct {
+	Disabler store.StoreDisabler
+}
+
+// SqliteStore aggregation store for chain data persistence operation with an embedded
+// SQLite database, which is mainly used for local development and CI.
+type SqliteStore struct {
+	*gormstore.BaseStore
+	*epochBlockMapStore
+	*gormstore.BlockTxStore
+	*gormstore.ConfStore
+	*gormstore.UserStore
+	*gormstore.TxSubmissionStore
+	*gormstore.RateLimitStore
+	db *gorm.DB
+	ls *logStore
+	cs *gormstore.ContractStore
+
+	// store chaindata disabler
+	disabler store.StoreDisabler
+}
+
+func mustNewStore(db *gorm.DB, option StoreOption) *SqliteStore {
+	cs := gormstore.NewContractStore(db)
+
+	return &SqliteStore{
+		BaseStore:          gormstore.NewBaseStore(db),
+		db:                 db,
+		epochBlockMapStore: newEpochBlockMapStore(db),
+		BlockTxStore:       gormstore.NewBlockTxStore(db),
+		ConfStore:          gormstore.NewConfStore(db),
+		UserStore:          gormstore.NewUserStore(db),
+		TxSubmissionStore:  gormstore.NewTxSubmissionStore(db),
+		RateLimitStore:     gormstore.NewRateLimitStore(db),
+		ls:                 newLogStore(db, cs),
+		cs:                 cs,
+		disabler:           option.Disabler,
+	}
+}
+
+func (ss *SqliteStore) Push(data *store.EpochData) error {
+	return ss.Pushn([]*store.EpochData{data})
+}
+
+func (ss *SqliteStore) Pushn(dataSlice []*store.EpochData) error {
+	if len(dataSlice) == 0 {
+		return nil
+	}
+
+	storeMaxEpoch, ok, err := ss.MaxEpoch()
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		storeMaxEpoch = citypes.EpochNumberNil
+	}
+
+	if err := store.RequireContinuous(dataSlice, storeMaxEpoch); err != nil {
+		return err
+	}
+
+	updater := metrics.Registry.Store.Push("sqlite")
+	defer updater.Update()
+
+	if !ss.disabler.IsChainLogDisabled() {
+		// SQLite allows only one writer at a time, so contracts are added before the
+		// db transaction. Even if failed to insert event logs afterward, no need to
+		// rollback the inserted contract records.
+		if _, err := ss.cs.AddContractByEpochData(dataSlice...); err != nil {
+			return errors.WithMessage(err, "failed to add contracts for specified epoch data slice")
+		}
+	}
+
+	return ss.db.Transaction(func(dbTx *gorm.DB) error {
+		if !ss.disabler.IsChainBlockDisabled() {
+			// save blocks
+			if err := ss.BlockStore.Add(dbTx, dataSlice); err != nil {
+				return errors.WithMessagef(err, "failed to save blocks")
+			}
+		}
+
+		skipTxn := ss.disabler.IsChainTxnDisabled()
+		skipRcpt := ss.disabler.IsChainReceiptDisabled()
+		if !skipRcpt || !skipTxn {
+			// save transactions or receipts
+			if err := ss.TxStore.Add(dbTx, dataSlice, skipTxn, skipRcpt); err != nil {
+				return errors.WithMessage(err, "failed to save transactions")
+			}
+		}
+
+		if !ss.disabler.IsChainLogDisabled() {
+			// save event logs
+			if err := ss.ls.Add(dbTx, dataSlice); err != nil {
+				return errors.WithMessage(err, "failed to save event logs")
+			}
+		}
+
+		// save epoch to block mapping data
+		return ss.epochBlockMapStore.Add(dbTx, dataSlice)
+	})
+}
+
+// Popn pops multiple epoch data from database.
+func (ss *SqliteStore) Popn(epochUntil uint64) error {
+	maxEpoch, ok, err := ss.MaxEpoch()
+	if err != nil {
+		return errors.WithMessage(err, "failed to get max epoch")
+	}
+
+	if !ok || epochUntil > maxEpoch { // no data in database or popped beyond the max epoch
+		return nil
+	}
+
+	updater := metrics.Registry.Store.Pop("sqlite")
+	defer updater.Update()
+
+	return ss.db.Transaction(func(dbTx *gorm.DB) error {
+		if !ss.disabler.IsChainBlockDisabled() {
+			// remove blocks
+			if err := ss.BlockStore.Remove(dbTx, epochUntil, maxEpoch); err != nil {
+				return errors.WithMessage(err, "failed to remove blocks")
+			}
+		}
+
+		skipTxn := ss.disabler.IsChainTxnDisabled()
+		skipRcpt := ss.disabler.IsChainReceiptDisabled()
+		if !skipRcpt || !skipTxn {
+			// remove transactions or receipts
+			if err := ss.TxStore.Remove(dbTx, epochUntil, maxEpoch); err != nil {
+				return errors.WithMessage(err, "failed to remove transactions")
+			}
+		}
+
+		if !ss.disabler.IsChainLogDisabled() {
+			// remove event logs
+			if err := ss.ls.Remove(dbTx, epochUntil, maxEpoch); err != nil {
+				return errors.WithMessage(err, "failed to remove event logs")
+			}
+		}
+
+		// remove epoch to block mapping data
+		if err := ss.epochBlockMapStore.Remove(dbTx, epochUntil, maxEpoch); err != nil {
+			return errors.WithMessage(err, "failed to remove epoch to block mapping data")
+		}
+
+		// pop is always due to pivot chain switch, update reorg version too
+		return ss.ConfStore.CreateOrUpdateReorgVersion(dbTx)
+	})
+}
+
+func (ss *SqliteStore) GetLogs(ctx context.Context, storeFilter store.LogFilter) (logs []*store.Log, err error) {
+	updater := metrics.Registry.Store.GetLogs()
+	defer updater.Update()
+
+	ctx, span := tracing.Start(ctx, "sqlite.GetLogs",
+		attribute.Int64("blockFrom", int64(storeFilter.BlockFrom)),
+		attribute.Int64("blockTo", int64(storeFilter.BlockTo)),
+		attribute.Int("contracts", storeFilter.Contracts.Count()),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int("logs", len(logs)))
+		tracing.End(span, err)
+	}()
+
+	epochFrom, _, err := ss.GetLogEpochRange()
+	if ss.IsRecordNotFound(err) { // no event logs persisted yet
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get log epoch range")
+	}
+
+	bnr, ok, err := ss.BlockRange(epochFrom)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get block range for epoch %v", epochFrom)
+	}
+
+	if ok && storeFilter.BlockFrom < bnr.From {
+		// search range is before the first synchronized block or event logs are dequeued
+		return nil, store.ErrAlreadyPruned
+	}
+
+	return ss.ls.GetLogs(ctx, storeFilter)
+}
+
+// Prune does nothing since the embedded store is not expected to persist tremendous data,
+// and historical data could be dequeued by a `sync.Pruner` if necessary.
+func (ss *SqliteStore) Prune() {}
@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
+	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
+	"gorm.io/gorm"
+)
+
+const defaultBatchSizeLogInsert = 500
+
+type log struct {
+	ID          uint64
+	ContractID  uint64 `gorm:"column:cid;not null;index:idx_logs_cid_bn,priority:1"`
+	BlockNumber uint64 `gorm:"column:bn;not null;index:idx_logs_bn;index:idx_logs_cid_bn,priority:2"`
+	Epoch       uint64 `gorm:"not null;index:idx_logs_epoch"`
+	Topic0      string `gorm:"size:66;not null"`
+	Topic1      string `gorm:"size:66"`
+	Topic2      string `gorm:"size:66"`
+	Topic3      string `gorm:"size:66"`
+	LogIndex    uint64 `gorm:"not null"`
+	Extra       []byte // extension json field
+}
+
+func (log) TableName() string {
+	return "logs"
+}
+
+// logStore stores event logs in a single table, which is indexed by block number and
+// contract id so that event logs could be filtered with or without contract address.
+type logStore struct {
+	*gormstore.BaseStore
+	db *gorm.DB
+	cs *gormstore.ContractStore
+}
+
+func newLogStore(db *gorm.DB, cs *gormstore.ContractStore) *logStore {
+	return &logStore{
+		BaseStore: gormstore.NewBaseStore(db),
+		db:        db,
+		cs:        cs,
+	}
+}
+
+// Add batch saves event logs of the epoch data slice. Note, contracts must be added in advance,
+// since SQLite allows only one writer at a time.
+func (ls *logStore) Add(dbTx *gorm.DB, dataSlice []*store.EpochData) error {
+	// containers to collect event logs for batch inserting
+	var logs []*log
+
+	for _, data := range dataSlice {
+		for _, block := range data.Blocks {
+			bn := block.BlockNumber.ToInt().Uint64()
+
+			for _, tx := range block.Transactions {
+				receipt := data.Receipts[tx.Hash]
+
+				// Skip transactions that unexecuted in block.
+				if receipt == nil || !util.IsTxExecutedInBlock(&tx) {
+					continue
+				}
+
+				var rcptExt *store.ReceiptExtra
+				if len(data.ReceiptExts) > 0 {
+					rcptExt = data.ReceiptExts[tx.Hash]
+				}
+
+				for k, rlog := range receipt.Logs {
+					addr := rlog.Address.MustGetBase32Address()
+
+					cid, ok, err := ls.cs.GetContractIdByAddress(addr)
+					if err != nil {
+						return errors.WithMessage(err, "failed to get contract id")
+					}
+
+					if !ok {
+						return errors.Errorf("contract %v not added yet", addr)
+					}
+
+					var logExt *store.LogExtra
+					if rcptExt != nil && k < len(rcptExt.LogExts) {
+						logExt = rcptExt.LogExts[k]
+					}
+
+					clog := store.ParseCfxLog(&rlog, cid, bn, logExt)
+					logs = append(logs, (*log)(clog))
+				}
+			}
+		}
+	}
+
+	if len(logs) == 0 {
+		return nil
+	}
+
+	return dbTx.CreateInBatches(logs, defaultBatchSizeLogInsert).Error
+}
+
+// Remove removes event logs of specific epoch range from db store.
+func (ls *logStore) Remove(dbTx *gorm.DB, epochFrom, epochTo uint64) error {
+	return dbTx.Where("epoch >= ? AND epoch <= ?", epochFrom, epochTo).Delete(&log{}).Error
+}
+
+func (ls *logStore) GetLogs(ctx context.Context, storeFilter store.LogFilter) ([]*store.Log, error) {
+	filter := LogFilter{
+		BlockFrom: storeFilter.BlockFrom,
+		BlockTo:   storeFilter.BlockTo,
+		Topics:    storeFilter.Topics,
+	}
+
+	if contracts := storeFilter.Contracts.ToSlice(); len(contracts) > 0 {
+		// convert contract addresses to ids
+		for _, addr := range contracts {
+			cid, exists, err := ls.cs.GetContractIdByAddress(addr)
+			if err != nil {
+				return nil, err
+			}
+
+			if exists {
+				filter.ContractIds = append(filter.ContractIds, cid)
+			}
+		}
+
+		if len(filter.ContractIds) == 0 { // none of the contracts have ever emitted event logs
+			return nil, nil
+		}
+	}
+
+	// check timeout before query
+	select {
+	case <-ctx.Done():
+		return nil, store.ErrGetLogsTimeout
+	default:
+	}
+
+	_, span := tracing.Start(ctx, "sqlite.FindLogs", tracing.AttrDbTable.String(log{}.TableName()))
+
+	logs, err := filter.Find(ls.db)
+	tracing.End(span, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// convert to common store log
+	result := make([]*store.Log, 0, len(logs))
+	for _, v := range logs {
+		result = append(result, (*store.Log)(v))
+	}
+
+	return result, nil
+}
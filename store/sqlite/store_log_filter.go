@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"github.com/scroll-tech/rpc-gateway/store"
+	"gorm.io/gorm"
+)
+
+type logColumnType int
+
+const (
+	logColumnTypeContract logColumnType = 0
+	logColumnTypeTopic0   logColumnType = 1
+	logColumnTypeTopic1   logColumnType = 2
+	logColumnTypeTopic2   logColumnType = 3
+	logColumnTypeTopic3   logColumnType = 4
+)
+
+var logWhereQueries = map[logColumnType]struct{ single, multiple string }{
+	logColumnTypeContract: {"contract_address = ?", "contract_address IN (?)"},
+	logColumnTypeTopic0:   {"topic0 = ?", "topic0 IN (?)"},
+	logColumnTypeTopic1:   {"topic1 = ?", "topic1 IN (?)"},
+	logColumnTypeTopic2:   {"topic2 = ?", "topic2 IN (?)"},
+	logColumnTypeTopic3:   {"topic3 = ?", "topic3 IN (?)"},
+}
+
+func applyVariadicFilter(db *gorm.DB, column logColumnType, value store.VariadicValue) *gorm.DB {
+	if single, ok := value.Single(); ok {
+		return db.Where(logWhereQueries[column].single, single)
+	}
+
+	if multiple, ok := value.FlatMultiple(); ok {
+		return db.Where(logWhereQueries[column].multiple, multiple)
+	}
+
+	return db
+}
+
+func applyTopicsFilter(db *gorm.DB, topics []store.VariadicValue) *gorm.DB {
+	numTopics := len(topics)
+
+	if numTopics > 0 {
+		db = applyVariadicFilter(db, logColumnTypeTopic0, topics[0])
+	}
+
+	if numTopics > 1 {
+		db = applyVariadicFilter(db, logColumnTypeTopic1, topics[1])
+	}
+
+	if numTopics > 2 {
+		db = applyVariadicFilter(db, logColumnTypeTopic2, topics[2])
+	}
+
+	if numTopics > 3 {
+		db = applyVariadicFilter(db, logColumnTypeTopic3, topics[3])
+	}
+
+	return db
+}
+
+// LogFilter is used to query event logs with specified block number range, contracts and topics.
+type LogFilter struct {
+	// always indexed by block number
+	BlockFrom uint64
+	BlockTo   uint64
+
+	// optional contract ids
+	ContractIds []uint64
+
+	// event hash and indexed data 1, 2, 3
+	Topics []store.VariadicValue
+}
+
+func (filter *LogFilter) Find(db *gorm.DB) ([]*log, error) {
+	db = db.Model(&log{}).Where("bn BETWEEN ? AND ?", filter.BlockFrom, filter.BlockTo)
+
+	if len(filter.ContractIds) == 1 {
+		db = db.Where("cid = ?", filter.ContractIds[0])
+	} else if len(filter.ContractIds) > 1 {
+		db = db.Where("cid IN ?", filter.ContractIds)
+	}
+
+	db = applyTopicsFilter(db, filter.Topics)
+	db = db.Order("bn ASC, log_index ASC").Limit(int(store.MaxLogLimit) + 1)
+
+	var result []*log
+	if err := db.Find(&result).Error; err != nil {
+		return nil, err
+	}
+
+	if len(result) > int(store.MaxLogLimit) {
+		return nil, store.ErrGetLogsResultSetTooLarge
+	}
+
+	return result, nil
+}
@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
+	"gorm.io/gorm"
+)
+
+// config name prefix to record the epoch until which epoch data dequeued
+const dequeuedEpochConfPrefix = "dequeued.epoch."
+
+// tables of epoch data, which are flushed altogether
+var epochDataTables = []string{"blocks", "txs", "logs", "epoch_block_map"}
+
+// GetBlockEpochRange returns the epoch range of persisted blocks.
+func (ss *SqliteStore) GetBlockEpochRange() (uint64, uint64, error) {
+	return ss.epochRange(store.EpochBlock)
+}
+
+// GetTransactionEpochRange returns the epoch range of persisted transactions.
+func (ss *SqliteStore) GetTransactionEpochRange() (uint64, uint64, error) {
+	return ss.epochRange(store.EpochTransaction)
+}
+
+// GetLogEpochRange returns the epoch range of persisted event logs.
+func (ss *SqliteStore) GetLogEpochRange() (uint64, uint64, error) {
+	return ss.epochRange(store.EpochLog)
+}
+
+// GetGlobalEpochRange returns the epoch range of all synchronized epochs.
+func (ss *SqliteStore) GetGlobalEpochRange() (uint64, uint64, error) {
+	return ss.epochRange(store.EpochDataNil)
+}
+
+// epochRange returns the epoch range of the specified epoch data type, which starts from the
+// next epoch of the dequeued one if any, or returns `store.ErrNotFound` if no epoch available.
+func (ss *SqliteStore) epochRange(dt store.EpochDataType) (uint64, uint64, error) {
+	minEpoch, ok, err := ss.MinEpoch()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !ok {
+		return 0, 0, store.ErrNotFound
+	}
+
+	maxEpoch, _, err := ss.MaxEpoch()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if dt == store.EpochDataNil {
+		return minEpoch, maxEpoch, nil
+	}
+
+	dequeued, ok, err := ss.dequeuedEpoch(ss.db, dt)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if ok && dequeued >= minEpoch {
+		minEpoch = dequeued + 1
+	}
+
+	if minEpoch > maxEpoch {
+		return 0, 0, store.ErrNotFound
+	}
+
+	return minEpoch, maxEpoch, nil
+}
+
+func (ss *SqliteStore) dequeuedEpoch(db *gorm.DB, dt store.EpochDataType) (uint64, bool, error) {
+	confs, err := gormstore.NewConfStore(db).LoadConfig(dequeuedEpochConfPrefix + dt.Name())
+	if err != nil {
+		return 0, false, err
+	}
+
+	val, ok := confs[dequeuedEpochConfPrefix+dt.Name()]
+	if !ok {
+		return 0, false, nil
+	}
+
+	epoch, err := strconv.ParseUint(val.(string), 10, 64)
+	if err != nil {
+		return 0, false, errors.WithMessage(err, "malformed dequeued epoch")
+	}
+
+	return epoch, true, nil
+}
+
+func (ss *SqliteStore) GetNumBlocks() (uint64, error) {
+	return ss.count("blocks")
+}
+
+func (ss *SqliteStore) GetNumTransactions() (uint64, error) {
+	return ss.count("txs")
+}
+
+func (ss *SqliteStore) GetNumLogs() (uint64, error) {
+	return ss.count("logs")
+}
+
+func (ss *SqliteStore) count(table string) (uint64, error) {
+	var count int64
+	if err := ss.db.Table(table).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return uint64(count), nil
+}
+
+func (ss *SqliteStore) DequeueBlocks(epochUntil uint64) error {
+	return ss.dequeue(store.EpochBlock, ss.BlockStore.Remove, epochUntil)
+}
+
+func (ss *SqliteStore) DequeueTransactions(epochUntil uint64) error {
+	return ss.dequeue(store.EpochTransaction, ss.TxStore.Remove, epochUntil)
+}
+
+func (ss *SqliteStore) DequeueLogs(epochUntil uint64) error {
+	return ss.dequeue(store.EpochLog, ss.ls.Remove, epochUntil)
+}
+
+// dequeue removes epoch data until the specified epoch, and records the dequeued epoch.
+func (ss *SqliteStore) dequeue(
+	dt store.EpochDataType, remove func(dbTx *gorm.DB, epochFrom, epochTo uint64) error, epochUntil uint64,
+) error {
+	return ss.db.Transaction(func(dbTx *gorm.DB) error {
+		dequeued, ok, err := ss.dequeuedEpoch(dbTx, dt)
+		if err != nil {
+			return err
+		}
+
+		if ok && epochUntil <= dequeued { // already dequeued
+			return nil
+		}
+
+		if err := remove(dbTx, 0, epochUntil); err != nil {
+			return err
+		}
+
+		return gormstore.NewConfStore(dbTx).StoreConfig(dequeuedEpochConfPrefix+dt.Name(), strconv.FormatUint(epochUntil, 10))
+	})
+}
+
+// Flush removes all the persisted chain data, along with the dequeued epochs.
+func (ss *SqliteStore) Flush() error {
+	return ss.db.Transaction(func(dbTx *gorm.DB) error {
+		for _, table := range epochDataTables {
+			if err := dbTx.Exec(fmt.Sprintf("DELETE FROM %v", table)).Error; err != nil {
+				return err
+			}
+		}
+
+		pattern := fmt.Sprintf("%v%%", dequeuedEpochConfPrefix)
+		return dbTx.Exec("DELETE FROM configs WHERE name LIKE ?", pattern).Error
+	})
+}
@@ -0,0 +1,161 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/scroll-tech/rpc-gateway/store/gormstore"
+	"github.com/scroll-tech/rpc-gateway/store/storetest"
+	"github.com/scroll-tech/rpc-gateway/util/rate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDisabler persists event logs only.
+type testDisabler struct{}
+
+func (testDisabler) IsChainBlockDisabled() bool                     { return true }
+func (testDisabler) IsChainTxnDisabled() bool                       { return true }
+func (testDisabler) IsChainReceiptDisabled() bool                   { return true }
+func (testDisabler) IsChainLogDisabled() bool                       { return false }
+func (testDisabler) IsDisabledForType(edt store.EpochDataType) bool { return edt != store.EpochLog }
+
+// testFullDisabler persists all chain data.
+type testFullDisabler struct{}
+
+func (testFullDisabler) IsChainBlockDisabled() bool                     { return false }
+func (testFullDisabler) IsChainTxnDisabled() bool                       { return false }
+func (testFullDisabler) IsChainReceiptDisabled() bool                   { return false }
+func (testFullDisabler) IsChainLogDisabled() bool                       { return false }
+func (testFullDisabler) IsDisabledForType(edt store.EpochDataType) bool { return false }
+
+func mustNewTestStore(t *testing.T, disabler ...store.StoreDisabler) *SqliteStore {
+	var option StoreOption
+	if len(disabler) > 0 {
+		option.Disabler = disabler[0]
+	} else {
+		option.Disabler = testDisabler{}
+	}
+
+	config := Config{
+		Path:        filepath.Join(t.TempDir(), "confura.db"),
+		BusyTimeout: 5 * time.Second,
+	}
+
+	ss := config.MustOpenOrCreate(option)
+	t.Cleanup(func() { ss.Close() })
+
+	return ss
+}
+
+func TestSqliteStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.DBStore {
+		return mustNewTestStore(t)
+	})
+}
+
+func TestSqliteStoreConfigs(t *testing.T) {
+	ss := mustNewTestStore(t)
+
+	// rate limit
+	require.NoError(t, ss.StoreConfig("ratelimit.strategy.whitelist", `{"cfx_getLogs":[10,20]}`))
+	require.NoError(t, ss.db.Create(&gormstore.RateLimit{SID: 1, LimitKey: "key1"}).Error)
+
+	rateCfg := ss.LoadRateLimitConfigs()
+	if assert.Equal(t, 1, len(rateCfg.Strategies)) {
+		for _, strategy := range rateCfg.Strategies {
+			assert.Equal(t, "whitelist", strategy.Name)
+		}
+	}
+
+	keyInfos, err := ss.LoadRateLimitKeyset(&rate.KeysetFilter{KeySet: []string{"key1"}})
+	require.NoError(t, err)
+	if assert.Equal(t, 1, len(keyInfos)) {
+		assert.Equal(t, uint32(1), keyInfos[0].SID)
+	}
+
+	// vip users
+	require.NoError(t, ss.db.Create(&store.User{Name: "vip", ApiKey: "apikey", NodeUrl: "http://127.0.0.1"}).Error)
+
+	user, ok, err := ss.GetUserByKey("apikey")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "vip", user.Name)
+}
+
+func TestSqliteStorePrunable(t *testing.T) {
+	ss := mustNewTestStore(t)
+
+	for i := uint64(1); i <= 3; i++ {
+		require.NoError(t, ss.Push(storetest.NewEpochData(i, storetest.TestContract1)))
+	}
+
+	numLogs, err := ss.GetNumLogs()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), numLogs)
+
+	require.NoError(t, ss.DequeueLogs(1))
+
+	epochFrom, epochTo, err := ss.GetLogEpochRange()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), epochFrom)
+	assert.Equal(t, uint64(3), epochTo)
+
+	// global epoch range is not affected by dequeue
+	epochFrom, _, err = ss.GetGlobalEpochRange()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), epochFrom)
+
+	// event logs already dequeued
+	_, err = ss.GetLogs(context.Background(), store.LogFilter{BlockFrom: 1, BlockTo: 3})
+	assert.Equal(t, store.ErrAlreadyPruned, err)
+
+	require.NoError(t, ss.Flush())
+
+	_, _, err = ss.GetGlobalEpochRange()
+	assert.True(t, ss.IsRecordNotFound(err))
+
+	numLogs, err = ss.GetNumLogs()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), numLogs)
+}
+
+func TestSqliteStoreBlockTransactions(t *testing.T) {
+	ss := mustNewTestStore(t, testFullDisabler{})
+	ctx := context.Background()
+
+	data := storetest.NewEpochData(1, storetest.TestContract1)
+	require.NoError(t, ss.Push(data))
+
+	blockHash := data.GetPivotBlock().Hash
+	txHash := data.GetPivotBlock().Transactions[0].Hash
+
+	count, err := ss.GetBlockTransactionCountByHash(ctx, blockHash)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	count, err = ss.GetBlockTransactionCountByNumber(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	tx, err := ss.GetTransactionByBlockNumberAndIndex(ctx, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, txHash, tx.CfxTransaction.Hash)
+
+	// index out of range
+	tx, err = ss.GetTransactionByBlockHashAndIndex(ctx, blockHash, 1)
+	require.NoError(t, err)
+	assert.Nil(t, tx)
+
+	receipts, err := ss.GetReceiptsByBlockHash(ctx, blockHash)
+	require.NoError(t, err)
+	if assert.Equal(t, 1, len(receipts)) {
+		assert.Equal(t, txHash, receipts[0].CfxReceipt.TransactionHash)
+	}
+
+	_, err = ss.GetReceiptsByBlockNumber(ctx, 2)
+	assert.True(t, ss.IsRecordNotFound(err))
+}
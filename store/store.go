@@ -50,6 +50,13 @@ type Readable interface {
 	GetBlockSummaryByHash(ctx context.Context, blockHash types.Hash) (*BlockSummary, error)
 	GetBlockByBlockNumber(ctx context.Context, blockNumber uint64) (*Block, error)
 	GetBlockSummaryByBlockNumber(ctx context.Context, blockNumber uint64) (*BlockSummary, error)
+
+	GetBlockTransactionCountByHash(ctx context.Context, blockHash types.Hash) (uint64, error)
+	GetBlockTransactionCountByNumber(ctx context.Context, blockNumber uint64) (uint64, error)
+	GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash types.Hash, index uint32) (*Transaction, error)
+	GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNumber uint64, index uint32) (*Transaction, error)
+	GetReceiptsByBlockHash(ctx context.Context, blockHash types.Hash) ([]*TransactionReceipt, error)
+	GetReceiptsByBlockNumber(ctx context.Context, blockNumber uint64) ([]*TransactionReceipt, error)
 }
 
 type Configurable interface {
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"io"
+	"time"
+
+	citypes "github.com/scroll-tech/rpc-gateway/types"
+	"github.com/scroll-tech/rpc-gateway/util/rate"
+)
+
+// DBStore is implemented by any relational database backend (e.g., MySQL or PostgreSQL), which
+// persists chain data along with epoch to block mappings, rate limit settings, VIP users and
+// transactions submitted via gateway.
+type DBStore interface {
+	Readable
+	Configurable
+	StackOperable
+	io.Closer
+
+	IsRecordNotFound(err error) bool
+
+	// MaxEpoch returns the max epoch persisted in the store
+	MaxEpoch() (uint64, bool, error)
+	// BlockRange returns the spanning block range for the given epoch
+	BlockRange(epoch uint64) (citypes.RangeUint64, bool, error)
+	// PivotHash returns the pivot block hash of the given epoch
+	PivotHash(epoch uint64) (string, bool, error)
+	// GetReorgVersion returns the reorg version, which is increased whenever data popped
+	GetReorgVersion() (int, error)
+
+	// LoadRateLimitConfigs loads rate limit strategies
+	LoadRateLimitConfigs() *rate.Config
+	// LoadRateLimitKeyset loads rate limit keyset with the specified filter
+	LoadRateLimitKeyset(filter *rate.KeysetFilter) ([]*rate.KeyInfo, error)
+
+	UserStore
+	TxSubmissionStore
+
+	// Prune schedules to prune archive data periodically in the background
+	Prune()
+}
+
+// UserStore is used to get VIP users.
+type UserStore interface {
+	GetUserByKey(key string) (*User, bool, error)
+}
+
+// User represents a VIP user that provide specific archive node to query historical
+// event logs.
+type User struct {
+	ID          uint32
+	Name        string `gorm:"size:256;not null;unique"`
+	Description string `gorm:"size:1024"`
+	ApiKey      string `gorm:"size:256;not null;unique"`
+	NodeUrl     string `gorm:"size:256;not null"`
+}
+
+func (User) TableName() string {
+	return "users"
+}
+
+// TxSubmissionStore is used to track the status of raw transactions submitted via gateway.
+type TxSubmissionStore interface {
+	// AddTxSubmission adds the submitted transaction, or does nothing if already exists
+	AddTxSubmission(sub *TxSubmission) error
+	// UpdateTxSubmission updates the tracked status of the submitted transaction
+	UpdateTxSubmission(sub *TxSubmission) error
+	// GetTxSubmission returns the submitted transaction by hash
+	GetTxSubmission(hash string) (*TxSubmission, bool, error)
+	// GetTxSubmissionsByNonce returns the submitted transactions of the same sender and nonce
+	GetTxSubmissionsByNonce(sender string, nonce uint64) ([]*TxSubmission, error)
+	// GetTrackingTxSubmissions returns the submitted transactions not concluded yet, which are
+	// ordered by the last checked time so that the least recently checked ones come first
+	GetTrackingTxSubmissions(limit int) ([]*TxSubmission, error)
+}
+
+// TxStatus is the tracked status of transaction submitted via gateway.
+type TxStatus string
+
+const (
+	// TxStatusSubmitted transaction accepted by fullnode, but not seen in the pool yet
+	TxStatusSubmitted TxStatus = "submitted"
+	// TxStatusPending transaction seen in the pool of fullnode
+	TxStatusPending TxStatus = "pending"
+	// TxStatusMined transaction included in block
+	TxStatusMined TxStatus = "mined"
+	// TxStatusDropped transaction disappeared from the pool and given up rebroadcasting
+	TxStatusDropped TxStatus = "dropped"
+	// TxStatusReplaced transaction nonce consumed by another transaction of the same sender
+	TxStatusReplaced TxStatus = "replaced"
+)
+
+// Concluded returns true if the status is final and no longer tracked.
+func (s TxStatus) Concluded() bool {
+	return s == TxStatusMined || s == TxStatusDropped || s == TxStatusReplaced
+}
+
+// TxSubmission represents a raw transaction submitted via gateway, which is tracked until mined,
+// dropped or replaced.
+type TxSubmission struct {
+	ID     uint64
+	Hash   string   `gorm:"size:66;not null;unique"`
+	Sender string   `gorm:"size:42;not null;index:idx_txsub_sender_nonce,priority:1"`
+	Nonce  uint64   `gorm:"not null;index:idx_txsub_sender_nonce,priority:2"`
+	RawTx  []byte   `gorm:"not null"`
+	Nodes  string   `gorm:"size:1024;not null"` // comma separated names of fullnodes sent to
+	Status TxStatus `gorm:"size:16;not null;index:idx_txsub_status_checked,priority:1"`
+
+	Rebroadcasts int    `gorm:"not null;default:0"`
+	BlockNumber  uint64 `gorm:"not null;default:0"` // block number if mined
+	ReplacedBy   string `gorm:"size:66"`            // hash of the replacing transaction if known
+
+	SubmittedAt time.Time  `gorm:"not null"`
+	BroadcastAt time.Time  `gorm:"not null"` // last time sent to fullnode
+	CheckedAt   time.Time  `gorm:"not null;index:idx_txsub_status_checked,priority:2"`
+	PendingAt   *time.Time // first time seen in the pool
+	ConcludedAt *time.Time // time of being mined, dropped or replaced
+}
+
+func (TxSubmission) TableName() string {
+	return "tx_submissions"
+}
@@ -0,0 +1,169 @@
+// Package storetest provides the behavioural tests that any relational database backend of
+// store.DBStore must pass, so that backends are interchangeable.
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/rpc-gateway/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	TestContract1 = cfxaddress.MustNewFromHex("0x8b8689c7f3014a4d86e4d1d0daaf74a47f5e0f27", 1)
+	TestContract2 = cfxaddress.MustNewFromHex("0x85b1432b900ec2552a3f1f6fdc94b2c3a63a8a21", 1)
+
+	TestTopic = types.Hash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+)
+
+// NewEpochData creates epoch data with a pivot block, which contains one executed
+// transaction emitting one event log for the specified contract.
+func NewEpochData(epoch uint64, contract types.Address) *store.EpochData {
+	blockHash := types.Hash(fmt.Sprintf("0x%064x", epoch))
+	txHash := types.Hash(fmt.Sprintf("0x%064x", epoch+1_000_000))
+	epochNum := hexutil.Uint64(epoch)
+	status := hexutil.Uint64(0)
+
+	block := types.Block{
+		BlockHeader: types.BlockHeader{
+			Hash:        blockHash,
+			BlockNumber: (*hexutil.Big)(new(big.Int).SetUint64(epoch)),
+			EpochNumber: (*hexutil.Big)(new(big.Int).SetUint64(epoch)),
+			Miner:       contract,
+		},
+		Transactions: []types.Transaction{
+			{Hash: txHash, BlockHash: &blockHash, Status: &status, From: contract, To: &contract},
+		},
+	}
+
+	receipt := types.TransactionReceipt{
+		TransactionHash: txHash,
+		BlockHash:       blockHash,
+		EpochNumber:     &epochNum,
+		From:            contract,
+		To:              &contract,
+		Logs: []types.Log{{
+			Address:         contract,
+			Topics:          []types.Hash{TestTopic},
+			BlockHash:       &blockHash,
+			EpochNumber:     (*hexutil.Big)(new(big.Int).SetUint64(epoch)),
+			TransactionHash: &txHash,
+			LogIndex:        (*hexutil.Big)(big.NewInt(0)),
+		}},
+	}
+
+	return &store.EpochData{
+		Number:   epoch,
+		Blocks:   []*types.Block{&block},
+		Receipts: map[types.Hash]*types.TransactionReceipt{txHash: &receipt},
+	}
+}
+
+// Run runs the behavioural tests against the store created by the specified factory, which is
+// called once per test with event logs persisted at least. The factory should register cleanup
+// on the passed-in test, e.g. to drop the database tables.
+func Run(t *testing.T, newStore func(t *testing.T) store.DBStore) {
+	t.Run("PushPop", func(t *testing.T) { testPushPop(t, newStore(t)) })
+	t.Run("Configs", func(t *testing.T) { testConfigs(t, newStore(t)) })
+}
+
+func testPushPop(t *testing.T, s store.DBStore) {
+	ctx := context.Background()
+
+	var dataSlice []*store.EpochData
+	for i := uint64(1); i <= 3; i++ {
+		contract := TestContract1
+		if i%2 == 0 {
+			contract = TestContract2
+		}
+
+		dataSlice = append(dataSlice, NewEpochData(i, contract))
+	}
+
+	require.NoError(t, s.Pushn(dataSlice))
+
+	// epoch data must be continuous
+	assert.ErrorIs(t, s.Push(NewEpochData(5, TestContract1)), store.ErrContinousEpochRequired)
+
+	maxEpoch, ok, err := s.MaxEpoch()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3), maxEpoch)
+
+	pivotHash, ok, err := s.PivotHash(2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, dataSlice[1].GetPivotBlock().Hash.String(), pivotHash)
+
+	// universal event logs
+	logs, err := s.GetLogs(ctx, store.LogFilter{BlockFrom: 1, BlockTo: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(logs))
+
+	// address indexed event logs
+	logs, err = s.GetLogs(ctx, store.LogFilter{
+		BlockFrom: 1,
+		BlockTo:   3,
+		Contracts: store.NewVariadicValue(TestContract1.MustGetBase32Address()),
+		Topics:    []store.VariadicValue{store.NewVariadicValue(TestTopic.String())},
+	})
+	require.NoError(t, err)
+	if assert.Equal(t, 2, len(logs)) {
+		assert.Equal(t, uint64(1), logs[0].BlockNumber)
+		assert.Equal(t, uint64(3), logs[1].BlockNumber)
+	}
+
+	// event logs before the first synchronized block
+	_, err = s.GetLogs(ctx, store.LogFilter{BlockFrom: 0, BlockTo: 3})
+	assert.Equal(t, store.ErrAlreadyPruned, err)
+
+	// pop the latest 2 epochs
+	require.NoError(t, s.Popn(2))
+
+	maxEpoch, _, err = s.MaxEpoch()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), maxEpoch)
+
+	logs, err = s.GetLogs(ctx, store.LogFilter{BlockFrom: 1, BlockTo: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(logs))
+
+	logs, err = s.GetLogs(ctx, store.LogFilter{
+		BlockFrom: 1,
+		BlockTo:   3,
+		Contracts: store.NewVariadicValue(TestContract2.MustGetBase32Address()),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(logs))
+
+	reorgVersion, err := s.GetReorgVersion()
+	require.NoError(t, err)
+	assert.Equal(t, 1, reorgVersion)
+
+	// push again after popped
+	require.NoError(t, s.Pushn(dataSlice[1:]))
+
+	logs, err = s.GetLogs(ctx, store.LogFilter{BlockFrom: 1, BlockTo: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(logs))
+}
+
+func testConfigs(t *testing.T, s store.DBStore) {
+	require.NoError(t, s.StoreConfig("foo", "bar"))
+	require.NoError(t, s.StoreConfig("foo", "baz")) // upsert
+
+	confs, err := s.LoadConfig("foo", "nonexistent")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": "baz"}, confs)
+
+	_, ok, err := s.GetUserByKey("apikey")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/Conflux-Chain/go-conflux-sdk/types"
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/store"
-	"github.com/scroll-tech/rpc-gateway/store/mysql"
 	citypes "github.com/scroll-tech/rpc-gateway/types"
 	"github.com/scroll-tech/rpc-gateway/util"
 	"github.com/sirupsen/logrus"
@@ -23,7 +22,7 @@ func ensureStoreEpochDataOk(cfx sdk.ClientOperator, s store.StackOperable) error
 	var maxEpoch uint64
 	var err error
 
-	if ms, ok := s.(*mysql.MysqlStore); ok {
+	if ms, ok := s.(store.DBStore); ok {
 		maxEpoch, ok, err = ms.MaxEpoch()
 		if err == nil && !ok { // no epoch data existed yet
 			return nil
@@ -148,7 +147,7 @@ func findFirstRevertedEpochInRange(
 func checkIfEpochIsReverted(
 	cfx sdk.ClientOperator, s store.StackOperable, epochNo uint64,
 ) (res bool, err error) {
-	if ms, ok := s.(*mysql.MysqlStore); ok {
+	if ms, ok := s.(store.DBStore); ok {
 		pivotHash, ok, err := ms.PivotHash(epochNo)
 		if err != nil {
 			return false, errors.WithMessage(err, "failed to get epoch pivot hash")
@@ -11,7 +11,6 @@ import (
 	viperutil "github.com/Conflux-Chain/go-conflux-util/viper"
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/store"
-	"github.com/scroll-tech/rpc-gateway/store/mysql"
 	"github.com/scroll-tech/rpc-gateway/sync/catchup"
 	citypes "github.com/scroll-tech/rpc-gateway/types"
 	"github.com/scroll-tech/rpc-gateway/util"
@@ -43,7 +42,7 @@ type DatabaseSyncer struct {
 	// conflux sdk client
 	cfx sdk.ClientOperator
 	// db store
-	db *mysql.MysqlStore
+	db store.DBStore
 	// epoch number to sync data from
 	epochFrom uint64
 	// maximum number of epochs to sync once
@@ -65,7 +64,7 @@ type DatabaseSyncer struct {
 }
 
 // MustNewDatabaseSyncer creates an instance of DatabaseSyncer to sync blockchain data.
-func MustNewDatabaseSyncer(cfx sdk.ClientOperator, db *mysql.MysqlStore) *DatabaseSyncer {
+func MustNewDatabaseSyncer(cfx sdk.ClientOperator, db store.DBStore) *DatabaseSyncer {
 	var conf syncConfig
 	viperutil.MustUnmarshalKey("sync", &conf)
 
@@ -2,18 +2,23 @@ package sync
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
 	"sync"
 	"time"
 
 	cfxtypes "github.com/Conflux-Chain/go-conflux-sdk/types"
 	viperutil "github.com/Conflux-Chain/go-conflux-util/viper"
 	"github.com/openweb3/web3go"
+	web3Types "github.com/openweb3/web3go/types"
 	"github.com/pkg/errors"
 	"github.com/scroll-tech/rpc-gateway/rpc/cfxbridge"
+	"github.com/scroll-tech/rpc-gateway/rpc/ethbridge"
 	"github.com/scroll-tech/rpc-gateway/store"
-	"github.com/scroll-tech/rpc-gateway/store/mysql"
 	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/scroll-tech/rpc-gateway/util/finality"
 	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/scroll-tech/rpc-gateway/util/pubsub"
 	"github.com/sirupsen/logrus"
 )
 
@@ -27,6 +32,8 @@ type syncEthConfig struct {
 	FromBlock uint64 `default:"1"`
 	MaxBlocks uint64 `default:"10"`
 	UseBatch  bool   `default:"false"`
+	// whether to sync finalized blocks only, so that synced data never reverted by chain reorg
+	FinalizedOnly bool
 }
 
 // EthSyncer is used to synchronize evm space blockchain data into db store.
@@ -37,7 +44,7 @@ type EthSyncer struct {
 	// EVM space chain id
 	chainId uint32
 	// db store
-	db *mysql.MysqlStore
+	db store.DBStore
 	// block number to sync chaindata from
 	fromBlock uint64
 	// maximum number of blocks to sync once
@@ -48,10 +55,13 @@ type EthSyncer struct {
 	syncIntervalCatchUp time.Duration
 	// window to cache block info
 	epochPivotWin *epochPivotWindow
+	// broker to publish synced or reverted event logs, nil if not configured
+	broker pubsub.Broker
 }
 
 // MustNewEthSyncer creates an instance of EthSyncer to sync Conflux EVM space chaindata.
-func MustNewEthSyncer(ethC *web3go.Client, db *mysql.MysqlStore) *EthSyncer {
+// Event logs synced or reverted will be published to the broker if provided.
+func MustNewEthSyncer(ethC *web3go.Client, db store.DBStore, broker ...pubsub.Broker) *EthSyncer {
 	ethChainId, err := ethC.Eth.ChainId()
 	if err != nil {
 		logrus.WithError(err).Fatal("Failed to get chain ID from eth space")
@@ -71,6 +81,10 @@ func MustNewEthSyncer(ethC *web3go.Client, db *mysql.MysqlStore) *EthSyncer {
 		epochPivotWin:       newEpochPivotWindow(syncPivotInfoWinCapacity),
 	}
 
+	if len(broker) > 0 {
+		syncer.broker = broker[0]
+	}
+
 	// Load last sync block information
 	syncer.mustLoadLastSyncBlock()
 
@@ -129,7 +143,11 @@ func (syncer *EthSyncer) syncOnce() (bool, error) {
 	}
 
 	recentBlockNo := recentBlockNumber.Uint64()
-	if recentBlockNo > skipBlocksAheadLatest {
+	if syncer.conf.FinalizedOnly {
+		if recentBlockNo, err = syncer.finalizedBlock(); err != nil {
+			return false, errors.WithMessage(err, "failed to query the finalized block number")
+		}
+	} else if recentBlockNo > skipBlocksAheadLatest {
 		recentBlockNo = recentBlockNo - skipBlocksAheadLatest
 	}
 
@@ -255,6 +273,9 @@ func (syncer *EthSyncer) syncOnce() (bool, error) {
 
 	syncer.fromBlock += uint64(len(ethDataSlice))
 
+	// notify subscribers of the new synced event logs
+	syncer.publishLogs(syncer.collectLogs(ethDataSlice))
+
 	logger.WithFields(logrus.Fields{
 		"newSyncFrom":   syncer.fromBlock,
 		"finalSyncSize": len(ethDataSlice),
@@ -263,6 +284,24 @@ func (syncer *EthSyncer) syncOnce() (bool, error) {
 	return false, nil
 }
 
+// finalizedBlock returns the finalized block number by the tracked chain finality if any,
+// otherwise by fullnode.
+func (syncer *EthSyncer) finalizedBlock() (uint64, error) {
+	if blockNum, ok := finality.Finalized(); ok {
+		return blockNum, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), syncer.syncIntervalNormal)
+	defer cancel()
+
+	blockNum, err := finality.QueryHead(ctx, syncer.w3c, finality.TagFinalized)
+	if err == nil && blockNum == 0 {
+		err = errors.New("finalized block not available")
+	}
+
+	return blockNum, err
+}
+
 func (syncer *EthSyncer) reorgRevert(revertTo uint64) error {
 	if revertTo == 0 {
 		return errors.New("genesis block must not be reverted")
@@ -279,6 +318,9 @@ func (syncer *EthSyncer) reorgRevert(revertTo uint64) error {
 		return nil
 	}
 
+	// load the event logs to be reverted before popped from database
+	revertedLogs := syncer.loadRevertedLogs(revertTo)
+
 	// remove block data from database due to chain re-org
 	if err := syncer.db.Popn(revertTo); err != nil {
 		logger.WithError(err).Error(
@@ -293,6 +335,11 @@ func (syncer *EthSyncer) reorgRevert(revertTo uint64) error {
 	// update syncer start block
 	syncer.fromBlock = revertTo
 
+	// notify subscribers of the reverted event logs again with `removed` marked
+	syncer.publishLogs(revertedLogs)
+	// notify subscribers of the chain reorg, e.g. to invalidate cached RPC responses
+	syncer.publishReorg(revertTo)
+
 	logger.Info("ETH syncer reverted block data due to chain re-org")
 	return nil
 }
@@ -372,3 +419,101 @@ func (syncer *EthSyncer) latestStoreBlock() uint64 {
 
 	return 0
 }
+
+// collectLogs collects event logs of the synced eth data in the order of block, transaction and log index.
+func (syncer *EthSyncer) collectLogs(ethDataSlice []*store.EthData) (logs []web3Types.Log) {
+	for _, data := range ethDataSlice {
+		txns := data.Block.Transactions.Transactions()
+
+		for i := range txns {
+			rcpt, ok := data.Receipts[txns[i].Hash]
+			if !ok {
+				continue
+			}
+
+			for _, log := range rcpt.Logs {
+				logs = append(logs, *log)
+			}
+		}
+	}
+
+	return logs
+}
+
+// loadRevertedLogs loads event logs from the db store for the blocks to be reverted, with
+// `removed` field marked as true.
+func (syncer *EthSyncer) loadRevertedLogs(revertTo uint64) []web3Types.Log {
+	if syncer.broker == nil || store.EthStoreConfig().IsChainLogDisabled() {
+		return nil
+	}
+
+	logger := logrus.WithFields(logrus.Fields{
+		"revertTo": revertTo, "revertFrom": syncer.latestStoreBlock(),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.TimeoutGetLogs)
+	defer cancel()
+
+	dbLogs, err := syncer.db.GetLogs(ctx, store.LogFilter{
+		BlockFrom: revertTo, BlockTo: syncer.latestStoreBlock(),
+	})
+	if err != nil {
+		logger.WithError(err).Warn("ETH syncer failed to load event logs to be reverted from ethdb")
+		return nil
+	}
+
+	sort.Sort(store.LogSlice(dbLogs))
+
+	logs := make([]web3Types.Log, 0, len(dbLogs))
+	for _, v := range dbLogs {
+		cfxLog, ext := v.ToCfxLog()
+
+		log := ethbridge.ConvertLog(cfxLog, ext)
+		log.Removed = true
+
+		logs = append(logs, *log)
+	}
+
+	return logs
+}
+
+// publishLogs publishes event logs to the broker if configured.
+func (syncer *EthSyncer) publishLogs(logs []web3Types.Log) {
+	if syncer.broker == nil || len(logs) == 0 {
+		return
+	}
+
+	msg, err := json.Marshal(logs)
+	if err == nil {
+		err = syncer.broker.Publish(pubsub.TopicEthLogs, msg)
+	}
+
+	if err != nil {
+		logrus.WithField("numLogs", len(logs)).
+			WithError(err).
+			Error("ETH syncer failed to publish event logs")
+	}
+}
+
+// publishReorg publishes chain reorg to the broker if configured.
+func (syncer *EthSyncer) publishReorg(revertTo uint64) {
+	if syncer.broker == nil {
+		return
+	}
+
+	reorgVersion, err := syncer.db.GetReorgVersion()
+	if err != nil {
+		logrus.WithError(err).Info("ETH syncer failed to get reorg version from ethdb")
+	}
+
+	msg, err := json.Marshal(pubsub.ReorgMessage{RevertTo: revertTo, ReorgVersion: reorgVersion})
+	if err == nil {
+		err = syncer.broker.Publish(pubsub.TopicEthReorg, msg)
+	}
+
+	if err != nil {
+		logrus.WithField("revertTo", revertTo).
+			WithError(err).
+			Error("ETH syncer failed to publish chain reorg")
+	}
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TransactionStatus is the tracked status of transaction submitted via gateway.
+type TransactionStatus struct {
+	Hash         common.Hash     `json:"hash"`
+	Status       string          `json:"status"`                // submitted, pending, mined, dropped or replaced
+	Nodes        []string        `json:"nodes"`                 // fullnodes that transaction sent to
+	Rebroadcasts int             `json:"rebroadcasts"`          // times rebroadcasted after disappeared from pool
+	BlockNumber  *hexutil.Uint64 `json:"blockNumber,omitempty"` // block number if mined
+	ReplacedBy   *common.Hash    `json:"replacedBy,omitempty"`  // hash of the replacing transaction if known
+
+	SubmittedAt time.Time  `json:"submittedAt"`
+	BroadcastAt time.Time  `json:"broadcastAt"`           // last time sent to fullnode
+	PendingAt   *time.Time `json:"pendingAt,omitempty"`   // first time seen in pool
+	ConcludedAt *time.Time `json:"concludedAt,omitempty"` // time of being mined, dropped or replaced
+	CheckedAt   time.Time  `json:"checkedAt"`             // last time status checked
+}
@@ -0,0 +1,223 @@
+package finality
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	viperutil "github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3/web3go"
+	"github.com/pkg/errors"
+	rpcutil "github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	// TagSafe is the block tag of the latest block that is safe from reorgs under honest majority.
+	TagSafe = "safe"
+	// TagFinalized is the block tag of the latest finalized block, e.g. rollup batch finalized
+	// on layer 1.
+	TagFinalized = "finalized"
+)
+
+type config struct {
+	// whether to track the safe and finalized heads of evm space chain
+	Enabled bool
+	// RPC endpoint to poll the safe and finalized heads, e.g. fullnode or rollup node, which
+	// defaults to `eth.http` if empty
+	Url string
+	// interval to poll the safe and finalized heads
+	Interval time.Duration `default:"1s"`
+	// custom RPC methods that return the safe or finalized block (number), otherwise polled by
+	// `eth_getBlockByNumber` with the `safe` or `finalized` block tag
+	SafeMethod      string
+	FinalizedMethod string
+}
+
+// Tracker tracks the safe and finalized heads of evm space chain, which never go backwards.
+type Tracker struct {
+	conf *config
+	w3c  *web3go.Client
+
+	safe      uint64 // 0 if not tracked yet
+	finalized uint64 // 0 if not tracked yet
+}
+
+// defaultTracker is the tracker shared within process, nil if not initialized or disabled.
+var defaultTracker *Tracker
+
+// MustInitFromViper initializes the default tracker if enabled, and polls the safe and finalized
+// heads in the background until the context done.
+func MustInitFromViper(ctx context.Context) {
+	var conf config
+	viperutil.MustUnmarshalKey("eth.finality", &conf)
+
+	if !conf.Enabled || defaultTracker != nil {
+		return
+	}
+
+	if len(conf.Url) == 0 {
+		conf.Url = viper.GetString("eth.http")
+	}
+
+	w3c, err := rpcutil.NewEthClient(conf.Url)
+	if err != nil {
+		logrus.WithError(err).WithField("url", conf.Url).Fatal("Failed to create client for finality tracker")
+	}
+
+	defaultTracker = NewTracker(&conf, w3c)
+	go defaultTracker.Run(ctx)
+
+	logrus.WithField("url", conf.Url).Info("Finality tracker started to poll safe and finalized heads")
+}
+
+func NewTracker(conf *config, w3c *web3go.Client) *Tracker {
+	return &Tracker{conf: conf, w3c: w3c}
+}
+
+// Run polls the safe and finalized heads periodically until the context done.
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.conf.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.poll(); err != nil {
+			logrus.WithError(err).Debug("Finality tracker failed to poll safe and finalized heads")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tracker) poll() error {
+	safe, err := t.queryHead(TagSafe, t.conf.SafeMethod)
+	if err != nil {
+		return errors.WithMessage(err, "failed to query safe head")
+	}
+
+	finalized, err := t.queryHead(TagFinalized, t.conf.FinalizedMethod)
+	if err != nil {
+		return errors.WithMessage(err, "failed to query finalized head")
+	}
+
+	advance(&t.safe, safe)
+	advance(&t.finalized, finalized)
+
+	return nil
+}
+
+func (t *Tracker) queryHead(tag, method string) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), t.conf.Interval)
+	defer cancel()
+
+	if len(method) == 0 {
+		return QueryHead(ctx, t.w3c, tag)
+	}
+
+	var result json.RawMessage
+	if err := t.w3c.Provider().CallContext(ctx, &result, method); err != nil {
+		return 0, err
+	}
+
+	return decodeHead(result)
+}
+
+// QueryHead queries the block number of the specified block tag from fullnode, or 0 if not
+// supported or not available yet.
+func QueryHead(ctx context.Context, w3c *web3go.Client, tag string) (uint64, error) {
+	var result json.RawMessage
+	if err := w3c.Provider().CallContext(ctx, &result, "eth_getBlockByNumber", tag, false); err != nil {
+		return 0, err
+	}
+
+	return decodeHead(result)
+}
+
+// decodeHead decodes the block number from result of custom RPC method, which could be either
+// a block number or a block object.
+func decodeHead(result json.RawMessage) (uint64, error) {
+	if len(result) == 0 || string(result) == "null" {
+		return 0, nil
+	}
+
+	var number hexutil.Big
+	if err := json.Unmarshal(result, &number); err == nil {
+		return number.ToInt().Uint64(), nil
+	}
+
+	var block struct {
+		Number *hexutil.Big `json:"number"`
+	}
+
+	if err := json.Unmarshal(result, &block); err != nil || block.Number == nil {
+		return 0, errors.Errorf("invalid block number or block (%v)", string(result))
+	}
+
+	return block.Number.ToInt().Uint64(), nil
+}
+
+// advance updates the head only if higher, so that the head never goes backwards.
+func advance(head *uint64, value uint64) {
+	for {
+		old := atomic.LoadUint64(head)
+		if value <= old || atomic.CompareAndSwapUint64(head, old, value) {
+			return
+		}
+	}
+}
+
+// Safe returns the safe head, or false if not tracked yet.
+func (t *Tracker) Safe() (uint64, bool) {
+	head := atomic.LoadUint64(&t.safe)
+	return head, head > 0
+}
+
+// Finalized returns the finalized head, or false if not tracked yet.
+func (t *Tracker) Finalized() (uint64, bool) {
+	head := atomic.LoadUint64(&t.finalized)
+	return head, head > 0
+}
+
+// Resolve resolves the `safe` or `finalized` block tag to block number, or returns false if
+// not tracked yet or other block tags.
+func (t *Tracker) Resolve(tag string) (uint64, bool) {
+	switch tag {
+	case TagSafe:
+		return t.Safe()
+	case TagFinalized:
+		return t.Finalized()
+	default:
+		return 0, false
+	}
+}
+
+// Enabled returns true if the default tracker initialized.
+func Enabled() bool {
+	return defaultTracker != nil
+}
+
+// Finalized returns the finalized head of the default tracker, or false if not tracked yet.
+func Finalized() (uint64, bool) {
+	if defaultTracker == nil {
+		return 0, false
+	}
+
+	return defaultTracker.Finalized()
+}
+
+// Resolve resolves the `safe` or `finalized` block tag by the default tracker, or returns false
+// if not tracked, in which case the block tag should be resolved by fullnode instead.
+func Resolve(tag string) (uint64, bool) {
+	if defaultTracker == nil {
+		return 0, false
+	}
+
+	return defaultTracker.Resolve(tag)
+}
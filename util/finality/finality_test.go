@@ -0,0 +1,52 @@
+package finality
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeHead(t *testing.T) {
+	testCases := []struct {
+		result   string
+		expected uint64
+		err      bool
+	}{
+		{`null`, 0, false},
+		{`"0x10"`, 16, false},
+		{`{"number":"0x10","hash":"0xabcd"}`, 16, false},
+		{`{"hash":"0xabcd"}`, 0, true},
+		{`"safe"`, 0, true},
+	}
+
+	for _, tc := range testCases {
+		head, err := decodeHead(json.RawMessage(tc.result))
+		assert.Equal(t, tc.err, err != nil, tc.result)
+		assert.Equal(t, tc.expected, head, tc.result)
+	}
+}
+
+func TestTrackerResolve(t *testing.T) {
+	tracker := NewTracker(&config{}, nil)
+
+	_, ok := tracker.Resolve(TagSafe)
+	assert.False(t, ok)
+
+	advance(&tracker.safe, 16)
+	advance(&tracker.finalized, 8)
+
+	// never goes backwards
+	advance(&tracker.safe, 12)
+
+	head, ok := tracker.Resolve(TagSafe)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(16), head)
+
+	head, ok = tracker.Resolve(TagFinalized)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(8), head)
+
+	_, ok = tracker.Resolve("latest")
+	assert.False(t, ok)
+}
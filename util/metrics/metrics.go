@@ -91,6 +91,26 @@ func (*RpcMetrics) StoreHit(method, storeName string) Percentage {
 	return GetOrRegisterTimeWindowPercentageDefault("infura/rpc/store/hit/%v/%v", storeName, method)
 }
 
+// RPC metrics - response cache hit ratio
+
+func (*RpcMetrics) CacheHit(method, layer string) Percentage {
+	return GetOrRegisterTimeWindowPercentageDefault("infura/rpc/cache/hit/%v/%v", layer, method)
+}
+
+// RPC metrics - transaction
+
+// TxPrecheckRejected returns the meter of raw transactions rejected by gateway precheck for the
+// specified reason.
+func (*RpcMetrics) TxPrecheckRejected(space, reason string) metrics.Meter {
+	return GetOrRegisterMeter("infura/rpc/tx/%v/rejected/%v", space, reason)
+}
+
+// TxRelay returns the meter of raw transactions relayed to node pool, where result is either
+// `queued` or `dropped` if relay queue is full.
+func (*RpcMetrics) TxRelay(space, result string) metrics.Meter {
+	return GetOrRegisterMeter("infura/rpc/tx/%v/relay/%v", space, result)
+}
+
 // RPC metrics - fullnode
 
 func (*RpcMetrics) FullnodeQps(space, method string, err error) metrics.Timer {
@@ -101,6 +121,44 @@ func (*RpcMetrics) FullnodeQps(space, method string, err error) metrics.Timer {
 	return GetOrRegisterTimer("infura/rpc/fullnode/%v/%v/failure", space, method)
 }
 
+// FullnodeCoalesced returns the percentage of fullnode calls that deduplicated by in-flight
+// identical calls.
+func (*RpcMetrics) FullnodeCoalesced(space string, method ...string) Percentage {
+	if len(method) == 0 {
+		return GetOrRegisterTimeWindowPercentageDefault("infura/rpc/fullnode/%v/coalesced", space)
+	}
+
+	return GetOrRegisterTimeWindowPercentageDefault("infura/rpc/fullnode/%v/coalesced/%v", space, method[0])
+}
+
+// FullnodeRetry returns the meter of upstream calls retried on other fullnodes due to the
+// transport error of the specified fullnode.
+func (*RpcMetrics) FullnodeRetry(space, node string) metrics.Meter {
+	return GetOrRegisterMeter("infura/rpc/fullnode/%v/retry/%v", space, node)
+}
+
+// FullnodeHedged returns the percentage of upstream calls hedged to another fullnode.
+func (*RpcMetrics) FullnodeHedged(space, method string) Percentage {
+	return GetOrRegisterTimeWindowPercentageDefault("infura/rpc/fullnode/%v/hedged/%v", space, method)
+}
+
+// FullnodeHedgeWon returns the percentage of hedged upstream calls that the hedged fullnode
+// responded first.
+func (*RpcMetrics) FullnodeHedgeWon(space, method string) Percentage {
+	return GetOrRegisterTimeWindowPercentageDefault("infura/rpc/fullnode/%v/hedgeWon/%v", space, method)
+}
+
+// FullnodeBreakerState returns the gauge of circuit breaker state of the specified fullnode,
+// where 0 means closed, 1 open and 2 half-open.
+func (*RpcMetrics) FullnodeBreakerState(space, node string) metrics.Gauge {
+	return GetOrRegisterGauge("infura/rpc/fullnode/%v/breaker/%v", space, node)
+}
+
+// FullnodeBreakerOpened returns the meter of circuit opened for the specified fullnode.
+func (*RpcMetrics) FullnodeBreakerOpened(space, node string) metrics.Meter {
+	return GetOrRegisterMeter("infura/rpc/fullnode/%v/breakerOpened/%v", space, node)
+}
+
 func (*RpcMetrics) FullnodeErrorRate(node ...string) Percentage {
 	if len(node) == 0 {
 		return GetOrRegisterTimeWindowPercentageDefault("infura/rpc/fullnode/rate/error")
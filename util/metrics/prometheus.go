@@ -0,0 +1,325 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// prometheusLabelPatterns are used to split labels out of the path-style metric names, where
+// segments in braces are labels and the others are joined as metric name. Note, the trailing
+// label matches all the remaining segments (e.g. node name with URL path), and patterns are
+// matched in order.
+var prometheusLabelPatterns = mustParsePrometheusPatterns(
+	// rpc
+	"infura/rpc/duration/{method}",
+	"infura/rpc/rate/{result}/{method}",
+	"infura/rpc/input/epoch/gap/{method}",
+	"infura/rpc/input/epoch/{method}/{epoch}",
+	"infura/rpc/input/block/gap/{method}",
+	"infura/rpc/input/block/{method}/{block}",
+	"infura/rpc/percentage/{method}/{name}",
+	"infura/rpc/store/hit/{store}/{method}",
+	"infura/rpc/cache/hit/{layer}/{method}",
+	"infura/rpc/tx/{space}/rejected/{reason}",
+	"infura/rpc/tx/{space}/relay/{result}",
+	"infura/rpc/fullnode/rate/error/{node}",
+	"infura/rpc/fullnode/rate/nonRpcErr/{node}",
+	"infura/rpc/fullnode/{space}/coalesced/{method}",
+	"infura/rpc/fullnode/{space}/retry/{node}",
+	"infura/rpc/fullnode/{space}/hedged/{method}",
+	"infura/rpc/fullnode/{space}/hedgeWon/{method}",
+	"infura/rpc/fullnode/{space}/breaker/{node}",
+	"infura/rpc/fullnode/{space}/breakerOpened/{node}",
+	"infura/rpc/fullnode/{space}/coalesced",
+	"infura/rpc/fullnode/{space}/{method}/{result}",
+	// sync
+	"infura/sync/{space}/{store}/once/size",
+	"infura/sync/{space}/{store}/once/{result}",
+	"infura/sync/{space}/fullnode/availability",
+	"infura/sync/{space}/fullnode",
+	// store
+	"infura/store/{store}/{operation}",
+	// node manager
+	"infura/nodes/{space}/routes/{group}/{node}",
+	"infura/nodes/{space}/latency/{group}/{node}",
+	"infura/nodes/{space}/availability/{group}/{node}",
+	// pubsub
+	"infura/pubsub/{space}/sessions/{topic}/{node}",
+	"infura/pubsub/{space}/input/logFilter",
+)
+
+var (
+	prometheusInvalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+	prometheusQuantiles        = []float64{0.5, 0.75, 0.95, 0.99, 0.999}
+	prometheusLabelEscaper     = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+type prometheusPattern struct {
+	name     string   // metric name joined by literal segments
+	segments []string // label name if in braces, otherwise literal
+	isLabel  []bool
+}
+
+func mustParsePrometheusPatterns(patterns ...string) []prometheusPattern {
+	result := make([]prometheusPattern, 0, len(patterns))
+
+	for _, p := range patterns {
+		var pattern prometheusPattern
+		var literals []string
+
+		for _, seg := range strings.Split(p, "/") {
+			isLabel := strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+			if isLabel {
+				seg = strings.TrimSuffix(strings.TrimPrefix(seg, "{"), "}")
+			} else {
+				literals = append(literals, seg)
+			}
+
+			pattern.segments = append(pattern.segments, seg)
+			pattern.isLabel = append(pattern.isLabel, isLabel)
+		}
+
+		pattern.name = sanitizePrometheusName(strings.Join(literals, "_"))
+		result = append(result, pattern)
+	}
+
+	return result
+}
+
+// match returns the labels if the specified path-style metric name matches the pattern.
+func (p *prometheusPattern) match(segments []string) (map[string]string, bool) {
+	n := len(p.segments)
+	if len(segments) < n || (len(segments) > n && !p.isLabel[n-1]) {
+		return nil, false
+	}
+
+	labels := make(map[string]string)
+
+	for i, seg := range p.segments {
+		if !p.isLabel[i] {
+			if segments[i] != seg {
+				return nil, false
+			}
+
+			continue
+		}
+
+		if i == n-1 { // trailing label matches all the remaining segments
+			labels[seg] = strings.Join(segments[i:], "/")
+		} else {
+			labels[seg] = segments[i]
+		}
+	}
+
+	return labels, true
+}
+
+// parsePrometheusName converts the path-style metric name into prometheus metric name and labels.
+func parsePrometheusName(name string) (string, map[string]string) {
+	segments := strings.Split(name, "/")
+
+	for i := range prometheusLabelPatterns {
+		if labels, ok := prometheusLabelPatterns[i].match(segments); ok {
+			return prometheusLabelPatterns[i].name, labels
+		}
+	}
+
+	return sanitizePrometheusName(name), nil
+}
+
+func sanitizePrometheusName(name string) string {
+	return prometheusInvalidNameChars.ReplaceAllString(name, "_")
+}
+
+type prometheusSample struct {
+	suffix string // e.g. `_sum` or `_count`
+	labels map[string]string
+	value  float64
+}
+
+type prometheusFamily struct {
+	name    string
+	typ     string // counter, gauge or summary
+	samples []prometheusSample
+}
+
+type prometheusCollector struct {
+	families map[string]*prometheusFamily
+}
+
+func (c *prometheusCollector) add(name, typ, suffix string, labels map[string]string, value float64) {
+	family, ok := c.families[name]
+	if !ok {
+		family = &prometheusFamily{name: name, typ: typ}
+		c.families[name] = family
+	}
+
+	family.samples = append(family.samples, prometheusSample{suffix, labels, value})
+}
+
+// addSummary adds quantiles along with sum and count, e.g. for histogram or timer.
+func (c *prometheusCollector) addSummary(
+	name string, labels map[string]string, count int64, sum float64, quantiles []float64, scale float64,
+) {
+	for i, q := range prometheusQuantiles {
+		c.add(name, "summary", "", withPrometheusLabel(labels, "quantile", fmt.Sprint(q)), quantiles[i]/scale)
+	}
+
+	c.add(name, "summary", "_sum", labels, sum/scale)
+	c.add(name, "summary", "_count", labels, float64(count))
+}
+
+func (c *prometheusCollector) collect(name string, metric interface{}) {
+	promName, labels := parsePrometheusName(name)
+
+	switch m := metric.(type) {
+	case Percentage: // must be ahead of GaugeFloat64, which is also implemented by Percentage
+		c.add(promName+"_ratio", "gauge", "", labels, m.Value()/100)
+	case metrics.Counter:
+		c.add(promName, "gauge", "", labels, float64(m.Count()))
+	case metrics.Gauge:
+		c.add(promName, "gauge", "", labels, float64(m.Value()))
+	case metrics.GaugeFloat64:
+		c.add(promName, "gauge", "", labels, m.Value())
+	case metrics.Meter:
+		ms := m.Snapshot()
+		c.add(promName+"_total", "counter", "", labels, float64(ms.Count()))
+		c.add(promName+"_rate1m", "gauge", "", labels, ms.Rate1())
+	case metrics.Histogram:
+		hs := m.Snapshot()
+		c.addSummary(promName, labels, hs.Count(), float64(hs.Sum()), hs.Percentiles(prometheusQuantiles), 1)
+	case metrics.Timer: // in seconds
+		ts := m.Snapshot()
+		c.addSummary(
+			promName+"_seconds", labels, ts.Count(), float64(ts.Sum()),
+			ts.Percentiles(prometheusQuantiles), float64(time.Second),
+		)
+	}
+}
+
+// write writes all the metric families in prometheus text format sorted by name.
+func (c *prometheusCollector) write(w io.Writer) error {
+	names := make([]string, 0, len(c.families))
+	for name := range c.families {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+
+	for _, name := range names {
+		family := c.families[name]
+		fmt.Fprintf(bw, "# TYPE %v %v\n", family.name, family.typ)
+
+		for _, s := range family.samples {
+			fmt.Fprintf(bw, "%v%v%v %v\n", family.name, s.suffix, formatPrometheusLabels(s.labels), s.value)
+		}
+	}
+
+	return bw.Flush()
+}
+
+func withPrometheusLabel(labels map[string]string, key, value string) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+
+	result[key] = value
+
+	return result
+}
+
+func formatPrometheusLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf(`%v="%v"`, sanitizePrometheusName(k), prometheusLabelEscaper.Replace(labels[k])))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// WritePrometheus writes all the metrics of the specified registry in prometheus text format.
+func WritePrometheus(w io.Writer, registry metrics.Registry) error {
+	collector := prometheusCollector{families: make(map[string]*prometheusFamily)}
+
+	registry.Each(collector.collect)
+
+	return collector.write(w)
+}
+
+// PrometheusHandler returns a HTTP handler to serve metrics for prometheus to scrape.
+func PrometheusHandler(registry metrics.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		if err := WritePrometheus(w, registry); err != nil {
+			logrus.WithError(err).Debug("Failed to write prometheus metrics")
+		}
+	})
+}
+
+// MustServePrometheusGraceful serves `/metrics` HTTP endpoint for prometheus if enabled until
+// graceful shutdown.
+func MustServePrometheusGraceful(ctx context.Context, wg *sync.WaitGroup) {
+	var config struct {
+		Enabled  bool
+		Endpoint string `default:":9464"`
+	}
+
+	viper.MustUnmarshalKey("metrics.prometheus", &config)
+
+	if !config.Enabled {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", PrometheusHandler(InfuraRegistry))
+	server := http.Server{Handler: mux}
+
+	listener, err := net.Listen("tcp", config.Endpoint)
+	if err != nil {
+		logrus.WithError(err).WithField("endpoint", config.Endpoint).Fatal("Failed to listen to prometheus endpoint")
+	}
+
+	wg.Add(1)
+	defer wg.Done()
+
+	go server.Serve(listener)
+
+	logrus.WithField("endpoint", config.Endpoint).Info("Prometheus metrics server started")
+
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logrus.WithError(err).Error("Failed to shutdown prometheus metrics server")
+	}
+}
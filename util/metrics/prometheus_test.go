@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrometheusName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+		labels   map[string]string
+	}{
+		{"infura/rpc/duration/eth_call", "infura_rpc_duration", map[string]string{"method": "eth_call"}},
+		{"infura/rpc/rate/success", "infura_rpc_rate_success", nil},
+		{
+			"infura/rpc/fullnode/rate/error/127.0.0.1:8545/rpc", "infura_rpc_fullnode_rate_error",
+			map[string]string{"node": "127.0.0.1:8545/rpc"},
+		},
+		{
+			"infura/sync/eth/db/once/size", "infura_sync_once_size",
+			map[string]string{"space": "eth", "store": "db"},
+		},
+		{"rpc/requests", "rpc_requests", nil},
+	}
+
+	for _, tc := range testCases {
+		name, labels := parsePrometheusName(tc.name)
+		assert.Equal(t, tc.expected, name, tc.name)
+		assert.Equal(t, tc.labels, labels, tc.name)
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	gauge := &metrics.StandardGauge{}
+	gauge.Update(3)
+	registry.Register("infura/pubsub/eth/sessions/logs/node1", gauge)
+	registry.Register("infura/rpc/store/hit/store/eth_getLogs", &standardPercentage{
+		data: percentageData{total: 4, marks: 1},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePrometheus(&buf, registry))
+
+	expected := strings.Join([]string{
+		"# TYPE infura_pubsub_sessions gauge",
+		`infura_pubsub_sessions{node="node1",space="eth",topic="logs"} 3`,
+		"# TYPE infura_rpc_store_hit_ratio gauge",
+		`infura_rpc_store_hit_ratio{method="eth_getLogs",store="store"} 0.25`,
+		"",
+	}, "\n")
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,72 @@
+package pubsub
+
+import (
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// topic to dispatch evm space event logs synchronized or reverted
+	TopicEthLogs = "eth:logs"
+	// topic to dispatch evm space chain reorg, see `ReorgMessage`
+	TopicEthReorg = "eth:reorg"
+
+	// channel size to buffer messages per subscription
+	subscriptionBufferSize = 1000
+)
+
+// Broker dispatches messages published on some topic to all the subscribers of the topic.
+type Broker interface {
+	// Publish publishes message on the specified topic.
+	Publish(topic string, msg []byte) error
+	// Subscribe subscribes messages published on the specified topic.
+	Subscribe(topic string) Subscription
+}
+
+// Subscription represents a message stream of some topic.
+type Subscription interface {
+	// Chan returns the channel to receive messages.
+	Chan() <-chan []byte
+	// Unsubscribe stops receiving messages, and the message channel will be closed.
+	Unsubscribe()
+}
+
+// ReorgMessage is published when chain data reverted due to chain reorg.
+type ReorgMessage struct {
+	// block number since which chain data reverted
+	RevertTo uint64 `json:"revertTo"`
+	// reorg version of store after chain data reverted
+	ReorgVersion int `json:"reorgVersion"`
+}
+
+type brokerConfig struct {
+	// whether to enable pub/sub broker
+	Enabled bool
+	// redis used to dispatch messages across processes, otherwise in-process
+	// memory is used.
+	RedisUrl string
+}
+
+// MustNewBrokerFromViper creates a broker from viper configurations, and returns false
+// if the broker is not enabled.
+func MustNewBrokerFromViper() (Broker, bool) {
+	var conf brokerConfig
+	viper.MustUnmarshalKey("pubsub", &conf)
+
+	if !conf.Enabled {
+		return nil, false
+	}
+
+	if len(conf.RedisUrl) == 0 {
+		return NewMemoryBroker(), true
+	}
+
+	// redis://<user>:<password>@<host>:<port>/<db_number>
+	opt, err := redis.ParseURL(conf.RedisUrl)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to parse redis URL for pub/sub broker")
+	}
+
+	return NewRedisBroker(redis.NewClient(opt)), true
+}
@@ -0,0 +1,93 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MemoryBroker in-process memory broker, which requires both the publisher and subscriber
+// to be within the same process.
+type MemoryBroker struct {
+	mu sync.Mutex
+	// topic => subscription set
+	topicSubs map[string]map[*memorySubscription]struct{}
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		topicSubs: make(map[string]map[*memorySubscription]struct{}),
+	}
+}
+
+// Publish implements the Broker interface. Note messages will be dropped for the subscriber
+// that is too slow to consume messages.
+func (b *MemoryBroker) Publish(topic string, msg []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.topicSubs[topic] {
+		select {
+		case sub.ch <- msg:
+		default:
+			logrus.WithField("topic", topic).Warn("Pub/sub message dropped due to subscription channel full")
+		}
+	}
+
+	return nil
+}
+
+// Subscribe implements the Broker interface.
+func (b *MemoryBroker) Subscribe(topic string) Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &memorySubscription{
+		broker: b,
+		topic:  topic,
+		ch:     make(chan []byte, subscriptionBufferSize),
+	}
+
+	if _, ok := b.topicSubs[topic]; !ok {
+		b.topicSubs[topic] = make(map[*memorySubscription]struct{})
+	}
+
+	b.topicSubs[topic][sub] = struct{}{}
+
+	return sub
+}
+
+func (b *MemoryBroker) unsubscribe(sub *memorySubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs, ok := b.topicSubs[sub.topic]
+	if !ok {
+		return
+	}
+
+	if _, ok := subs[sub]; !ok { // already unsubscribed
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.ch)
+
+	if len(subs) == 0 {
+		delete(b.topicSubs, sub.topic)
+	}
+}
+
+type memorySubscription struct {
+	broker *MemoryBroker
+	topic  string
+	ch     chan []byte
+}
+
+func (sub *memorySubscription) Chan() <-chan []byte {
+	return sub.ch
+}
+
+func (sub *memorySubscription) Unsubscribe() {
+	sub.broker.unsubscribe(sub)
+}
@@ -0,0 +1,32 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBroker(t *testing.T) {
+	broker := NewMemoryBroker()
+
+	sub1 := broker.Subscribe(TopicEthLogs)
+	sub2 := broker.Subscribe(TopicEthLogs)
+
+	assert.NoError(t, broker.Publish(TopicEthLogs, []byte("hello")))
+	assert.NoError(t, broker.Publish("others", []byte("world")))
+
+	assert.Equal(t, []byte("hello"), <-sub1.Chan())
+	assert.Equal(t, []byte("hello"), <-sub2.Chan())
+
+	// no more messages for other topics
+	assert.Len(t, sub1.Chan(), 0)
+
+	sub1.Unsubscribe()
+	sub1.Unsubscribe() // idempotent
+
+	_, ok := <-sub1.Chan()
+	assert.False(t, ok)
+
+	assert.NoError(t, broker.Publish(TopicEthLogs, []byte("again")))
+	assert.Equal(t, []byte("again"), <-sub2.Chan())
+}
@@ -0,0 +1,78 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisBroker broker based on Redis pub/sub, which could dispatch messages across processes.
+type RedisBroker struct {
+	rdb *redis.Client
+}
+
+func NewRedisBroker(rdb *redis.Client) *RedisBroker {
+	return &RedisBroker{rdb: rdb}
+}
+
+// Publish implements the Broker interface.
+func (b *RedisBroker) Publish(topic string, msg []byte) error {
+	return b.rdb.Publish(context.Background(), topic, msg).Err()
+}
+
+// Subscribe implements the Broker interface.
+func (b *RedisBroker) Subscribe(topic string) Subscription {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub := &redisSubscription{
+		ps:     b.rdb.Subscribe(ctx, topic),
+		ch:     make(chan []byte, subscriptionBufferSize),
+		cancel: cancel,
+	}
+
+	go sub.forward(ctx)
+
+	return sub
+}
+
+type redisSubscription struct {
+	ps     *redis.PubSub
+	ch     chan []byte
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// forward forwards redis messages to the subscription channel until unsubscribed.
+func (sub *redisSubscription) forward(ctx context.Context) {
+	defer close(sub.ch)
+
+	msgCh := sub.ps.Channel()
+	for {
+		select {
+		case msg, ok := <-msgCh:
+			if !ok {
+				return
+			}
+
+			select {
+			case sub.ch <- []byte(msg.Payload):
+			case <-ctx.Done():
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (sub *redisSubscription) Chan() <-chan []byte {
+	return sub.ch
+}
+
+func (sub *redisSubscription) Unsubscribe() {
+	sub.once.Do(func() {
+		sub.cancel()
+		sub.ps.Close()
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Conflux-Chain/go-conflux-util/viper"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/rpc-gateway/util"
+	"github.com/scroll-tech/rpc-gateway/util/metrics"
 	"github.com/sirupsen/logrus"
 )
 
@@ -19,10 +20,33 @@ type TxnRelayerConfig struct {
 	NodeUrls       []string
 }
 
+// RawTxSender sends raw transaction to fullnode of node pool.
+type RawTxSender interface {
+	// SendRawTx sends the signed transaction, and returns the transaction hash.
+	SendRawTx(signedTx hexutil.Bytes) (string, error)
+	// NodeURL returns the URL of fullnode.
+	NodeURL() string
+}
+
+// cfxTxSender sends core space raw transaction.
+type cfxTxSender struct {
+	*sdk.Client
+}
+
+func (s cfxTxSender) SendRawTx(signedTx hexutil.Bytes) (string, error) {
+	txHash, err := s.SendRawTransaction(signedTx)
+	return txHash.String(), err
+}
+
+func (s cfxTxSender) NodeURL() string {
+	return s.GetNodeURL()
+}
+
 // TxnRelayer relays raw transaction by broadcasting to node pool
 // of different regions to accelerate P2P diffusion.
 type TxnRelayer struct {
-	poolClients []*sdk.Client      // fullnode pool
+	space       string             // `cfx` or `eth`
+	poolClients []RawTxSender      // fullnode pool
 	txnQueue    chan hexutil.Bytes // transactions queued to relay
 	config      *TxnRelayerConfig
 }
@@ -43,11 +67,7 @@ func NewTxnRelayerFromViper() (*TxnRelayer, error) {
 }
 
 func NewTxnRelayer(relayConf *TxnRelayerConfig) (*TxnRelayer, error) {
-	if len(relayConf.NodeUrls) == 0 {
-		return &TxnRelayer{}, nil
-	}
-
-	var cfxClients []*sdk.Client
+	var cfxClients []RawTxSender
 
 	for _, url := range relayConf.NodeUrls {
 		cfx, err := sdk.NewClient(url, sdk.ClientOption{
@@ -59,11 +79,22 @@ func NewTxnRelayer(relayConf *TxnRelayerConfig) (*TxnRelayer, error) {
 			return nil, err
 		}
 
-		cfxClients = append(cfxClients, cfx)
+		cfxClients = append(cfxClients, cfxTxSender{cfx})
+	}
+
+	return newTxnRelayer("cfx", cfxClients, relayConf), nil
+}
+
+// newTxnRelayer creates relayer to broadcast raw transactions of the specified space to the
+// node pool, and starts concurrency worker(s) if node pool not empty.
+func newTxnRelayer(space string, poolClients []RawTxSender, relayConf *TxnRelayerConfig) *TxnRelayer {
+	if len(poolClients) == 0 {
+		return &TxnRelayer{space: space}
 	}
 
 	relayer := &TxnRelayer{
-		poolClients: cfxClients,
+		space:       space,
+		poolClients: poolClients,
 		txnQueue:    make(chan hexutil.Bytes, relayConf.BufferSize),
 		config:      relayConf,
 	}
@@ -78,7 +109,7 @@ func NewTxnRelayer(relayConf *TxnRelayerConfig) (*TxnRelayer, error) {
 		}()
 	}
 
-	return relayer, nil
+	return relayer
 }
 
 // AsyncRelay relays raw transaction broadcasting asynchronously.
@@ -87,20 +118,22 @@ func (relayer *TxnRelayer) AsyncRelay(signedTx hexutil.Bytes) bool {
 		return true
 	}
 
-	if len(relayer.txnQueue) == relayer.config.BufferSize { // queue is full?
+	select {
+	case relayer.txnQueue <- signedTx:
+		metrics.Registry.RPC.TxRelay(relayer.space, "queued").Mark(1)
+		return true
+	default: // queue is full
+		metrics.Registry.RPC.TxRelay(relayer.space, "dropped").Mark(1)
 		return false
 	}
-
-	relayer.txnQueue <- signedTx
-	return true
 }
 
 func (relayer *TxnRelayer) doRelay(signedTx hexutil.Bytes) {
 	for _, client := range relayer.poolClients {
-		txHash, err := client.SendRawTransaction(signedTx)
+		txHash, err := client.SendRawTx(signedTx)
 
 		logrus.WithFields(logrus.Fields{
-			"nodeUrl": client.GetNodeURL(), "txHash": txHash,
+			"nodeUrl": client.NodeURL(), "txHash": txHash,
 		}).WithError(err).Trace("Raw transaction relayed")
 	}
 }
@@ -0,0 +1,60 @@
+package relay
+
+import (
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3/web3go"
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+// ethTxSender sends evm space raw transaction.
+type ethTxSender struct {
+	*web3go.Client
+	url string
+}
+
+func (s ethTxSender) SendRawTx(signedTx hexutil.Bytes) (string, error) {
+	txHash, err := s.Eth.SendRawTransaction(signedTx)
+	return txHash.Hex(), err
+}
+
+func (s ethTxSender) NodeURL() string {
+	return s.url
+}
+
+func MustNewEthTxnRelayerFromViper() *TxnRelayer {
+	relayer, err := NewEthTxnRelayerFromViper()
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to new evm space transaction relayer from viper")
+	}
+
+	return relayer
+}
+
+func NewEthTxnRelayerFromViper() (*TxnRelayer, error) {
+	var relayConf TxnRelayerConfig
+	viper.MustUnmarshalKey("ethrelay", &relayConf)
+	return NewEthTxnRelayer(&relayConf)
+}
+
+// NewEthTxnRelayer creates relayer to broadcast evm space raw transactions to node pool.
+func NewEthTxnRelayer(relayConf *TxnRelayerConfig) (*TxnRelayer, error) {
+	var ethClients []RawTxSender
+
+	for _, url := range relayConf.NodeUrls {
+		eth, err := rpc.NewEthClient(
+			url,
+			rpc.WithClientRetryCount(relayConf.Retry),
+			rpc.WithClientRetryInterval(relayConf.RetryInterval),
+			rpc.WithClientRequestTimeout(relayConf.RequestTimeout),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		ethClients = append(ethClients, ethTxSender{eth, url})
+	}
+
+	return newTxnRelayer("eth", ethClients, relayConf), nil
+}
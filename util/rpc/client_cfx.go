@@ -61,7 +61,7 @@ func NewCfxClient(url string, options ...ClientOption) (*sdk.Client, error) {
 
 	cfx, err := sdk.NewClient(url, *opt.ClientOption)
 	if err == nil && opt.hookMetrics {
-		HookMiddlewares(cfx.Provider(), url, "cfx")
+		HookMiddlewares(cfx.Provider(), url, "cfx", cfxClientCfg.Coalesce)
 	}
 
 	return cfx, err
@@ -60,7 +60,7 @@ func NewEthClient(url string, options ...ClientOption) (*web3go.Client, error) {
 
 	eth, err := web3go.NewClientWithOption(url, opt.ClientOption)
 	if err == nil && opt.hookMetrics {
-		HookMiddlewares(eth.Provider(), url, "eth")
+		HookMiddlewares(eth.Provider(), url, "eth", ethClientCfg.Coalesce)
 	}
 
 	return eth, err
@@ -2,15 +2,31 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"strings"
 	"time"
 
 	providers "github.com/openweb3/go-rpc-provider/provider_wrapper"
 	"github.com/openweb3/go-rpc-provider/utils"
 	"github.com/scroll-tech/rpc-gateway/util/metrics"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
 )
 
+// non-idempotent or stateful RPC methods (without namespace) that must not be coalesced
+var uncoalescableMethods = map[string]bool{
+	"sendRawTransaction":          true,
+	"sendTransaction":             true,
+	"newFilter":                   true,
+	"newBlockFilter":              true,
+	"newPendingTransactionFilter": true,
+	"getFilterChanges":            true,
+	"uninstallFilter":             true,
+}
+
 func Url2NodeName(url string) string {
 	nodeName := strings.ToLower(url)
 	nodeName = strings.TrimPrefix(nodeName, "http://")
@@ -20,12 +36,39 @@ func Url2NodeName(url string) string {
 	return strings.TrimPrefix(nodeName, "/")
 }
 
-func HookMiddlewares(provider *providers.MiddlewarableProvider, url, space string) {
+func HookMiddlewares(provider *providers.MiddlewarableProvider, url, space string, coalesce ...bool) {
 	nodeName := Url2NodeName(url)
+	if len(coalesce) > 0 && coalesce[0] {
+		// coalesce at first so that deduplicated calls won't be requested to fullnode
+		provider.HookCallContext(middlewareCoalesce(space))
+	}
+	provider.HookCallContext(middlewareTracing(nodeName, space))
 	provider.HookCallContext(middlewareLog(nodeName, space))
 	provider.HookCallContext(middlewareMetrics(nodeName, space))
 }
 
+func middlewareTracing(fullnode, space string) providers.CallContextMiddleware {
+	return func(handler providers.CallContextFunc) providers.CallContextFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			ctx, span := tracing.Tracer().Start(
+				ctx, "fullnode "+method,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					tracing.AttrRpcMethod.String(method),
+					tracing.AttrRpcSpace.String(space),
+					tracing.AttrNodeName.String(fullnode),
+				),
+			)
+
+			err := handler(ctx, result, method, args...)
+
+			tracing.End(span, err)
+
+			return err
+		}
+	}
+}
+
 func middlewareMetrics(fullnode, space string) providers.CallContextMiddleware {
 	return func(handler providers.CallContextFunc) providers.CallContextFunc {
 		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
@@ -79,3 +122,69 @@ func middlewareLog(fullnode, space string) providers.CallContextMiddleware {
 		}
 	}
 }
+
+// middlewareCoalesce collapses identical in-flight RPC calls into one, and shares the result
+// with all the callers.
+func middlewareCoalesce(space string) providers.CallContextMiddleware {
+	var group singleflight.Group
+
+	return func(handler providers.CallContextFunc) providers.CallContextFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			if !isCoalescable(method) {
+				return handler(ctx, result, method, args...)
+			}
+
+			argsJson, err := json.Marshal(args)
+			if err != nil {
+				return handler(ctx, result, method, args...)
+			}
+
+			// the first caller of identical calls executes the RPC request
+			var executed bool
+			ch := group.DoChan(method+string(argsJson), func() (interface{}, error) {
+				executed = true
+
+				var raw json.RawMessage
+				err := handler(ctx, &raw, method, args...)
+				return raw, err
+			})
+
+			var res singleflight.Result
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case res = <-ch:
+			}
+
+			metrics.Registry.RPC.FullnodeCoalesced(space).Mark(!executed)
+			metrics.Registry.RPC.FullnodeCoalesced(space, method).Mark(!executed)
+
+			if res.Err != nil {
+				// request canceled by the first caller, e.g. client disconnected
+				if !executed && ctx.Err() == nil && isContextError(res.Err) {
+					return handler(ctx, result, method, args...)
+				}
+
+				return res.Err
+			}
+
+			if result == nil {
+				return nil
+			}
+
+			return json.Unmarshal(res.Val.(json.RawMessage), result)
+		}
+	}
+}
+
+func isCoalescable(method string) bool {
+	if idx := strings.Index(method, "_"); idx >= 0 {
+		method = method[idx+1:]
+	}
+
+	return !uncoalescableMethods[method]
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,51 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareCoalesce(t *testing.T) {
+	var numCalls int32
+	release := make(chan struct{})
+
+	handler := middlewareCoalesce("eth")(func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+		atomic.AddInt32(&numCalls, 1)
+		<-release
+
+		*(result.(*json.RawMessage)) = json.RawMessage(`"0x10"`)
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+
+	for i := range results {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, handler(context.Background(), &results[i], "eth_blockNumber"))
+		}(i)
+	}
+
+	// wait for all calls in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&numCalls))
+	for _, v := range results {
+		assert.Equal(t, "0x10", v)
+	}
+
+	// stateful method never coalesced
+	assert.False(t, isCoalescable("eth_getFilterChanges"))
+	assert.True(t, isCoalescable("eth_getBlockByNumber"))
+}
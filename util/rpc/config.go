@@ -18,6 +18,8 @@ type clientConfig struct {
 	RetryInterval   time.Duration `default:"1s"`
 	RequestTimeout  time.Duration `default:"3s"`
 	MaxConnsPerHost int           `default:"1024"`
+	// whether to collapse identical in-flight RPC calls into one
+	Coalesce bool
 }
 
 type ClientOptioner interface {
@@ -7,6 +7,8 @@ import (
 	web3pay "github.com/Conflux-Chain/web3pay-service/client"
 	"github.com/openweb3/go-rpc-provider"
 	"github.com/scroll-tech/rpc-gateway/util/rpc/handlers"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -36,16 +38,26 @@ func RateLimit(next rpc.HandleCallMsgFunc) rpc.HandleCallMsgFunc {
 			return next(ctx, msg)
 		}
 
-		// overall rate limit
-		if !handlers.RateLimitAllow(ctx, "rpc_all", 1) {
-			return msg.ErrorResponse(errRateLimit)
-		}
-
-		// single method rate limit
-		if !handlers.RateLimitAllow(ctx, msg.Method, 1) {
+		if !rateLimitAllow(ctx, msg.Method) {
 			return msg.ErrorResponse(errRateLimit)
 		}
 
 		return next(ctx, msg)
 	}
 }
+
+func rateLimitAllow(ctx context.Context, method string) (allowed bool) {
+	ctx, span := tracing.Start(ctx, "rateLimit")
+	defer func() {
+		span.SetAttributes(attribute.Bool("rateLimit.allowed", allowed))
+		span.End()
+	}()
+
+	// overall rate limit
+	if !handlers.RateLimitAllow(ctx, "rpc_all", 1) {
+		return false
+	}
+
+	// single method rate limit
+	return handlers.RateLimitAllow(ctx, method, 1)
+}
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/openweb3/go-rpc-provider"
+	"github.com/scroll-tech/rpc-gateway/util/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Tracing(next rpc.HandleCallMsgFunc) rpc.HandleCallMsgFunc {
+	return func(ctx context.Context, msg *rpc.JsonRpcMessage) *rpc.JsonRpcMessage {
+		ctx, span := tracing.Tracer().Start(
+			ctx, msg.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(tracing.AttrRpcMethod.String(msg.Method)),
+		)
+
+		resp := next(ctx, msg)
+
+		if resp != nil && resp.Error != nil {
+			tracing.End(span, resp.Error)
+		} else {
+			tracing.End(span, nil)
+		}
+
+		return resp
+	}
+}
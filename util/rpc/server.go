@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
 	"sync"
@@ -31,19 +32,30 @@ var (
 
 // Server serves JSON RPC services.
 type Server struct {
-	name    string
-	servers map[Protocol]*http.Server
+	name      string
+	servers   map[Protocol]*http.Server
+	tlsConfig *tls.Config
 }
 
-// MustNewServer creates an instance of Server with specified RPC services.
+// MustNewServer creates an instance of Server with specified RPC services, where multiple services
+// of the same namespace could be specified as a slice.
 func MustNewServer(name string, rpcs map[string]interface{}, middlewares ...handlers.Middleware) *Server {
 	handler := rpc.NewServer()
 	servedApis := make([]string, 0, len(rpcs))
 
 	for namespace, impl := range rpcs {
-		if err := handler.RegisterName(namespace, impl); err != nil {
-			logrus.WithError(err).WithField("namespace", namespace).Fatal("Failed to register rpc service")
+		// multiple services could be registered under the same namespace
+		impls, ok := impl.([]interface{})
+		if !ok {
+			impls = []interface{}{impl}
 		}
+
+		for _, impl := range impls {
+			if err := handler.RegisterName(namespace, impl); err != nil {
+				logrus.WithError(err).WithField("namespace", namespace).Fatal("Failed to register rpc service")
+			}
+		}
+
 		servedApis = append(servedApis, namespace)
 	}
 
@@ -77,6 +89,11 @@ func MustNewServer(name string, rpcs map[string]interface{}, middlewares ...hand
 	}
 }
 
+// SetTLSConfig sets the TLS configurations to serve HTTPS or secure websocket.
+func (s *Server) SetTLSConfig(config *tls.Config) {
+	s.tlsConfig = config
+}
+
 // MustServe serves RPC server in blocking way or panics if failed.
 func (s *Server) MustServe(endpoint string, protocol Protocol) {
 	logger := logrus.WithFields(logrus.Fields{
@@ -95,6 +112,10 @@ func (s *Server) MustServe(endpoint string, protocol Protocol) {
 		logger.WithError(err).Fatal("Failed to listen to endpoint")
 	}
 
+	if s.tlsConfig != nil {
+		listener = tls.NewListener(listener, s.tlsConfig)
+	}
+
 	logger.Info("JSON RPC server started")
 
 	server.Serve(listener)
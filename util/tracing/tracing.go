@@ -0,0 +1,188 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-util/viper"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/scroll-tech/rpc-gateway"
+
+	ExporterOtlp   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// span attribute keys
+const (
+	AttrRpcMethod = attribute.Key("rpc.method")
+	AttrRpcSpace  = attribute.Key("rpc.space")
+	AttrNodeName  = attribute.Key("rpc.node")
+	AttrStoreHit  = attribute.Key("rpc.store_hit")
+	AttrDbTable   = attribute.Key("db.sql.table")
+)
+
+type config struct {
+	// whether to enable tracing
+	Enabled bool
+	// service name reported along with spans
+	ServiceName string `default:"confura"`
+	// ratio of root spans to sample, e.g. 0.1 to sample 10% of requests
+	SampleRatio float64 `default:"1"`
+	// span exporter, `otlp` or `stdout`
+	Exporter string `default:"otlp"`
+	Otlp     struct {
+		// OTLP/HTTP collector endpoint in `host:port` format
+		Endpoint string `default:"127.0.0.1:4318"`
+		// URL path of the collector, `/v1/traces` by default
+		UrlPath string
+		// whether to disable TLS
+		Insecure bool
+	}
+	Stdout struct {
+		// file to write spans into, empty for stdout
+		File string
+		// whether to write spans in human readable format
+		PrettyPrint bool
+	}
+}
+
+var provider *sdktrace.TracerProvider
+
+// Init initializes the global tracer provider and W3C trace context propagator from
+// viper settings if enabled, otherwise spans are not recorded at all.
+func Init() {
+	var conf config
+	viper.MustUnmarshalKey("tracing", &conf)
+
+	if !conf.Enabled {
+		return
+	}
+
+	exporter, err := newExporter(&conf)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create tracing exporter")
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL, semconv.ServiceNameKey.String(conf.ServiceName),
+	))
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create tracing resource")
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(conf.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logrus.WithError(err).Debug("Tracing error occurred")
+	}))
+
+	logrus.WithField("config", conf).Info("Tracing initialized")
+}
+
+func newExporter(conf *config) (sdktrace.SpanExporter, error) {
+	switch strings.ToLower(conf.Exporter) {
+	case ExporterOtlp:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(conf.Otlp.Endpoint)}
+		if len(conf.Otlp.UrlPath) > 0 {
+			opts = append(opts, otlptracehttp.WithURLPath(conf.Otlp.UrlPath))
+		}
+
+		if conf.Otlp.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		return otlptracehttp.New(context.Background(), opts...)
+	case ExporterStdout:
+		var w io.Writer = os.Stdout
+		if len(conf.Stdout.File) > 0 {
+			f, err := os.OpenFile(conf.Stdout.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to open file")
+			}
+
+			w = f
+		}
+
+		opts := []stdouttrace.Option{stdouttrace.WithWriter(w)}
+		if conf.Stdout.PrettyPrint {
+			opts = append(opts, stdouttrace.WithPrettyPrint())
+		}
+
+		return stdouttrace.New(opts...)
+	default:
+		return nil, errors.Errorf("unsupported exporter %v", conf.Exporter)
+	}
+}
+
+// Shutdown flushes all the pending spans and stops the tracer provider if enabled.
+func Shutdown() {
+	if provider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := provider.Shutdown(ctx); err != nil {
+		logrus.WithError(err).Warn("Failed to shutdown tracer provider")
+	}
+}
+
+// Tracer returns the tracer of this service, which is no-op if tracing not enabled.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start creates a span and a context containing the newly-created span.
+func Start(
+	ctx context.Context, spanName string, attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, spanName, trace.WithAttributes(attrs...))
+}
+
+// End records the error if any and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// SetAttributes sets attributes on the span of the context if any.
+func SetAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// ExtractHTTPHeader extracts W3C trace context from the HTTP headers into the context, so that
+// spans created later will be children of the remote caller span.
+func ExtractHTTPHeader(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
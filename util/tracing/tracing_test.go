@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpanFromHTTPTraceContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx := ExtractHTTPHeader(context.Background(), header)
+	ctx, span := Start(ctx, "eth_getLogs", AttrRpcMethod.String("eth_getLogs"))
+	SetAttributes(ctx, AttrStoreHit.Bool(true))
+	End(span, errors.New("timeout"))
+
+	spans := recorder.Ended()
+	if assert.Equal(t, 1, len(spans)) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+		assert.Contains(t, spans[0].Attributes(), AttrStoreHit.Bool(true))
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+	}
+}